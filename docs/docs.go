@@ -66,6 +66,7 @@ const docTemplate = `{
                 }
             },
             "post": {
+                "description": "鉴权迁移说明：传入 sign 时使用签名模式，sign 为除 sign、sign_type 外全部非空参数（含 items、timestamp、nonce）的 MD5 签名，且必须携带 timestamp 与 nonce；\nAPI Key 开启强制防重放（require_nonce）后只接受签名模式。未开启时仍可沿用旧版方式以 key 传入 client_secret，旧版方式将逐步淘汰，建议尽快迁移到签名模式后开启强制防重放。",
                 "consumes": [
                     "application/json"
                 ],
@@ -96,7 +97,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/admin/system-configs": {
+        "/api/v1/admin/escrows": {
             "get": {
                 "produces": [
                     "application/json"
@@ -104,6 +105,43 @@ const docTemplate = `{
                 "tags": [
                     "admin"
                 ],
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "buyer",
+                            "seller"
+                        ],
+                        "type": "string",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "held",
+                            "shipped",
+                            "completed",
+                            "disputing",
+                            "refunded",
+                            "cancelled"
+                        ],
+                        "type": "string",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -112,7 +150,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/admin/escrows/{id}/arbitrate": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -124,13 +164,21 @@ const docTemplate = `{
                     "admin"
                 ],
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "担保交易 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "description": "request body",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/system_config.CreateSystemConfigRequest"
+                            "$ref": "#/definitions/escrow.ArbitrateEscrowRequest"
                         }
                     }
                 ],
@@ -144,21 +192,36 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/admin/system-configs/{key}": {
+        "/api/v1/admin/redeem-batches": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "admin"
+                    "redeem"
                 ],
                 "parameters": [
                     {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "active",
+                            "disabled"
+                        ],
                         "type": "string",
-                        "description": "配置键",
-                        "name": "key",
-                        "in": "path",
-                        "required": true
+                        "name": "status",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -170,7 +233,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -178,23 +241,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "admin"
+                    "redeem"
                 ],
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "配置键",
-                        "name": "key",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "description": "request body",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/system_config.UpdateSystemConfigRequest"
+                            "$ref": "#/definitions/redeem.CreateRedeemBatchRequest"
                         }
                     }
                 ],
@@ -206,19 +262,22 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/admin/redeem-batches/{batchId}/disable": {
+            "post": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "admin"
+                    "redeem"
                 ],
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "配置键",
-                        "name": "key",
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "批次 ID",
+                        "name": "batchId",
                         "in": "path",
                         "required": true
                     }
@@ -233,7 +292,63 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/admin/user-pay-configs": {
+        "/api/v1/admin/redeem-batches/{batchId}/export": {
+            "get": {
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "redeem"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "批次 ID",
+                        "name": "batchId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "CSV 文件",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/redeem-batches/{batchId}/stats": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "redeem"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "批次 ID",
+                        "name": "batchId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/redeem.RedeemBatchStatsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/system-configs": {
             "get": {
                 "produces": [
                     "application/json"
@@ -267,7 +382,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/user_pay_config.CreateUserPayConfigRequest"
+                            "$ref": "#/definitions/system_config.CreateSystemConfigRequest"
                         }
                     }
                 ],
@@ -281,7 +396,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/admin/user-pay-configs/{id}": {
+        "/api/v1/admin/system-configs/{key}": {
             "get": {
                 "produces": [
                     "application/json"
@@ -292,8 +407,8 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "配置ID",
-                        "name": "id",
+                        "description": "配置键",
+                        "name": "key",
                         "in": "path",
                         "required": true
                     }
@@ -320,8 +435,8 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "配置ID",
-                        "name": "id",
+                        "description": "配置键",
+                        "name": "key",
                         "in": "path",
                         "required": true
                     },
@@ -331,7 +446,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/user_pay_config.UpdateUserPayConfigRequest"
+                            "$ref": "#/definitions/system_config.UpdateSystemConfigRequest"
                         }
                     }
                 ],
@@ -354,8 +469,8 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "配置ID",
-                        "name": "id",
+                        "description": "配置键",
+                        "name": "key",
                         "in": "path",
                         "required": true
                     }
@@ -370,52 +485,13 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/config/public": {
-            "get": {
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "config"
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/util.ResponseAny"
-                        }
-                    }
-                }
-            }
-        },
-        "/api/v1/health": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "health"
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/util.ResponseAny"
-                        }
-                    }
-                }
-            }
-        },
-        "/api/v1/merchant/api-keys": {
+        "/api/v1/admin/user-pay-configs": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "merchant"
+                    "admin"
                 ],
                 "responses": {
                     "200": {
@@ -434,7 +510,7 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "merchant"
+                    "admin"
                 ],
                 "parameters": [
                     {
@@ -443,7 +519,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api_key.CreateAPIKeyRequest"
+                            "$ref": "#/definitions/user_pay_config.CreateUserPayConfigRequest"
                         }
                     }
                 ],
@@ -457,19 +533,18 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/merchant/api-keys/{id}": {
+        "/api/v1/admin/user-pay-configs/{id}": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "merchant"
+                    "admin"
                 ],
                 "parameters": [
                     {
-                        "type": "integer",
-                        "format": "int64",
-                        "description": "API Key ID",
+                        "type": "string",
+                        "description": "配置ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -492,13 +567,12 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "merchant"
+                    "admin"
                 ],
                 "parameters": [
                     {
-                        "type": "integer",
-                        "format": "int64",
-                        "description": "API Key ID",
+                        "type": "string",
+                        "description": "配置ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -509,7 +583,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api_key.UpdateAPIKeyRequest"
+                            "$ref": "#/definitions/user_pay_config.UpdateUserPayConfigRequest"
                         }
                     }
                 ],
@@ -527,13 +601,12 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "merchant"
+                    "admin"
                 ],
                 "parameters": [
                     {
-                        "type": "integer",
-                        "format": "int64",
-                        "description": "API Key ID",
+                        "type": "string",
+                        "description": "配置ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -549,22 +622,70 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/merchant/api-keys/{id}/payment-links": {
+        "/api/v1/config/public": {
             "get": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "merchant"
+                    "config"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/escrow": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "escrow"
                 ],
                 "parameters": [
                     {
+                        "minimum": 1,
                         "type": "integer",
-                        "format": "int64",
-                        "description": "API Key ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "buyer",
+                            "seller"
+                        ],
+                        "type": "string",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "held",
+                            "shipped",
+                            "completed",
+                            "disputing",
+                            "refunded",
+                            "cancelled"
+                        ],
+                        "type": "string",
+                        "name": "status",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -584,25 +705,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "merchant"
+                    "escrow"
                 ],
                 "parameters": [
                     {
-                        "type": "integer",
-                        "format": "int64",
-                        "description": "API Key ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "创建支付链接请求",
+                        "description": "request body",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/link.CreatePaymentLinkRequest"
+                            "$ref": "#/definitions/escrow.CreateEscrowRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "幂等键，重复请求返回首次请求的响应",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -615,30 +734,22 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/merchant/api-keys/{id}/payment-links/{linkId}": {
-            "delete": {
+        "/api/v1/escrow/{id}": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "merchant"
+                    "escrow"
                 ],
                 "parameters": [
                     {
                         "type": "integer",
                         "format": "int64",
-                        "description": "API Key ID",
+                        "description": "担保交易 ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "format": "int64",
-                        "description": "Payment Link ID",
-                        "name": "linkId",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
@@ -651,26 +762,22 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/merchant/payment": {
+        "/api/v1/escrow/{id}/cancel": {
             "post": {
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "payment"
+                    "escrow"
                 ],
                 "parameters": [
                     {
-                        "description": "支付订单请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/payment.PayOrderRequest"
-                        }
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "担保交易 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -683,7 +790,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/merchant/payment-links/pay": {
+        "/api/v1/escrow/{id}/confirm": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -692,44 +799,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "merchant"
+                    "escrow"
                 ],
                 "parameters": [
                     {
-                        "description": "支付请求",
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "担保交易 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "request body",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/link.PayByLinkRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/util.ResponseAny"
+                            "$ref": "#/definitions/escrow.ConfirmEscrowRequest"
                         }
-                    }
-                }
-            }
-        },
-        "/api/v1/merchant/payment-links/{token}": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "merchant"
-                ],
-                "parameters": [
+                    },
                     {
                         "type": "string",
-                        "description": "支付链接 Token",
-                        "name": "token",
-                        "in": "path",
-                        "required": true
+                        "description": "幂等键，重复请求返回首次请求的响应",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -742,8 +836,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/merchant/payment/order": {
-            "get": {
+        "/api/v1/escrow/{id}/dispute": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -751,15 +845,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "payment"
+                    "escrow"
                 ],
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "订单号",
-                        "name": "order_no",
-                        "in": "query",
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "担保交易 ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/escrow.DisputeEscrowRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -772,22 +876,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/oauth/callback": {
+        "/api/v1/escrow/{id}/ship": {
             "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "oauth"
+                    "escrow"
                 ],
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "担保交易 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "description": "request body",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/oauth.CallbackRequest"
+                            "$ref": "#/definitions/escrow.ShipEscrowRequest"
                         }
                     }
                 ],
@@ -801,13 +916,13 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/oauth/login": {
+        "/api/v1/health": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "oauth"
+                    "health"
                 ],
                 "responses": {
                     "200": {
@@ -819,13 +934,13 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/oauth/logout": {
+        "/api/v1/merchant/api-keys": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "oauth"
+                    "merchant"
                 ],
                 "responses": {
                     "200": {
@@ -835,15 +950,27 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/oauth/user-info": {
-            "get": {
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "oauth"
+                    "merchant"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_key.CreateAPIKeyRequest"
+                        }
+                    }
                 ],
                 "responses": {
                     "200": {
@@ -855,26 +982,22 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/order/dispute": {
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/v1/merchant/api-keys/{id}": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "order"
+                    "merchant"
                 ],
                 "parameters": [
                     {
-                        "description": "request body",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dispute.CreateDisputeRequest"
-                        }
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -885,10 +1008,8 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/order/dispute/close": {
-            "post": {
+            },
+            "put": {
                 "consumes": [
                     "application/json"
                 ],
@@ -896,16 +1017,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "order"
+                    "merchant"
                 ],
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "description": "request body",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dispute.CloseDisputeRequest"
+                            "$ref": "#/definitions/api_key.UpdateAPIKeyRequest"
                         }
                     }
                 ],
@@ -917,27 +1046,22 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/order/disputes": {
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "order"
+                    "merchant"
                 ],
                 "parameters": [
                     {
-                        "description": "request body",
-                        "name": "request",
-                        "in": "body",
-                        "schema": {
-                            "$ref": "#/definitions/dispute.ListDisputesRequest"
-                        }
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -950,25 +1074,22 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/order/disputes/merchant": {
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/v1/merchant/api-keys/{id}/coupons": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "order"
+                    "merchant"
                 ],
                 "parameters": [
                     {
-                        "description": "request body",
-                        "name": "request",
-                        "in": "body",
-                        "schema": {
-                            "$ref": "#/definitions/dispute.ListDisputesRequest"
-                        }
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -979,9 +1100,7 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/order/refund-review": {
+            },
             "post": {
                 "consumes": [
                     "application/json"
@@ -990,16 +1109,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "order"
+                    "merchant"
                 ],
                 "parameters": [
                     {
-                        "description": "request body",
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "创建优惠券请求",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dispute.RefundReviewRequest"
+                            "$ref": "#/definitions/coupon.CreateCouponRequest"
                         }
                     }
                 ],
@@ -1013,8 +1140,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/order/transactions": {
-            "post": {
+        "/api/v1/merchant/api-keys/{id}/coupons/{couponId}": {
+            "put": {
                 "consumes": [
                     "application/json"
                 ],
@@ -1022,15 +1149,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "order"
+                    "merchant"
                 ],
                 "parameters": [
                     {
-                        "description": "request body",
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "优惠券 ID",
+                        "name": "couponId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "更新优惠券请求",
                         "name": "request",
                         "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/order.TransactionListRequest"
+                            "$ref": "#/definitions/coupon.UpdateCouponRequest"
                         }
                     }
                 ],
@@ -1042,9 +1186,118 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "优惠券 ID",
+                        "name": "couponId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
         },
-        "/api/v1/payment/transfer": {
+        "/api/v1/merchant/api-keys/{id}/coupons/{couponId}/redemptions": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "优惠券 ID",
+                        "name": "couponId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/coupon.ListCouponRedemptionsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/api-keys/{id}/payment-links": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            },
             "post": {
                 "consumes": [
                     "application/json"
@@ -1053,16 +1306,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "payment"
+                    "merchant"
                 ],
                 "parameters": [
                     {
-                        "description": "转账请求",
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "创建支付链接请求",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/payment.TransferRequest"
+                            "$ref": "#/definitions/link.CreatePaymentLinkRequest"
                         }
                     }
                 ],
@@ -1076,7 +1337,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/user/pay-key": {
+        "/api/v1/merchant/api-keys/{id}/payment-links/{linkId}": {
             "put": {
                 "consumes": [
                     "application/json"
@@ -1085,18 +1346,68 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "merchant"
                 ],
                 "parameters": [
                     {
-                        "description": "request body",
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Payment Link ID",
+                        "name": "linkId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "更新支付链接请求",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/user.UpdatePayKeyRequest"
+                            "$ref": "#/definitions/link.UpdatePaymentLinkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
                         }
                     }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Payment Link ID",
+                        "name": "linkId",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
                     "200": {
@@ -1108,25 +1419,102 @@ const docTemplate = `{
                 }
             }
         },
-        "/pay/submit.php": {
+        "/api/v1/merchant/api-keys/{id}/payment-links/{linkId}/stats": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Payment Link ID",
+                        "name": "linkId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 90,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/api-keys/{id}/products": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            },
             "post": {
                 "consumes": [
-                    "application/x-www-form-urlencoded"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "payment"
+                    "merchant"
                 ],
                 "parameters": [
                     {
-                        "description": "request body",
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "创建商品请求",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/payment.CreateOrderRequest"
+                            "$ref": "#/definitions/product.CreateProductRequest"
                         }
                     }
                 ],
@@ -1139,405 +1527,4978 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "api_key.CreateAPIKeyRequest": {
-            "type": "object",
+        },
+        "/api/v1/merchant/api-keys/{id}/products/{productId}": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "商品 ID",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "更新商品请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/product.UpdateProductRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "商品 ID",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/api-keys/{id}/products/{productId}/codes": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "商品 ID",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "name": "delivered",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/product.ListProductCodesResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "商品 ID",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "发货内容",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/product.UploadProductCodesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/api-keys/{id}/redeem-batches": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "redeem"
+                ],
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "active",
+                            "disabled"
+                        ],
+                        "type": "string",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "redeem"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/redeem.CreateRedeemBatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/api-keys/{id}/redeem-batches/{batchId}/disable": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "redeem"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "批次 ID",
+                        "name": "batchId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/api-keys/{id}/redeem-batches/{batchId}/export": {
+            "get": {
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "redeem"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "批次 ID",
+                        "name": "batchId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "CSV 文件",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/api-keys/{id}/redeem-batches/{batchId}/stats": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "redeem"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "批次 ID",
+                        "name": "batchId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/redeem.RedeemBatchStatsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/api-keys/{id}/subscription-plans": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "订阅计划",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/subscription.PlanRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/api-keys/{id}/subscription-plans/{planId}": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Plan ID",
+                        "name": "planId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "订阅计划",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/subscription.PlanRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Plan ID",
+                        "name": "planId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/api-keys/{id}/subscriptions": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "trialing",
+                            "active",
+                            "past_due",
+                            "paused",
+                            "cancelled",
+                            "expired"
+                        ],
+                        "type": "string",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/api-keys/{id}/subscriptions/{subscriptionId}/cancel": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Subscription ID",
+                        "name": "subscriptionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/api-keys/{id}/subscriptions/{subscriptionId}/pause": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Subscription ID",
+                        "name": "subscriptionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/api-keys/{id}/subscriptions/{subscriptionId}/resume": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Subscription ID",
+                        "name": "subscriptionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/charges": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payment.ChargeUserRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "幂等键，重复请求返回首次请求的响应",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/payment.ChargeUserResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/orders": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payment.CreateOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/payment.CreateNativeOrderResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/orders/events": {
+            "get": {
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.OrderEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/orders/{tradeNo}/capture": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "平台订单号",
+                        "name": "tradeNo",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/payment.CaptureOrderRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "幂等键，重复请求返回首次请求的响应",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/payment.OrderAuthorizationResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/orders/{tradeNo}/void": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "平台订单号",
+                        "name": "tradeNo",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/payment.OrderAuthorizationResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/payment": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "parameters": [
+                    {
+                        "description": "支付订单请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payment.PayOrderRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "幂等键，重复请求返回首次请求的响应",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/payment-links/pay": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "parameters": [
+                    {
+                        "description": "支付请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/link.PayByLinkRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "幂等键，重复请求返回首次请求的响应",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/payment-links/{token}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "支付链接 Token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/payment-links/{token}/button": {
+            "get": {
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "支付链接 Token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maxLength": 20,
+                        "type": "string",
+                        "name": "text",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/payment-links/{token}/coupon": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "支付链接 Token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "name": "amount",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 32,
+                        "type": "string",
+                        "name": "coupon_code",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.CouponDiscount"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/payment-links/{token}/qrcode": {
+            "get": {
+                "produces": [
+                    "image/png",
+                    "image/svg+xml"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "支付链接 Token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "png",
+                            "svg"
+                        ],
+                        "type": "string",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 1024,
+                        "minimum": 64,
+                        "type": "integer",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/payment/cancel": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "parameters": [
+                    {
+                        "description": "取消订单请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payment.CancelOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/payment/coupon": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "parameters": [
+                    {
+                        "maxLength": 32,
+                        "type": "string",
+                        "name": "coupon_code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "order_no",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.CouponDiscount"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/payment/events": {
+            "get": {
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "订单号",
+                        "name": "order_no",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.OrderEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/payment/order": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "订单号",
+                        "name": "order_no",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/payment/qrcode": {
+            "get": {
+                "produces": [
+                    "image/png",
+                    "image/svg+xml"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "parameters": [
+                    {
+                        "enum": [
+                            "png",
+                            "svg"
+                        ],
+                        "type": "string",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "order_no",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 1024,
+                        "minimum": 64,
+                        "type": "integer",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/payouts": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payout"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payout.CreatePayoutRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "幂等键，重复请求返回首次请求的响应",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/payouts/{outBatchNo}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payout"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "商户批次号",
+                        "name": "outBatchNo",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/product-orders/{tradeNo}/deliveries": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "平台订单号",
+                        "name": "tradeNo",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/products/{productId}/buy": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "商品 ID",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "购买请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/product.BuyProductRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "幂等键，重复请求返回首次请求的响应",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/product.BuyProductResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/stores/{clientId}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "商户应用 Client ID",
+                        "name": "clientId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/product.StoreResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/money-requests": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "money_request"
+                ],
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "incoming",
+                            "outgoing"
+                        ],
+                        "type": "string",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "paid",
+                            "declined",
+                            "cancelled",
+                            "expired"
+                        ],
+                        "type": "string",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "money_request"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/money_request.CreateMoneyRequestRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/money-requests/{token}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "money_request"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "收款请求 token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/money-requests/{token}/cancel": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "money_request"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "收款请求 token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/money-requests/{token}/decline": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "money_request"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "收款请求 token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/money-requests/{token}/pay": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "money_request"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "收款请求 token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/money_request.PayMoneyRequestRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "幂等键，重复请求返回首次请求的响应",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/notifications": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notification"
+                ],
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "name": "unread_only",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/notifications/read": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notification"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/notification.MarkNotificationsReadRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/oauth/callback": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "oauth"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/oauth.CallbackRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/oauth/login": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "oauth"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/oauth/logout": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "oauth"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/oauth/user-info": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "oauth"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/order/dispute": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dispute.CreateDisputeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/order/dispute/close": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dispute.CloseDisputeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/order/disputes": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dispute.ListDisputesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/order/disputes/merchant": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dispute.ListDisputesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/order/refund-review": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dispute.RefundReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/order/transactions": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/order.TransactionListRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/payment/transfer": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "parameters": [
+                    {
+                        "description": "转账请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payment.TransferRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "幂等键，重复请求返回首次请求的响应",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/receive-code": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "receive_code"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/receive_code.ReceiveCodeResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/receive-code/qrcode": {
+            "get": {
+                "produces": [
+                    "image/png",
+                    "image/svg+xml"
+                ],
+                "tags": [
+                    "receive_code"
+                ],
+                "parameters": [
+                    {
+                        "enum": [
+                            "png",
+                            "svg"
+                        ],
+                        "type": "string",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 1024,
+                        "minimum": 64,
+                        "type": "integer",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/receive-code/regenerate": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "receive_code"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/receive_code.ReceiveCodeResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/receive-codes/{token}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "receive_code"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "收款码 token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/receive_code.ReceiveCodeOwner"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/receive-codes/{token}/pay": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "receive_code"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "收款码 token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/receive_code.PayReceiveCodeRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "幂等键，重复请求返回首次请求的响应",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/red-packets": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "red_packet"
+                ],
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "active",
+                            "finished",
+                            "refunded"
+                        ],
+                        "type": "string",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "red_packet"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/red_packet.CreateRedPacketRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "幂等键，重复请求返回首次请求的响应",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/red-packets/{token}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "red_packet"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "红包 token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/red_packet.RedPacketDetailResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/red-packets/{token}/claim": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "red_packet"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "红包 token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "幂等键，重复请求返回首次请求的响应",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/redeem": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "redeem"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/redeem.RedeemRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "幂等键，重复请求返回首次请求的响应",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/scheduled-transfers": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scheduled_transfer"
+                ],
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "active",
+                            "completed",
+                            "failed",
+                            "cancelled"
+                        ],
+                        "type": "string",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scheduled_transfer"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/scheduled_transfer.CreateScheduledTransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/scheduled-transfers/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scheduled_transfer"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "定时转账 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scheduled_transfer"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "定时转账 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/scheduled_transfer.UpdateScheduledTransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/scheduled-transfers/{id}/cancel": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scheduled_transfer"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "定时转账 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscription/plans/{planId}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Plan ID",
+                        "name": "planId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscription/subscriptions": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "trialing",
+                            "active",
+                            "past_due",
+                            "paused",
+                            "cancelled",
+                            "expired"
+                        ],
+                        "type": "string",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "parameters": [
+                    {
+                        "description": "订阅请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/subscription.SubscribeRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "幂等键，重复请求返回首次请求的响应",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscription/subscriptions/{subscriptionId}/cancel": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Subscription ID",
+                        "name": "subscriptionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscription/subscriptions/{subscriptionId}/pause": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Subscription ID",
+                        "name": "subscriptionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscription/subscriptions/{subscriptionId}/resume": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Subscription ID",
+                        "name": "subscriptionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/allowances": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/allowance.GrantAllowanceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/allowances/{id}": {
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Allowance ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/allowances/{id}/usages": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Allowance ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/pay-key": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.UpdatePayKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/search": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "parameters": [
+                    {
+                        "maxLength": 64,
+                        "type": "string",
+                        "name": "keyword",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 20,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/mapi.php": {
+            "post": {
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payment.CreateOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/payment.CreateMerchantOrderAPIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pay/submit.php": {
+            "post": {
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payment.CreateOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "allowance.GrantAllowanceRequest": {
+            "type": "object",
+            "required": [
+                "client_id",
+                "pay_key"
+            ],
+            "properties": {
+                "client_id": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "daily_limit": {
+                    "type": "number"
+                },
+                "pay_key": {
+                    "type": "string",
+                    "maxLength": 6
+                },
+                "per_payment_limit": {
+                    "type": "number"
+                }
+            }
+        },
+        "api_key.CreateAPIKeyRequest": {
+            "type": "object",
+            "required": [
+                "app_homepage_url",
+                "app_name",
+                "notify_url"
+            ],
+            "properties": {
+                "app_description": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "app_homepage_url": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "app_name": {
+                    "type": "string",
+                    "maxLength": 20
+                },
+                "daily_receive_limit": {
+                    "type": "number"
+                },
+                "max_order_amount": {
+                    "type": "number"
+                },
+                "min_order_amount": {
+                    "description": "金额限制，不传或传 0 表示不限制",
+                    "type": "number"
+                },
+                "notify_url": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "redirect_uri": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "require_nonce": {
+                    "type": "boolean"
+                },
+                "scopes": {
+                    "description": "权限范围，如 payout:write",
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api_key.UpdateAPIKeyRequest": {
+            "type": "object",
+            "properties": {
+                "app_description": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "app_homepage_url": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "app_name": {
+                    "type": "string",
+                    "maxLength": 20
+                },
+                "daily_receive_limit": {
+                    "type": "number"
+                },
+                "max_order_amount": {
+                    "type": "number"
+                },
+                "min_order_amount": {
+                    "description": "金额限制，传 0 表示取消限制",
+                    "type": "number"
+                },
+                "notify_url": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "redirect_uri": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "require_nonce": {
+                    "type": "boolean"
+                },
+                "scopes": {
+                    "description": "权限范围，传空数组表示清空",
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "coupon.CreateCouponRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "discount_type",
+                "discount_value"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "minLength": 3
+                },
+                "discount_type": {
+                    "enum": [
+                        "percentage",
+                        "fixed"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.CouponDiscountType"
+                        }
+                    ]
+                },
+                "discount_value": {
+                    "type": "number"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "max_uses": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "min_spend": {
+                    "type": "number"
+                },
+                "payment_link_ids": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "per_user_limit": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "scope": {
+                    "enum": [
+                        "all",
+                        "links"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.CouponScope"
+                        }
+                    ]
+                },
+                "starts_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "coupon.ListCouponRedemptionsResponse": {
+            "type": "object",
+            "properties": {
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "redemptions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.MerchantCouponRedemption"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "total_discount": {
+                    "type": "number"
+                },
+                "used_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "coupon.UpdateCouponRequest": {
+            "type": "object",
+            "required": [
+                "discount_type",
+                "discount_value",
+                "is_active"
+            ],
+            "properties": {
+                "discount_type": {
+                    "enum": [
+                        "percentage",
+                        "fixed"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.CouponDiscountType"
+                        }
+                    ]
+                },
+                "discount_value": {
+                    "type": "number"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "max_uses": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "min_spend": {
+                    "type": "number"
+                },
+                "payment_link_ids": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "per_user_limit": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "scope": {
+                    "enum": [
+                        "all",
+                        "links"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.CouponScope"
+                        }
+                    ]
+                },
+                "starts_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dispute.CloseDisputeRequest": {
+            "type": "object",
+            "required": [
+                "dispute_id"
+            ],
+            "properties": {
+                "dispute_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dispute.CreateDisputeRequest": {
+            "type": "object",
+            "required": [
+                "order_id",
+                "reason"
+            ],
+            "properties": {
+                "order_id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "dispute.ListDisputesRequest": {
+            "type": "object",
+            "properties": {
+                "dispute_id": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "page_size": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "disputing",
+                        "refund",
+                        "closed"
+                    ]
+                }
+            }
+        },
+        "dispute.RefundReviewRequest": {
+            "type": "object",
+            "required": [
+                "dispute_id",
+                "status"
+            ],
+            "properties": {
+                "dispute_id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "refund",
+                        "closed"
+                    ]
+                }
+            }
+        },
+        "escrow.ArbitrateEscrowRequest": {
+            "type": "object",
+            "required": [
+                "result"
+            ],
+            "properties": {
+                "note": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "result": {
+                    "enum": [
+                        "release",
+                        "refund"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.EscrowArbitrationResult"
+                        }
+                    ]
+                }
+            }
+        },
+        "escrow.ConfirmEscrowRequest": {
+            "type": "object",
+            "required": [
+                "pay_key"
+            ],
+            "properties": {
+                "pay_key": {
+                    "type": "string",
+                    "maxLength": 6
+                }
+            }
+        },
+        "escrow.CreateEscrowRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "pay_key",
+                "seller_id",
+                "seller_username",
+                "title"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "pay_key": {
+                    "type": "string",
+                    "maxLength": 6
+                },
+                "remark": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "seller_id": {
+                    "type": "integer"
+                },
+                "seller_username": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 64
+                }
+            }
+        },
+        "escrow.DisputeEscrowRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 500
+                }
+            }
+        },
+        "escrow.ShipEscrowRequest": {
+            "type": "object",
+            "required": [
+                "shipping_info"
+            ],
+            "properties": {
+                "shipping_info": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "link.CreatePaymentLinkRequest": {
+            "type": "object",
+            "required": [
+                "product_name"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "amount_mode": {
+                    "enum": [
+                        "fixed",
+                        "open",
+                        "tiers"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.PaymentLinkAmountMode"
+                        }
+                    ]
+                },
+                "amount_tiers": {
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "goal_amount": {
+                    "type": "number"
+                },
+                "max_amount": {
+                    "type": "number"
+                },
+                "max_uses": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "min_amount": {
+                    "type": "number"
+                },
+                "per_user_limit": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "product_name": {
+                    "type": "string",
+                    "maxLength": 30
+                },
+                "remark": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "stock": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "link.PayByLinkRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "coupon_code": {
+                    "type": "string",
+                    "maxLength": 32
+                },
+                "out_trade_no": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "pay_key": {
+                    "type": "string",
+                    "maxLength": 6
+                },
+                "remark": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "link.UpdatePaymentLinkRequest": {
+            "type": "object",
+            "required": [
+                "is_active",
+                "product_name"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "amount_mode": {
+                    "enum": [
+                        "fixed",
+                        "open",
+                        "tiers"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.PaymentLinkAmountMode"
+                        }
+                    ]
+                },
+                "amount_tiers": {
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "clear_limits": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "goal_amount": {
+                    "type": "number"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "max_amount": {
+                    "type": "number"
+                },
+                "max_uses": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "min_amount": {
+                    "type": "number"
+                },
+                "per_user_limit": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "product_name": {
+                    "type": "string",
+                    "maxLength": 30
+                },
+                "remark": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "stock": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "model.CouponDiscountType": {
+            "type": "string",
+            "enum": [
+                "percentage",
+                "fixed"
+            ],
+            "x-enum-comments": {
+                "CouponDiscountTypeFixed": "固定金额减免",
+                "CouponDiscountTypePercentage": "按订单金额百分比减免"
+            },
+            "x-enum-descriptions": [
+                "按订单金额百分比减免",
+                "固定金额减免"
+            ],
+            "x-enum-varnames": [
+                "CouponDiscountTypePercentage",
+                "CouponDiscountTypeFixed"
+            ]
+        },
+        "model.CouponScope": {
+            "type": "string",
+            "enum": [
+                "all",
+                "links"
+            ],
+            "x-enum-comments": {
+                "CouponScopeAll": "适用于商户应用的所有订单",
+                "CouponScopeLinks": "仅适用于指定的支付链接"
+            },
+            "x-enum-descriptions": [
+                "适用于商户应用的所有订单",
+                "仅适用于指定的支付链接"
+            ],
+            "x-enum-varnames": [
+                "CouponScopeAll",
+                "CouponScopeLinks"
+            ]
+        },
+        "model.EscrowArbitrationResult": {
+            "type": "string",
+            "enum": [
+                "release",
+                "refund"
+            ],
+            "x-enum-comments": {
+                "EscrowArbitrationRefund": "退款给买家",
+                "EscrowArbitrationRelease": "放款给卖家"
+            },
+            "x-enum-descriptions": [
+                "放款给卖家",
+                "退款给买家"
+            ],
+            "x-enum-varnames": [
+                "EscrowArbitrationRelease",
+                "EscrowArbitrationRefund"
+            ]
+        },
+        "model.MerchantCouponRedemption": {
+            "type": "object",
+            "properties": {
+                "coupon_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "discount_amount": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "original_amount": {
+                    "type": "number"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.MerchantProductCode": {
+            "type": "object",
+            "properties": {
+                "buyer_user_id": {
+                    "type": "integer"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.OrderAuthorizationStatus": {
+            "type": "string",
+            "enum": [
+                "authorized",
+                "captured",
+                "voided"
+            ],
+            "x-enum-comments": {
+                "OrderAuthorizationStatusAuthorized": "已冻结付款人余额，等待商户确认扣款",
+                "OrderAuthorizationStatusCaptured": "商户已确认扣款，未扣款部分已解冻",
+                "OrderAuthorizationStatusVoided": "商户撤销或超时自动撤销，冻结金额已解冻"
+            },
+            "x-enum-descriptions": [
+                "已冻结付款人余额，等待商户确认扣款",
+                "商户已确认扣款，未扣款部分已解冻",
+                "商户撤销或超时自动撤销，冻结金额已解冻"
+            ],
+            "x-enum-varnames": [
+                "OrderAuthorizationStatusAuthorized",
+                "OrderAuthorizationStatusCaptured",
+                "OrderAuthorizationStatusVoided"
+            ]
+        },
+        "model.OrderStatus": {
+            "type": "string",
+            "enum": [
+                "success",
+                "failed",
+                "pending",
+                "expired",
+                "disputing",
+                "refund",
+                "refused",
+                "cancelled",
+                "held",
+                "authorized"
+            ],
+            "x-enum-comments": {
+                "OrderStatusAuthorized": "预授权订单已冻结付款人余额，等待商户确认扣款",
+                "OrderStatusHeld": "担保交易资金托管中"
+            },
+            "x-enum-descriptions": [
+                "",
+                "",
+                "",
+                "",
+                "",
+                "",
+                "",
+                "",
+                "担保交易资金托管中",
+                "预授权订单已冻结付款人余额，等待商户确认扣款"
+            ],
+            "x-enum-varnames": [
+                "OrderStatusSuccess",
+                "OrderStatusFailed",
+                "OrderStatusPending",
+                "OrderStatusExpired",
+                "OrderStatusDisputing",
+                "OrderStatusRefund",
+                "OrderStatusRefused",
+                "OrderStatusCancelled",
+                "OrderStatusHeld",
+                "OrderStatusAuthorized"
+            ]
+        },
+        "model.PayLevel": {
+            "type": "integer",
+            "format": "int32",
+            "enum": [
+                0,
+                1,
+                2,
+                3
+            ],
+            "x-enum-varnames": [
+                "PayLevelFree",
+                "PayLevelBasic",
+                "PayLevelStandard",
+                "PayLevelPremium"
+            ]
+        },
+        "model.PaymentLinkAmountMode": {
+            "type": "string",
+            "enum": [
+                "fixed",
+                "open",
+                "tiers"
+            ],
+            "x-enum-varnames": [
+                "PaymentLinkAmountModeFixed",
+                "PaymentLinkAmountModeOpen",
+                "PaymentLinkAmountModeTiers"
+            ]
+        },
+        "model.RedPacketClaim": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "red_packet_id": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.RedPacketSplitMode": {
+            "type": "string",
+            "enum": [
+                "equal",
+                "random"
+            ],
+            "x-enum-comments": {
+                "RedPacketSplitModeEqual": "平均分配",
+                "RedPacketSplitModeRandom": "拼手气随机分配"
+            },
+            "x-enum-descriptions": [
+                "平均分配",
+                "拼手气随机分配"
+            ],
+            "x-enum-varnames": [
+                "RedPacketSplitModeEqual",
+                "RedPacketSplitModeRandom"
+            ]
+        },
+        "model.RedPacketStatus": {
+            "type": "string",
+            "enum": [
+                "active",
+                "finished",
+                "refunded"
+            ],
+            "x-enum-comments": {
+                "RedPacketStatusActive": "可领取",
+                "RedPacketStatusFinished": "已领完",
+                "RedPacketStatusRefunded": "已过期，剩余金额已退回"
+            },
+            "x-enum-descriptions": [
+                "可领取",
+                "已领完",
+                "已过期，剩余金额已退回"
+            ],
+            "x-enum-varnames": [
+                "RedPacketStatusActive",
+                "RedPacketStatusFinished",
+                "RedPacketStatusRefunded"
+            ]
+        },
+        "model.RedeemCodeBatch": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "client_id": {
+                    "type": "string"
+                },
+                "code_count": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "creator_user_id": {
+                    "type": "integer"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "max_uses": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "per_user_limit": {
+                    "type": "integer"
+                },
+                "redeemed_amount": {
+                    "type": "number"
+                },
+                "redeemed_count": {
+                    "type": "integer"
+                },
+                "refunded_amount": {
+                    "type": "number"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.RedeemCodeBatchStatus"
+                },
+                "total_budget": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.RedeemCodeBatchStatus": {
+            "type": "string",
+            "enum": [
+                "active",
+                "disabled"
+            ],
+            "x-enum-varnames": [
+                "RedeemCodeBatchStatusActive",
+                "RedeemCodeBatchStatusDisabled"
+            ]
+        },
+        "model.SubscriptionInterval": {
+            "type": "string",
+            "enum": [
+                "day",
+                "week",
+                "month",
+                "year"
+            ],
+            "x-enum-varnames": [
+                "SubscriptionIntervalDay",
+                "SubscriptionIntervalWeek",
+                "SubscriptionIntervalMonth",
+                "SubscriptionIntervalYear"
+            ]
+        },
+        "model.TrustLevel": {
+            "type": "integer",
+            "format": "int32",
+            "enum": [
+                0,
+                1,
+                2,
+                3,
+                4
+            ],
+            "x-enum-varnames": [
+                "TrustLevelNewUser",
+                "TrustLevelBasicUser",
+                "TrustLevelUser",
+                "TrustLevelActiveUser",
+                "TrustLevelLeader"
+            ]
+        },
+        "money_request.CreateMoneyRequestRequest": {
+            "type": "object",
+            "required": [
+                "amount"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "payer_id": {
+                    "type": "integer"
+                },
+                "payer_username": {
+                    "type": "string"
+                }
+            }
+        },
+        "money_request.PayMoneyRequestRequest": {
+            "type": "object",
+            "required": [
+                "pay_key"
+            ],
+            "properties": {
+                "pay_key": {
+                    "type": "string",
+                    "maxLength": 6
+                }
+            }
+        },
+        "notification.MarkNotificationsReadRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "oauth.CallbackRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "order.TransactionListRequest": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "type": "string"
+                },
+                "endTime": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "page_size": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1
+                },
+                "startTime": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "success",
+                        "pending",
+                        "failed",
+                        "expired",
+                        "disputing",
+                        "refund",
+                        "refused",
+                        "cancelled",
+                        "held",
+                        "authorized"
+                    ]
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "receive",
+                        "payment",
+                        "transfer",
+                        "community",
+                        "payout",
+                        "escrow",
+                        "red_packet",
+                        "redeem"
+                    ]
+                }
+            }
+        },
+        "payment.CancelOrderRequest": {
+            "type": "object",
+            "required": [
+                "order_no"
+            ],
+            "properties": {
+                "order_no": {
+                    "type": "string"
+                }
+            }
+        },
+        "payment.CaptureOrderRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Amount 确认扣款金额，不超过预授权金额，为空时按预授权金额全额扣款",
+                    "type": "number"
+                }
+            }
+        },
+        "payment.ChargeUserRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "order_name",
+                "user_id"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "merchant_order_no": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "order_name": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "remark": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "payment.ChargeUserResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "trade_no": {
+                    "type": "string",
+                    "example": "123456"
+                },
+                "trade_time": {
+                    "type": "string"
+                }
+            }
+        },
+        "payment.CreateMerchantOrderAPIResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "msg": {
+                    "type": "string",
+                    "example": "创建订单成功"
+                },
+                "payurl": {
+                    "type": "string"
+                },
+                "qrcode": {
+                    "type": "string"
+                },
+                "trade_no": {
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "payment.CreateNativeOrderResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "pay_url": {
+                    "type": "string"
+                },
+                "trade_no": {
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "payment.CreateOrderRequest": {
+            "type": "object",
             "required": [
-                "app_homepage_url",
-                "app_name",
-                "notify_url"
+                "amount"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "expire_minutes": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "items": {
+                    "description": "Items 商品明细，提供时订单金额由明细合计得出，amount 可省略，order_name 为空时取自首个商品",
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "$ref": "#/definitions/payment.OrderItemRequest"
+                    }
+                },
+                "merchant_order_no": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "order_name": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "payment_type": {
+                    "type": "string"
+                },
+                "pre_auth": {
+                    "description": "PreAuth 预授权订单，支付时仅冻结付款人余额，由商户确认扣款或撤销",
+                    "type": "boolean"
+                },
+                "remark": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "splits": {
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "$ref": "#/definitions/payment.OrderSplitRule"
+                    }
+                }
+            }
+        },
+        "payment.OrderAuthorizationResponse": {
+            "type": "object",
+            "properties": {
+                "captured_amount": {
+                    "type": "number"
+                },
+                "hold_amount": {
+                    "type": "number"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.OrderAuthorizationStatus"
+                },
+                "trade_no": {
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "payment.OrderItemRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "quantity",
+                "unit_price"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "quantity": {
+                    "type": "integer",
+                    "maximum": 10000,
+                    "minimum": 1
+                },
+                "sku": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "unit_price": {
+                    "type": "number"
+                }
+            }
+        },
+        "payment.OrderSplitRule": {
+            "type": "object",
+            "required": [
+                "recipient_user_id"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "percentage": {
+                    "type": "number"
+                },
+                "recipient_user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "payment.PayOrderRequest": {
+            "type": "object",
+            "required": [
+                "order_no"
+            ],
+            "properties": {
+                "coupon_code": {
+                    "description": "CouponCode 使用的商户优惠码，按减免后的金额支付",
+                    "type": "string",
+                    "maxLength": 32
+                },
+                "order_no": {
+                    "type": "string"
+                },
+                "pay_key": {
+                    "description": "PayKey 为空时使用用户授予该商户应用的免密支付额度",
+                    "type": "string",
+                    "maxLength": 6
+                }
+            }
+        },
+        "payment.QueryMerchantOrderResponse": {
+            "type": "object",
+            "properties": {
+                "addtime": {
+                    "type": "string",
+                    "example": "2023-12-08 12:00:00"
+                },
+                "code": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "endtime": {
+                    "type": "string",
+                    "example": "2023-12-08 12:05:00"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "money": {
+                    "type": "string",
+                    "example": "10.00"
+                },
+                "msg": {
+                    "type": "string",
+                    "example": "查询订单号成功！"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "商品名称"
+                },
+                "out_trade_no": {
+                    "type": "string",
+                    "example": "M202312080001"
+                },
+                "param": {
+                    "type": "string",
+                    "example": ""
+                },
+                "pid": {
+                    "type": "string",
+                    "example": "1001"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "trade_no": {
+                    "type": "string",
+                    "example": "123456"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "epay"
+                }
+            }
+        },
+        "payment.RefundMerchantOrderResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "msg": {
+                    "type": "string",
+                    "example": "退款成功"
+                }
+            }
+        },
+        "payment.RefundOrderRequest": {
+            "type": "object",
+            "required": [
+                "money",
+                "pid",
+                "trade_no"
+            ],
+            "properties": {
+                "items": {
+                    "type": "string",
+                    "maxLength": 1024
+                },
+                "key": {
+                    "type": "string"
+                },
+                "money": {
+                    "type": "number"
+                },
+                "nonce": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "out_trade_no": {
+                    "type": "string"
+                },
+                "pid": {
+                    "type": "string"
+                },
+                "sign": {
+                    "type": "string"
+                },
+                "sign_type": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "trade_no": {
+                    "type": "integer"
+                }
+            }
+        },
+        "payment.TransferRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "pay_key",
+                "recipient_username"
             ],
             "properties": {
-                "app_description": {
+                "amount": {
+                    "type": "number"
+                },
+                "pay_key": {
                     "type": "string",
-                    "maxLength": 100
+                    "maxLength": 6
                 },
-                "app_homepage_url": {
+                "recipient_id": {
+                    "type": "integer"
+                },
+                "recipient_username": {
+                    "type": "string"
+                },
+                "remark": {
                     "type": "string",
                     "maxLength": 100
+                }
+            }
+        },
+        "payout.CreatePayoutRequest": {
+            "type": "object",
+            "required": [
+                "items",
+                "out_batch_no"
+            ],
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/payout.PayoutItemRequest"
+                    }
                 },
-                "app_name": {
+                "out_batch_no": {
                     "type": "string",
-                    "maxLength": 20
+                    "maxLength": 64
                 },
-                "notify_url": {
+                "remark": {
                     "type": "string",
                     "maxLength": 100
+                }
+            }
+        },
+        "payout.PayoutItemRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "user_id"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
                 },
-                "redirect_uri": {
+                "out_detail_no": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "remark": {
                     "type": "string",
                     "maxLength": 100
+                },
+                "user_id": {
+                    "type": "integer"
                 }
             }
         },
-        "api_key.UpdateAPIKeyRequest": {
+        "product.BuyProductRequest": {
             "type": "object",
             "properties": {
-                "app_description": {
+                "pay_key": {
                     "type": "string",
-                    "maxLength": 100
+                    "maxLength": 6
                 },
-                "app_homepage_url": {
+                "quantity": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1
+                },
+                "remark": {
                     "type": "string",
                     "maxLength": 100
+                }
+            }
+        },
+        "product.BuyProductResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
                 },
-                "app_name": {
+                "deliveries": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "out_trade_no": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "trade_no": {
+                    "type": "string"
+                }
+            }
+        },
+        "product.CreateProductRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "price"
+            ],
+            "properties": {
+                "description": {
                     "type": "string",
-                    "maxLength": 20
+                    "maxLength": 500
                 },
-                "notify_url": {
+                "is_digital": {
+                    "type": "boolean"
+                },
+                "name": {
                     "type": "string",
-                    "maxLength": 100
+                    "maxLength": 64
                 },
-                "redirect_uri": {
+                "price": {
+                    "type": "number"
+                },
+                "stock": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "product.ListProductCodesResponse": {
+            "type": "object",
+            "properties": {
+                "codes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.MerchantProductCode"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "product.StoreProduct": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_digital": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "sold_count": {
+                    "type": "integer"
+                },
+                "stock": {
+                    "type": "integer"
+                }
+            }
+        },
+        "product.StoreResponse": {
+            "type": "object",
+            "properties": {
+                "app_description": {
+                    "type": "string"
+                },
+                "app_homepage_url": {
+                    "type": "string"
+                },
+                "app_name": {
+                    "type": "string"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/product.StoreProduct"
+                    }
+                }
+            }
+        },
+        "product.UpdateProductRequest": {
+            "type": "object",
+            "required": [
+                "is_active",
+                "name",
+                "price"
+            ],
+            "properties": {
+                "description": {
                     "type": "string",
-                    "maxLength": 100
+                    "maxLength": 500
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "price": {
+                    "type": "number"
+                },
+                "stock": {
+                    "type": "integer",
+                    "minimum": 0
                 }
             }
         },
-        "dispute.CloseDisputeRequest": {
+        "product.UploadProductCodesRequest": {
             "type": "object",
             "required": [
-                "dispute_id"
+                "contents"
             ],
             "properties": {
-                "dispute_id": {
-                    "type": "integer"
+                "contents": {
+                    "type": "array",
+                    "maxItems": 1000,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "dispute.CreateDisputeRequest": {
+        "receive_code.PayReceiveCodeRequest": {
             "type": "object",
             "required": [
-                "order_id",
-                "reason"
+                "amount",
+                "pay_key"
             ],
             "properties": {
-                "order_id": {
-                    "type": "integer"
+                "amount": {
+                    "type": "number"
                 },
-                "reason": {
+                "pay_key": {
+                    "type": "string",
+                    "maxLength": 6
+                },
+                "remark": {
                     "type": "string",
                     "maxLength": 100
                 }
             }
         },
-        "dispute.ListDisputesRequest": {
+        "receive_code.ReceiveCodeOwner": {
             "type": "object",
             "properties": {
-                "dispute_id": {
+                "avatar_url": {
+                    "type": "string"
+                },
+                "id": {
                     "type": "integer"
                 },
-                "page": {
-                    "type": "integer",
-                    "minimum": 1
+                "nickname": {
+                    "type": "string"
                 },
-                "page_size": {
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "receive_code.ReceiveCodeResponse": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "red_packet.CreateRedPacketRequest": {
+            "type": "object",
+            "required": [
+                "count",
+                "pay_key",
+                "split_mode",
+                "total_amount"
+            ],
+            "properties": {
+                "count": {
                     "type": "integer",
-                    "maximum": 100,
                     "minimum": 1
                 },
-                "status": {
+                "greeting": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "min_trust_level": {
+                    "maximum": 4,
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.TrustLevel"
+                        }
+                    ]
+                },
+                "pay_key": {
                     "type": "string",
+                    "maxLength": 6
+                },
+                "split_mode": {
                     "enum": [
-                        "disputing",
-                        "refund",
-                        "closed"
+                        "equal",
+                        "random"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.RedPacketSplitMode"
+                        }
                     ]
+                },
+                "total_amount": {
+                    "type": "number"
                 }
             }
         },
-        "dispute.RefundReviewRequest": {
+        "red_packet.RedPacketDetailResponse": {
             "type": "object",
-            "required": [
-                "dispute_id",
-                "status"
-            ],
             "properties": {
-                "dispute_id": {
+                "claims": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.RedPacketClaim"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "greeting": {
+                    "type": "string"
+                },
+                "id": {
                     "type": "integer"
                 },
-                "reason": {
-                    "type": "string",
-                    "maxLength": 100
+                "min_trust_level": {
+                    "$ref": "#/definitions/model.TrustLevel"
+                },
+                "my_claim": {
+                    "$ref": "#/definitions/model.RedPacketClaim"
+                },
+                "refunded_amount": {
+                    "type": "number"
+                },
+                "remaining_amount": {
+                    "type": "number"
+                },
+                "remaining_count": {
+                    "type": "integer"
+                },
+                "sender_user_id": {
+                    "type": "integer"
+                },
+                "sender_username": {
+                    "type": "string"
+                },
+                "split_mode": {
+                    "$ref": "#/definitions/model.RedPacketSplitMode"
                 },
                 "status": {
-                    "type": "string",
-                    "enum": [
-                        "refund",
-                        "closed"
-                    ]
+                    "$ref": "#/definitions/model.RedPacketStatus"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "total_amount": {
+                    "type": "number"
+                },
+                "total_count": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "link.CreatePaymentLinkRequest": {
+        "redeem.CreateRedeemBatchRequest": {
             "type": "object",
             "required": [
                 "amount",
-                "product_name"
+                "code_count",
+                "name"
             ],
             "properties": {
                 "amount": {
                     "type": "number"
                 },
-                "product_name": {
-                    "type": "string",
-                    "maxLength": 30
+                "code_count": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 1
                 },
-                "remark": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "max_uses": {
+                    "type": "integer",
+                    "maximum": 10000,
+                    "minimum": 1
+                },
+                "name": {
                     "type": "string",
-                    "maxLength": 100
+                    "maxLength": 64
+                },
+                "per_user_limit": {
+                    "type": "integer",
+                    "minimum": 1
                 }
             }
         },
-        "link.PayByLinkRequest": {
+        "redeem.RedeemBatchStatsResponse": {
             "type": "object",
-            "required": [
-                "pay_key",
-                "token"
-            ],
             "properties": {
-                "pay_key": {
-                    "type": "string",
-                    "maxLength": 6
+                "batch": {
+                    "$ref": "#/definitions/model.RedeemCodeBatch"
                 },
-                "remark": {
-                    "type": "string",
-                    "maxLength": 100
+                "exhausted_codes": {
+                    "type": "integer"
                 },
-                "token": {
-                    "type": "string"
+                "remaining_budget": {
+                    "type": "number"
+                },
+                "unique_users": {
+                    "type": "integer"
+                },
+                "used_codes": {
+                    "type": "integer"
                 }
             }
         },
-        "model.PayLevel": {
-            "type": "integer",
-            "format": "int32",
-            "enum": [
-                0,
-                1,
-                2,
-                3
-            ],
-            "x-enum-varnames": [
-                "PayLevelFree",
-                "PayLevelBasic",
-                "PayLevelStandard",
-                "PayLevelPremium"
-            ]
-        },
-        "oauth.CallbackRequest": {
+        "redeem.RedeemRequest": {
             "type": "object",
+            "required": [
+                "code"
+            ],
             "properties": {
                 "code": {
-                    "type": "string"
-                },
-                "state": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 64
                 }
             }
         },
-        "order.TransactionListRequest": {
+        "scheduled_transfer.CreateScheduledTransferRequest": {
             "type": "object",
+            "required": [
+                "amount",
+                "pay_key",
+                "recipient_username"
+            ],
             "properties": {
-                "client_id": {
-                    "type": "string"
+                "amount": {
+                    "type": "number"
                 },
-                "endTime": {
+                "cron_expr": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "ends_at": {
                     "type": "string"
                 },
-                "page": {
-                    "type": "integer",
-                    "minimum": 1
+                "pay_key": {
+                    "type": "string",
+                    "maxLength": 6
                 },
-                "page_size": {
-                    "type": "integer",
-                    "maximum": 100,
-                    "minimum": 1
+                "recipient_id": {
+                    "type": "integer"
                 },
-                "startTime": {
+                "recipient_username": {
                     "type": "string"
                 },
-                "status": {
+                "remark": {
                     "type": "string",
-                    "enum": [
-                        "success",
-                        "pending",
-                        "failed",
-                        "expired",
-                        "disputing",
-                        "refund",
-                        "refused"
-                    ]
+                    "maxLength": 100
                 },
-                "type": {
-                    "type": "string",
-                    "enum": [
-                        "receive",
-                        "payment",
-                        "transfer",
-                        "community"
-                    ]
+                "run_at": {
+                    "type": "string"
                 }
             }
         },
-        "payment.CreateOrderRequest": {
+        "scheduled_transfer.UpdateScheduledTransferRequest": {
             "type": "object",
             "required": [
                 "amount",
-                "order_name"
+                "pay_key"
             ],
             "properties": {
                 "amount": {
                     "type": "number"
                 },
-                "merchant_order_no": {
-                    "type": "string"
-                },
-                "order_name": {
+                "cron_expr": {
                     "type": "string",
                     "maxLength": 64
                 },
-                "payment_type": {
+                "ends_at": {
                     "type": "string"
                 },
+                "pay_key": {
+                    "type": "string",
+                    "maxLength": 6
+                },
                 "remark": {
                     "type": "string",
                     "maxLength": 100
+                },
+                "run_at": {
+                    "type": "string"
                 }
             }
         },
-        "payment.PayOrderRequest": {
+        "service.CouponDiscount": {
             "type": "object",
-            "required": [
-                "order_no",
-                "pay_key"
-            ],
             "properties": {
-                "order_no": {
+                "code": {
                     "type": "string"
                 },
-                "pay_key": {
-                    "type": "string",
-                    "maxLength": 6
+                "discount_amount": {
+                    "type": "number"
+                },
+                "original_amount": {
+                    "type": "number"
+                },
+                "payable_amount": {
+                    "type": "number"
                 }
             }
         },
-        "payment.QueryMerchantOrderResponse": {
+        "service.OrderEvent": {
             "type": "object",
             "properties": {
-                "addtime": {
-                    "type": "string",
-                    "example": "2023-12-08 12:00:00"
-                },
-                "code": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "endtime": {
-                    "type": "string",
-                    "example": "2023-12-08 12:05:00"
+                "amount": {
+                    "type": "number"
                 },
-                "money": {
-                    "type": "string",
-                    "example": "10.00"
+                "client_id": {
+                    "type": "string"
                 },
-                "msg": {
-                    "type": "string",
-                    "example": "查询订单号成功！"
+                "event_time": {
+                    "type": "string"
                 },
-                "name": {
-                    "type": "string",
-                    "example": "商品名称"
+                "order_id": {
+                    "type": "integer"
                 },
                 "out_trade_no": {
-                    "type": "string",
-                    "example": "M202312080001"
-                },
-                "pid": {
-                    "type": "string",
-                    "example": "1001"
+                    "type": "string"
                 },
                 "status": {
-                    "type": "integer",
-                    "example": 1
+                    "$ref": "#/definitions/model.OrderStatus"
                 },
                 "trade_no": {
-                    "type": "string",
-                    "example": "123456"
-                },
-                "type": {
-                    "type": "string",
-                    "example": "epay"
-                }
-            }
-        },
-        "payment.RefundMerchantOrderResponse": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "msg": {
-                    "type": "string",
-                    "example": "退款成功"
+                    "type": "string"
                 }
             }
         },
-        "payment.RefundOrderRequest": {
+        "subscription.PlanRequest": {
             "type": "object",
             "required": [
-                "key",
-                "money",
-                "pid",
-                "trade_no"
+                "interval",
+                "name"
             ],
             "properties": {
-                "key": {
-                    "type": "string"
-                },
-                "money": {
+                "amount": {
                     "type": "number"
                 },
-                "out_trade_no": {
-                    "type": "string"
+                "description": {
+                    "type": "string",
+                    "maxLength": 100
                 },
-                "pid": {
-                    "type": "string"
+                "interval": {
+                    "enum": [
+                        "day",
+                        "week",
+                        "month",
+                        "year"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.SubscriptionInterval"
+                        }
+                    ]
                 },
-                "trade_no": {
-                    "type": "integer"
+                "interval_count": {
+                    "type": "integer",
+                    "maximum": 365,
+                    "minimum": 1
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 30
+                },
+                "trial_days": {
+                    "type": "integer",
+                    "maximum": 365,
+                    "minimum": 0
                 }
             }
         },
-        "payment.TransferRequest": {
+        "subscription.SubscribeRequest": {
             "type": "object",
             "required": [
-                "amount",
                 "pay_key",
-                "recipient_id",
-                "recipient_username"
+                "plan_id"
             ],
             "properties": {
-                "amount": {
-                    "type": "number"
-                },
                 "pay_key": {
                     "type": "string",
                     "maxLength": 6
                 },
-                "recipient_id": {
+                "plan_id": {
                     "type": "integer"
-                },
-                "recipient_username": {
-                    "type": "string"
-                },
-                "remark": {
-                    "type": "string",
-                    "maxLength": 100
                 }
             }
         },
@@ -0,0 +1,253 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+package allowance
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/pay/internal/apps/oauth"
+	"github.com/linux-do/pay/internal/common"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/service"
+	"github.com/linux-do/pay/internal/util"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GrantAllowanceRequest 授权免密支付请求
+type GrantAllowanceRequest struct {
+	ClientID        string          `json:"client_id" binding:"required,max=64"`
+	PerPaymentLimit decimal.Decimal `json:"per_payment_limit"`
+	DailyLimit      decimal.Decimal `json:"daily_limit"`
+	PayKey          string          `json:"pay_key" binding:"required,max=6"`
+}
+
+// GrantAllowance 授权商户应用免密支付，已授权时更新额度
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param request body GrantAllowanceRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/user/allowances [put]
+func GrantAllowance(c *gin.Context) {
+	var req GrantAllowanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	for _, limit := range []decimal.Decimal{req.PerPaymentLimit, req.DailyLimit} {
+		if limit.LessThanOrEqual(decimal.Zero) {
+			c.JSON(http.StatusBadRequest, util.Err(common.AmountMustBeGreaterThanZero))
+			return
+		}
+		if limit.Exponent() < -2 {
+			c.JSON(http.StatusBadRequest, util.Err(common.AmountDecimalPlacesExceeded))
+			return
+		}
+	}
+	if req.PerPaymentLimit.GreaterThan(req.DailyLimit) {
+		c.JSON(http.StatusBadRequest, util.Err(PerPaymentAboveDaily))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	if subtle.ConstantTimeCompare([]byte(user.PayKey), []byte(req.PayKey)) != 1 {
+		c.JSON(http.StatusBadRequest, util.Err(common.PayKeyIncorrect))
+		return
+	}
+
+	var apiKey model.MerchantAPIKey
+	if err := apiKey.GetByClientID(db.DB(c.Request.Context()), req.ClientID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(MerchantAppNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+	if apiKey.UserID == user.ID {
+		c.JSON(http.StatusBadRequest, util.Err(CannotGrantOwnApp))
+		return
+	}
+
+	allowance := model.PaymentAllowance{
+		UserID:          user.ID,
+		ClientID:        apiKey.ClientID,
+		PerPaymentLimit: req.PerPaymentLimit,
+		DailyLimit:      req.DailyLimit,
+		IsActive:        true,
+	}
+	if err := db.DB(c.Request.Context()).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "client_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"per_payment_limit": allowance.PerPaymentLimit,
+			"daily_limit":       allowance.DailyLimit,
+			"is_active":         true,
+			"revoked_at":        nil,
+			"updated_at":        time.Now(),
+		}),
+	}).Create(&allowance).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(allowance))
+}
+
+// ListAllowances 查询当前用户的免密支付授权及当日用量
+// @Tags user
+// @Produce json
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/user/allowances [get]
+func ListAllowances(c *gin.Context) {
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	var allowances []model.PaymentAllowance
+	if err := db.DB(c.Request.Context()).
+		Model(&model.PaymentAllowance{}).
+		Select("payment_allowances.*, merchant_api_keys.app_name").
+		Joins("LEFT JOIN merchant_api_keys ON merchant_api_keys.client_id = payment_allowances.client_id").
+		Where("payment_allowances.user_id = ?", user.ID).
+		Order("payment_allowances.is_active DESC, payment_allowances.updated_at DESC").
+		Find(&allowances).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	for i := range allowances {
+		if !allowances[i].IsActive {
+			continue
+		}
+		used, err := service.GetAllowanceTodayUsed(db.DB(c.Request.Context()), allowances[i].ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+			return
+		}
+		allowances[i].TodayUsedAmount = used
+	}
+
+	c.JSON(http.StatusOK, util.OK(allowances))
+}
+
+// RevokeAllowance 撤销免密支付授权
+// @Tags user
+// @Produce json
+// @Param id path uint64 true "Allowance ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/user/allowances/{id} [delete]
+func RevokeAllowance(c *gin.Context) {
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	result := db.DB(c.Request.Context()).
+		Model(&model.PaymentAllowance{}).
+		Where("id = ? AND user_id = ? AND is_active = ?", c.Param("id"), user.ID, true).
+		Updates(map[string]interface{}{
+			"is_active":  false,
+			"revoked_at": time.Now(),
+		})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(result.Error.Error()))
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, util.Err(AllowanceNotFound))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
+
+// ListAllowanceUsagesRequest 查询免密支付记录请求
+type ListAllowanceUsagesRequest struct {
+	Page     int `json:"page" form:"page" binding:"min=1"`
+	PageSize int `json:"page_size" form:"page_size" binding:"min=1,max=100"`
+}
+
+// ListAllowanceUsagesResponse 查询免密支付记录响应
+type ListAllowanceUsagesResponse struct {
+	Total    int64         `json:"total"`
+	Page     int           `json:"page"`
+	PageSize int           `json:"page_size"`
+	Orders   []model.Order `json:"orders"`
+}
+
+// ListAllowanceUsages 查询免密支付授权的使用记录
+// @Tags user
+// @Produce json
+// @Param id path uint64 true "Allowance ID"
+// @Param request query ListAllowanceUsagesRequest true "查询参数"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/user/allowances/{id}/usages [get]
+func ListAllowanceUsages(c *gin.Context) {
+	var req ListAllowanceUsagesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	var allowance model.PaymentAllowance
+	if err := db.DB(c.Request.Context()).
+		Where("id = ? AND user_id = ?", c.Param("id"), user.ID).
+		First(&allowance).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(AllowanceNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	baseQuery := db.DB(c.Request.Context()).Model(&model.Order{}).
+		Where("allowance_id = ? AND payer_user_id = ?", allowance.ID, user.ID)
+
+	var total int64
+	if err := baseQuery.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	response := &ListAllowanceUsagesResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := baseQuery.Order("created_at DESC").Offset(offset).Limit(req.PageSize).Find(&response.Orders).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
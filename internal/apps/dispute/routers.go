@@ -32,8 +32,10 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/linux-do/pay/internal/apps/oauth"
+	"github.com/linux-do/pay/internal/common"
 	"github.com/linux-do/pay/internal/db"
 	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/service"
 	"github.com/linux-do/pay/internal/util"
 	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
@@ -277,6 +279,7 @@ func RefundReview(c *gin.Context) {
 
 	merchantUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
 
+	var order model.Order
 	if err := db.DB(c.Request.Context()).Transaction(
 		func(tx *gorm.DB) error {
 			var dispute model.Dispute
@@ -289,7 +292,6 @@ func RefundReview(c *gin.Context) {
 				return err
 			}
 
-			var order model.Order
 			if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
 				Where("id = ? AND payee_user_id = ? AND status = ? AND type = ?", dispute.OrderID, merchantUser.ID, model.OrderStatusDisputing, model.OrderTypePayment).
 				First(&order).Error; err != nil {
@@ -311,12 +313,21 @@ func RefundReview(c *gin.Context) {
 					return err
 				}
 
-				merchantScoreDecrease := order.Amount.Mul(merchantPayConfig.ScoreRate).Round(0).IntPart()
+				// 已按商品行部分退款的订单只退还剩余金额
+				refundAmount := order.RefundableAmount()
+
+				// 按比例扣回分账收款人的分账份额，商户退还剩余部分
+				splitAmount, err := service.ReverseOrderSplits(tx, &order, refundAmount)
+				if err != nil {
+					return err
+				}
+				merchantScoreDecrease := refundAmount.Mul(merchantPayConfig.ScoreRate).Round(0).IntPart()
+				merchantRefund := refundAmount.Sub(splitAmount)
 				if err := tx.Model(&model.User{}).
 					Where("id = ?", merchantUser.ID).
 					UpdateColumns(map[string]interface{}{
-						"available_balance": gorm.Expr("available_balance - ?", order.Amount),
-						"total_receive":     gorm.Expr("total_receive - ?", order.Amount),
+						"available_balance": gorm.Expr("available_balance - ?", merchantRefund),
+						"total_receive":     gorm.Expr("total_receive - ?", merchantRefund),
 						"pay_score":         gorm.Expr("pay_score - ?", merchantScoreDecrease),
 					}).Error; err != nil {
 					return err
@@ -325,9 +336,9 @@ func RefundReview(c *gin.Context) {
 				if err := tx.Model(&model.User{}).
 					Where("id = ?", payerUser.ID).
 					UpdateColumns(map[string]interface{}{
-						"available_balance": gorm.Expr("available_balance + ?", order.Amount),
-						"total_payment":     gorm.Expr("total_payment - ?", order.Amount),
-						"pay_score":         gorm.Expr("pay_score - ?", order.Amount.Round(0).IntPart()),
+						"available_balance": gorm.Expr("available_balance + ?", refundAmount),
+						"total_payment":     gorm.Expr("total_payment - ?", refundAmount),
+						"pay_score":         gorm.Expr("pay_score - ?", refundAmount.Round(0).IntPart()),
 					}).Error; err != nil {
 					return err
 				}
@@ -343,9 +354,13 @@ func RefundReview(c *gin.Context) {
 
 				if err := tx.Model(&model.Order{}).
 					Where("id = ?", order.ID).
-					UpdateColumn("status", model.OrderStatusRefund).Error; err != nil {
+					UpdateColumns(map[string]interface{}{
+						"status":          model.OrderStatusRefund,
+						"refunded_amount": order.Amount,
+					}).Error; err != nil {
 					return err
 				}
+				order.Status = model.OrderStatusRefund
 			} else if status == model.DisputeStatusClosed {
 				updateData := map[string]interface{}{
 					"status":          model.DisputeStatusClosed,
@@ -372,12 +387,18 @@ func RefundReview(c *gin.Context) {
 		errMsg := err.Error()
 		if errMsg == DisputeNotFound {
 			c.JSON(http.StatusNotFound, util.Err(DisputeNotFound))
+		} else if errMsg == common.SplitReverseInsufficient {
+			c.JSON(http.StatusBadRequest, util.Err(errMsg))
 		} else {
 			c.JSON(http.StatusInternalServerError, util.Err(errMsg))
 		}
 		return
 	}
 
+	if order.Status == model.OrderStatusRefund {
+		service.PublishOrderEvent(c.Request.Context(), &order)
+	}
+
 	c.JSON(http.StatusOK, util.OKNil())
 }
 
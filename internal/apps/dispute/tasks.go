@@ -36,6 +36,7 @@ import (
 	"github.com/linux-do/pay/internal/db"
 	"github.com/linux-do/pay/internal/logger"
 	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/service"
 	"github.com/linux-do/pay/internal/task"
 	"github.com/linux-do/pay/internal/task/schedule"
 	"gorm.io/gorm"
@@ -109,6 +110,7 @@ func HandleAutoRefundSingleDispute(ctx context.Context, t *asynq.Task) error {
 		return fmt.Errorf("解析任务参数失败: %w", err)
 	}
 
+	var order model.Order
 	if err := db.DB(ctx).Transaction(func(tx *gorm.DB) error {
 		var dispute model.Dispute
 		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
@@ -121,7 +123,6 @@ func HandleAutoRefundSingleDispute(ctx context.Context, t *asynq.Task) error {
 			return err
 		}
 
-		var order model.Order
 		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
 			Where("id = ? AND status = ? AND type = ?", dispute.OrderID, model.OrderStatusDisputing, model.OrderTypePayment).
 			First(&order).Error; err != nil {
@@ -147,15 +148,25 @@ func HandleAutoRefundSingleDispute(ctx context.Context, t *asynq.Task) error {
 			return fmt.Errorf("查询商家支付配置失败: %w", err)
 		}
 
-		// 计算商家积分减少：订单金额 × 商家的 score_rate
-		merchantScoreDecrease := order.Amount.Mul(merchantPayConfig.ScoreRate).Round(0).IntPart()
+		// 已按商品行部分退款的订单只退还剩余金额
+		refundAmount := order.RefundableAmount()
+
+		// 计算商家积分减少：退款金额 × 商家的 score_rate
+		merchantScoreDecrease := refundAmount.Mul(merchantPayConfig.ScoreRate).Round(0).IntPart()
+
+		// 按比例扣回分账收款人的分账份额，商户退还剩余部分
+		splitAmount, err := service.ReverseOrderSplits(tx, &order, refundAmount)
+		if err != nil {
+			return fmt.Errorf("扣回分账失败: %w", err)
+		}
 
 		// 商家(收款方)退款：扣除可用余额、总收款和积分
+		merchantRefund := refundAmount.Sub(splitAmount)
 		if err := tx.Model(&model.User{}).
 			Where("id = ?", payeeUser.ID).
 			UpdateColumns(map[string]interface{}{
-				"available_balance": gorm.Expr("available_balance - ?", order.Amount),
-				"total_receive":     gorm.Expr("total_receive - ?", order.Amount),
+				"available_balance": gorm.Expr("available_balance - ?", merchantRefund),
+				"total_receive":     gorm.Expr("total_receive - ?", merchantRefund),
 				"pay_score":         gorm.Expr("pay_score - ?", merchantScoreDecrease),
 			}).Error; err != nil {
 			return fmt.Errorf("商家退款失败: %w", err)
@@ -165,9 +176,9 @@ func HandleAutoRefundSingleDispute(ctx context.Context, t *asynq.Task) error {
 		if err := tx.Model(&model.User{}).
 			Where("id = ?", payerUser.ID).
 			UpdateColumns(map[string]interface{}{
-				"available_balance": gorm.Expr("available_balance + ?", order.Amount),
-				"total_payment":     gorm.Expr("total_payment - ?", order.Amount),
-				"pay_score":         gorm.Expr("pay_score - ?", order.Amount.Round(0).IntPart()),
+				"available_balance": gorm.Expr("available_balance + ?", refundAmount),
+				"total_payment":     gorm.Expr("total_payment - ?", refundAmount),
+				"pay_score":         gorm.Expr("pay_score - ?", refundAmount.Round(0).IntPart()),
 			}).Error; err != nil {
 			return fmt.Errorf("付款方退款失败: %w", err)
 		}
@@ -185,12 +196,16 @@ func HandleAutoRefundSingleDispute(ctx context.Context, t *asynq.Task) error {
 		// 更新订单状态为已退款
 		if err := tx.Model(&model.Order{}).
 			Where("id = ?", order.ID).
-			UpdateColumn("status", model.OrderStatusRefund).Error; err != nil {
+			UpdateColumns(map[string]interface{}{
+				"status":          model.OrderStatusRefund,
+				"refunded_amount": order.Amount,
+			}).Error; err != nil {
 			return fmt.Errorf("更新订单状态失败: %w", err)
 		}
+		order.Status = model.OrderStatusRefund
 
 		logger.InfoF(ctx, "自动退款成功: 争议[ID:%d] 订单[ID:%d] 金额[%s] 付款方[%s] 商家[%s]",
-			dispute.ID, order.ID, order.Amount.String(), payerUser.Username, payeeUser.Username)
+			dispute.ID, order.ID, refundAmount.String(), payerUser.Username, payeeUser.Username)
 
 		return nil
 	}); err != nil {
@@ -198,5 +213,9 @@ func HandleAutoRefundSingleDispute(ctx context.Context, t *asynq.Task) error {
 		return err
 	}
 
+	if order.Status == model.OrderStatusRefund {
+		service.PublishOrderEvent(ctx, &order)
+	}
+
 	return nil
 }
@@ -0,0 +1,55 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package escrow
+
+import "github.com/linux-do/pay/internal/model"
+
+// escrowAction 担保交易操作
+type escrowAction string
+
+const (
+	actionShip    escrowAction = "ship"
+	actionConfirm escrowAction = "confirm"
+	actionCancel  escrowAction = "cancel"
+	actionDispute escrowAction = "dispute"
+)
+
+// actionFromStatuses 各操作允许的原状态
+var actionFromStatuses = map[escrowAction][]model.EscrowStatus{
+	actionShip:    {model.EscrowStatusHeld},
+	actionConfirm: {model.EscrowStatusHeld, model.EscrowStatusShipped},
+	actionCancel:  {model.EscrowStatusHeld},
+	actionDispute: {model.EscrowStatusHeld, model.EscrowStatusShipped},
+}
+
+// orderStatuses 担保交易状态对应的订单状态
+var orderStatuses = map[model.EscrowStatus]model.OrderStatus{
+	model.EscrowStatusHeld:      model.OrderStatusHeld,
+	model.EscrowStatusShipped:   model.OrderStatusHeld,
+	model.EscrowStatusCompleted: model.OrderStatusSuccess,
+	model.EscrowStatusDisputing: model.OrderStatusDisputing,
+	model.EscrowStatusRefunded:  model.OrderStatusRefund,
+	model.EscrowStatusCancelled: model.OrderStatusCancelled,
+}
@@ -0,0 +1,472 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package escrow
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/pay/internal/apps/oauth"
+	"github.com/linux-do/pay/internal/common"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/util"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateEscrowRequest 发起担保交易请求
+type CreateEscrowRequest struct {
+	SellerID       uint64          `json:"seller_id" binding:"required"`
+	SellerUsername string          `json:"seller_username" binding:"required"`
+	Title          string          `json:"title" binding:"required,max=64"`
+	Amount         decimal.Decimal `json:"amount" binding:"required"`
+	Remark         string          `json:"remark" binding:"max=100"`
+	PayKey         string          `json:"pay_key" binding:"required,max=6"`
+}
+
+// CreateEscrow 买家发起担保交易，付款金额从可用余额转入托管余额
+// @Tags escrow
+// @Accept json
+// @Produce json
+// @Param request body CreateEscrowRequest true "request body"
+// @Param Idempotency-Key header string false "幂等键，重复请求返回首次请求的响应"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/escrow [post]
+func CreateEscrow(c *gin.Context) {
+	var req CreateEscrowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	if req.Amount.LessThanOrEqual(decimal.Zero) {
+		c.JSON(http.StatusBadRequest, util.Err(common.AmountMustBeGreaterThanZero))
+		return
+	}
+	if req.Amount.Exponent() < -2 {
+		c.JSON(http.StatusBadRequest, util.Err(common.AmountDecimalPlacesExceeded))
+		return
+	}
+
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	if subtle.ConstantTimeCompare([]byte(currentUser.PayKey), []byte(req.PayKey)) != 1 {
+		c.JSON(http.StatusBadRequest, util.Err(common.PayKeyIncorrect))
+		return
+	}
+
+	if currentUser.ID == req.SellerID {
+		c.JSON(http.StatusBadRequest, util.Err(CannotEscrowWithSelf))
+		return
+	}
+
+	var escrow model.Escrow
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			var seller model.User
+			if err := tx.Where("id = ? AND username = ? AND is_active = ?", req.SellerID, req.SellerUsername, true).First(&seller).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New(SellerNotFound)
+				}
+				return err
+			}
+
+			// 扣减买家可用余额并转入托管余额
+			result := tx.Model(&model.User{}).
+				Where("id = ? AND available_balance >= ?", currentUser.ID, req.Amount).
+				UpdateColumns(map[string]interface{}{
+					"available_balance": gorm.Expr("available_balance - ?", req.Amount),
+					"escrow_balance":    gorm.Expr("escrow_balance + ?", req.Amount),
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return errors.New(common.InsufficientBalance)
+			}
+
+			now := time.Now()
+			order := model.Order{
+				OrderName:   req.Title,
+				PayerUserID: currentUser.ID,
+				PayeeUserID: seller.ID,
+				Amount:      req.Amount,
+				Status:      model.OrderStatusHeld,
+				Type:        model.OrderTypeEscrow,
+				Remark:      req.Remark,
+				TradeTime:   now,
+				ExpiresAt:   now,
+			}
+			if err := tx.Create(&order).Error; err != nil {
+				return err
+			}
+
+			escrow = model.Escrow{
+				OrderID:      order.ID,
+				BuyerUserID:  currentUser.ID,
+				SellerUserID: seller.ID,
+				Title:        req.Title,
+				Amount:       req.Amount,
+				Status:       model.EscrowStatusHeld,
+			}
+			return tx.Create(&escrow).Error
+		},
+	); err != nil {
+		switch err.Error() {
+		case SellerNotFound:
+			c.JSON(http.StatusNotFound, util.Err(SellerNotFound))
+		case common.InsufficientBalance:
+			c.JSON(http.StatusBadRequest, util.Err(common.InsufficientBalance))
+		default:
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(escrow))
+}
+
+// ListEscrowsRequest 查询担保交易列表请求
+type ListEscrowsRequest struct {
+	Page     int    `json:"page" form:"page" binding:"min=1"`
+	PageSize int    `json:"page_size" form:"page_size" binding:"min=1,max=100"`
+	Role     string `json:"role" form:"role" binding:"omitempty,oneof=buyer seller"`
+	Status   string `json:"status" form:"status" binding:"omitempty,oneof=held shipped completed disputing refunded cancelled"`
+}
+
+// ListEscrowsResponse 查询担保交易列表响应
+type ListEscrowsResponse struct {
+	Total    int64          `json:"total"`
+	Page     int            `json:"page"`
+	PageSize int            `json:"page_size"`
+	Escrows  []model.Escrow `json:"escrows"`
+}
+
+// ListEscrows 查询当前用户作为买家或卖家的担保交易
+// @Tags escrow
+// @Produce json
+// @Param request query ListEscrowsRequest true "查询参数"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/escrow [get]
+func ListEscrows(c *gin.Context) {
+	var req ListEscrowsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	scope := partyScope(c)
+	switch req.Role {
+	case "buyer":
+		scope = buyerScope(c)
+	case "seller":
+		scope = sellerScope(c)
+	}
+	listEscrows(c, &req, scope)
+}
+
+// GetEscrow 查询担保交易详情
+// @Tags escrow
+// @Produce json
+// @Param id path uint64 true "担保交易 ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/escrow/{id} [get]
+func GetEscrow(c *gin.Context) {
+	var escrow model.Escrow
+	if err := partyScope(c)(escrowQuery(db.DB(c.Request.Context()))).
+		Where("escrows.id = ?", c.Param("id")).
+		First(&escrow).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(EscrowNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(escrow))
+}
+
+// ShipEscrowRequest 卖家发货请求
+type ShipEscrowRequest struct {
+	ShippingInfo string `json:"shipping_info" binding:"required,max=255"`
+}
+
+// ShipEscrow 卖家标记已发货，开始自动确认收货倒计时
+// @Tags escrow
+// @Accept json
+// @Produce json
+// @Param id path uint64 true "担保交易 ID"
+// @Param request body ShipEscrowRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/escrow/{id}/ship [post]
+func ShipEscrow(c *gin.Context) {
+	var req ShipEscrowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	autoConfirmDays, errGet := model.GetIntByKey(c.Request.Context(), model.ConfigKeyEscrowAutoConfirmDays)
+	if errGet != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(errGet.Error()))
+		return
+	}
+
+	respondEscrowAction(c, sellerScope(c), actionShip, func(tx *gorm.DB, escrow *model.Escrow) error {
+		now := time.Now()
+		autoConfirmAt := now.AddDate(0, 0, autoConfirmDays)
+		escrow.Status = model.EscrowStatusShipped
+		escrow.ShippingInfo = req.ShippingInfo
+		escrow.ShippedAt = &now
+		escrow.AutoConfirmAt = &autoConfirmAt
+		return tx.Model(escrow).Select("status", "shipping_info", "shipped_at", "auto_confirm_at").Updates(escrow).Error
+	})
+}
+
+// ConfirmEscrowRequest 买家确认收货请求
+type ConfirmEscrowRequest struct {
+	PayKey string `json:"pay_key" binding:"required,max=6"`
+}
+
+// ConfirmEscrow 买家确认收货，托管资金放款给卖家
+// @Tags escrow
+// @Accept json
+// @Produce json
+// @Param id path uint64 true "担保交易 ID"
+// @Param request body ConfirmEscrowRequest true "request body"
+// @Param Idempotency-Key header string false "幂等键，重复请求返回首次请求的响应"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/escrow/{id}/confirm [post]
+func ConfirmEscrow(c *gin.Context) {
+	var req ConfirmEscrowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+	if subtle.ConstantTimeCompare([]byte(currentUser.PayKey), []byte(req.PayKey)) != 1 {
+		c.JSON(http.StatusBadRequest, util.Err(common.PayKeyIncorrect))
+		return
+	}
+
+	respondEscrowAction(c, buyerScope(c), actionConfirm, func(tx *gorm.DB, escrow *model.Escrow) error {
+		return settleEscrow(tx, escrow, true)
+	})
+}
+
+// CancelEscrow 卖家在发货前取消担保交易，托管资金退回买家
+// @Tags escrow
+// @Produce json
+// @Param id path uint64 true "担保交易 ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/escrow/{id}/cancel [post]
+func CancelEscrow(c *gin.Context) {
+	respondEscrowAction(c, sellerScope(c), actionCancel, func(tx *gorm.DB, escrow *model.Escrow) error {
+		return settleEscrow(tx, escrow, false)
+	})
+}
+
+// DisputeEscrowRequest 发起担保交易争议请求
+type DisputeEscrowRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// DisputeEscrow 买家或卖家发起争议，冻结自动确认收货，等待管理员仲裁
+// @Tags escrow
+// @Accept json
+// @Produce json
+// @Param id path uint64 true "担保交易 ID"
+// @Param request body DisputeEscrowRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/escrow/{id}/dispute [post]
+func DisputeEscrow(c *gin.Context) {
+	var req DisputeEscrowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	respondEscrowAction(c, partyScope(c), actionDispute, func(tx *gorm.DB, escrow *model.Escrow) error {
+		now := time.Now()
+		escrow.Status = model.EscrowStatusDisputing
+		escrow.DisputeInitiatorID = currentUser.ID
+		escrow.DisputeReason = req.Reason
+		escrow.DisputedAt = &now
+		if err := tx.Model(escrow).Select("status", "dispute_initiator_id", "dispute_reason", "disputed_at").Updates(escrow).Error; err != nil {
+			return err
+		}
+		return syncOrderStatus(tx, escrow)
+	})
+}
+
+// ListDisputedEscrows 管理员查询担保交易列表，默认查询争议中的交易
+// @Tags admin
+// @Produce json
+// @Param request query ListEscrowsRequest true "查询参数"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/escrows [get]
+func ListDisputedEscrows(c *gin.Context) {
+	var req ListEscrowsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+	if req.Status == "" {
+		req.Status = string(model.EscrowStatusDisputing)
+	}
+
+	listEscrows(c, &req, func(tx *gorm.DB) *gorm.DB { return tx })
+}
+
+// ArbitrateEscrowRequest 管理员仲裁请求
+type ArbitrateEscrowRequest struct {
+	Result model.EscrowArbitrationResult `json:"result" binding:"required,oneof=release refund"`
+	Note   string                        `json:"note" binding:"max=500"`
+}
+
+// ArbitrateEscrow 管理员仲裁争议中的担保交易，放款给卖家或退款给买家
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path uint64 true "担保交易 ID"
+// @Param request body ArbitrateEscrowRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/escrows/{id}/arbitrate [post]
+func ArbitrateEscrow(c *gin.Context) {
+	var req ArbitrateEscrowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	var escrow model.Escrow
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+				Where("id = ?", c.Param("id")).
+				First(&escrow).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New(EscrowNotFound)
+				}
+				return err
+			}
+			if escrow.Status != model.EscrowStatusDisputing {
+				return errors.New(EscrowStatusInvalid)
+			}
+
+			escrow.ArbitratorUserID = &currentUser.ID
+			escrow.ArbitrationResult = req.Result
+			escrow.ArbitrationNote = req.Note
+			if err := tx.Model(&escrow).Select("arbitrator_user_id", "arbitration_result", "arbitration_note").Updates(&escrow).Error; err != nil {
+				return err
+			}
+
+			return settleEscrow(tx, &escrow, req.Result == model.EscrowArbitrationRelease)
+		},
+	); err != nil {
+		respondEscrowError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(escrow))
+}
+
+// escrowQuery 担保交易查询，附带买卖双方用户名
+func escrowQuery(tx *gorm.DB) *gorm.DB {
+	return tx.Model(&model.Escrow{}).
+		Select("escrows.*, buyer_user.username as buyer_username, seller_user.username as seller_username").
+		Joins("JOIN users as buyer_user ON escrows.buyer_user_id = buyer_user.id").
+		Joins("JOIN users as seller_user ON escrows.seller_user_id = seller_user.id")
+}
+
+// listEscrows 分页查询担保交易，scope 限定查询范围
+func listEscrows(c *gin.Context, req *ListEscrowsRequest, scope func(*gorm.DB) *gorm.DB) {
+	baseQuery := scope(escrowQuery(db.DB(c.Request.Context())))
+	if req.Status != "" {
+		baseQuery = baseQuery.Where("escrows.status = ?", model.EscrowStatus(req.Status))
+	}
+
+	var total int64
+	if err := baseQuery.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	response := &ListEscrowsResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := baseQuery.Order("escrows.created_at DESC").Offset(offset).Limit(req.PageSize).Find(&response.Escrows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// respondEscrowAction 锁定担保交易、执行操作并返回结果
+func respondEscrowAction(c *gin.Context, scope func(*gorm.DB) *gorm.DB, action escrowAction, apply func(*gorm.DB, *model.Escrow) error) {
+	var escrow *model.Escrow
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			var err error
+			if escrow, err = lockEscrow(tx, scope, c.Param("id"), action); err != nil {
+				return err
+			}
+			return apply(tx, escrow)
+		},
+	); err != nil {
+		respondEscrowError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(escrow))
+}
+
+// respondEscrowError 将担保交易错误映射为 HTTP 响应
+func respondEscrowError(c *gin.Context, err error) {
+	switch err.Error() {
+	case EscrowNotFound:
+		c.JSON(http.StatusNotFound, util.Err(EscrowNotFound))
+	case EscrowStatusInvalid:
+		c.JSON(http.StatusBadRequest, util.Err(EscrowStatusInvalid))
+	default:
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+	}
+}
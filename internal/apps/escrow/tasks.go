@@ -0,0 +1,121 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package escrow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/linux-do/pay/internal/config"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/logger"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/task"
+	"github.com/linux-do/pay/internal/task/schedule"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// HandleEscrowAutoConfirmDue 查询所有到达自动确认收货时间的担保交易并逐个下发放款任务
+func HandleEscrowAutoConfirmDue(ctx context.Context, t *asynq.Task) error {
+	pageSize := 200
+	lastID := uint64(0)
+	currentDelay := 0 * time.Second
+	now := time.Now()
+
+	for {
+		var escrows []model.Escrow
+		if err := db.DB(ctx).
+			Where("id > ? AND status = ? AND auto_confirm_at <= ?", lastID, model.EscrowStatusShipped, now).
+			Order("id ASC").
+			Limit(pageSize).
+			Find(&escrows).Error; err != nil {
+			logger.ErrorF(ctx, "查询待自动确认担保交易失败: %v", err)
+			return err
+		}
+
+		// 没有更多担保交易，退出循环
+		if len(escrows) == 0 {
+			break
+		}
+
+		for _, escrow := range escrows {
+			currentDelay += time.Duration(config.Config.Schedule.EscrowAutoConfirmDispatchIntervalSeconds) * time.Second
+
+			payload, _ := json.Marshal(map[string]interface{}{
+				"escrow_id": escrow.ID,
+			})
+
+			if _, errTask := schedule.AsynqClient.Enqueue(
+				asynq.NewTask(task.EscrowAutoConfirmSingleTask, payload),
+				asynq.ProcessIn(currentDelay),
+				asynq.MaxRetry(3),
+			); errTask != nil {
+				logger.ErrorF(ctx, "下发担保交易[ID:%d]自动确认任务失败: %v", escrow.ID, errTask)
+				return errTask
+			} else {
+				logger.InfoF(ctx, "下发担保交易[ID:%d]自动确认任务成功", escrow.ID)
+			}
+		}
+
+		lastID = escrows[len(escrows)-1].ID
+	}
+	return nil
+}
+
+// HandleEscrowAutoConfirmSingle 自动确认收货并放款给卖家，争议中的交易不会被自动确认
+func HandleEscrowAutoConfirmSingle(ctx context.Context, t *asynq.Task) error {
+	var payload struct {
+		EscrowID uint64 `json:"escrow_id"`
+	}
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("解析任务参数失败: %w", err)
+	}
+
+	if err := db.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		var escrow model.Escrow
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+			Where("id = ? AND status = ? AND auto_confirm_at <= ?", payload.EscrowID, model.EscrowStatusShipped, time.Now()).
+			First(&escrow).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				logger.InfoF(ctx, "担保交易[ID:%d]已被处理或未到自动确认时间，跳过", payload.EscrowID)
+				return nil
+			}
+			return err
+		}
+
+		return settleEscrow(tx, &escrow, true)
+	}); err != nil {
+		logger.ErrorF(ctx, "担保交易[ID:%d]自动确认失败: %v", payload.EscrowID, err)
+		return err
+	}
+
+	logger.InfoF(ctx, "担保交易[ID:%d]自动确认处理完成", payload.EscrowID)
+	return nil
+}
@@ -0,0 +1,133 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package escrow
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/pay/internal/apps/oauth"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// buyerScope 限定为当前用户作为买家的担保交易
+func buyerScope(c *gin.Context) func(*gorm.DB) *gorm.DB {
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+	return func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("escrows.buyer_user_id = ?", user.ID)
+	}
+}
+
+// sellerScope 限定为当前用户作为卖家的担保交易
+func sellerScope(c *gin.Context) func(*gorm.DB) *gorm.DB {
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+	return func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("escrows.seller_user_id = ?", user.ID)
+	}
+}
+
+// partyScope 限定为当前用户作为买家或卖家的担保交易
+func partyScope(c *gin.Context) func(*gorm.DB) *gorm.DB {
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+	return func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("escrows.buyer_user_id = ? OR escrows.seller_user_id = ?", user.ID, user.ID)
+	}
+}
+
+// lockEscrow 锁定担保交易并校验当前状态是否允许执行操作
+func lockEscrow(tx *gorm.DB, scope func(*gorm.DB) *gorm.DB, escrowID string, action escrowAction) (*model.Escrow, error) {
+	var escrow model.Escrow
+	if err := scope(tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"})).
+		Where("id = ?", escrowID).
+		First(&escrow).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New(EscrowNotFound)
+		}
+		return nil, err
+	}
+	if !slices.Contains(actionFromStatuses[action], escrow.Status) {
+		return nil, errors.New(EscrowStatusInvalid)
+	}
+	return &escrow, nil
+}
+
+// syncOrderStatus 将担保交易状态同步到关联订单
+func syncOrderStatus(tx *gorm.DB, escrow *model.Escrow) error {
+	updates := map[string]interface{}{"status": orderStatuses[escrow.Status]}
+	if escrow.Status == model.EscrowStatusCompleted {
+		updates["trade_time"] = escrow.CompletedAt
+	}
+	return tx.Model(&model.Order{}).Where("id = ?", escrow.OrderID).Updates(updates).Error
+}
+
+// settleEscrow 结算担保交易：release 为 true 时放款给卖家，否则退回买家可用余额
+func settleEscrow(tx *gorm.DB, escrow *model.Escrow, release bool) error {
+	buyerUpdates := map[string]interface{}{
+		"escrow_balance": gorm.Expr("escrow_balance - ?", escrow.Amount),
+	}
+	if release {
+		buyerUpdates["total_transfer"] = gorm.Expr("total_transfer + ?", escrow.Amount)
+	} else {
+		buyerUpdates["available_balance"] = gorm.Expr("available_balance + ?", escrow.Amount)
+	}
+
+	result := tx.Model(&model.User{}).
+		Where("id = ? AND escrow_balance >= ?", escrow.BuyerUserID, escrow.Amount).
+		UpdateColumns(buyerUpdates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New(EscrowBalanceMismatch)
+	}
+
+	now := time.Now()
+	if release {
+		if err := tx.Model(&model.User{}).
+			Where("id = ?", escrow.SellerUserID).
+			UpdateColumns(map[string]interface{}{
+				"available_balance": gorm.Expr("available_balance + ?", escrow.Amount),
+				"total_receive":     gorm.Expr("total_receive + ?", escrow.Amount),
+			}).Error; err != nil {
+			return err
+		}
+		escrow.Status = model.EscrowStatusCompleted
+		escrow.CompletedAt = &now
+	} else if escrow.Status == model.EscrowStatusHeld {
+		escrow.Status = model.EscrowStatusCancelled
+	} else {
+		escrow.Status = model.EscrowStatusRefunded
+	}
+
+	if err := tx.Model(escrow).Select("status", "completed_at").Updates(escrow).Error; err != nil {
+		return err
+	}
+	return syncOrderStatus(tx, escrow)
+}
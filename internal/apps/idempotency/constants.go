@@ -0,0 +1,47 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package idempotency
+
+import "time"
+
+const (
+	// HeaderKey 客户端携带的幂等键请求头
+	HeaderKey = "Idempotency-Key"
+	// HeaderReplayed 返回缓存响应时附带的响应头
+	HeaderReplayed = "Idempotent-Replayed"
+	// CacheKeyFormat 幂等记录缓存 key，参数依次为调用方范围、请求路由与幂等键的摘要
+	CacheKeyFormat = "idempotency:%s:%s"
+	// CacheTTL 幂等记录保留时间
+	CacheTTL = 24 * time.Hour
+	// ProcessingTTL 处理中标记的保留时间，超时后视为首次请求已中断，允许重试
+	ProcessingTTL = 2 * time.Minute
+	// maxKeyLength 幂等键最大长度
+	maxKeyLength = 255
+)
+
+const (
+	recordStatusProcessing = "processing"
+	recordStatusCompleted  = "completed"
+)
@@ -0,0 +1,208 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/pay/internal/apps/merchant"
+	"github.com/linux-do/pay/internal/apps/oauth"
+	"github.com/linux-do/pay/internal/apps/payment"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/logger"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/util"
+	"github.com/redis/go-redis/v9"
+)
+
+// record 幂等记录，处理中时仅包含 Status 与 Fingerprint
+type record struct {
+	Status      string `json:"status"`
+	Fingerprint string `json:"fingerprint"`
+	StatusCode  int    `json:"status_code,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// responseRecorder 在写出响应的同时记录响应内容
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotent 幂等中间件，需放在鉴权中间件之后
+// 请求携带 Idempotency-Key 时，同一调用方在同一路由上重复使用该 key 会直接返回首次请求的响应；
+// 首次请求返回 5xx 时不保存结果，允许客户端重试
+func Idempotent() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(HeaderKey)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, util.Err(IdempotencyKeyTooLong))
+			return
+		}
+
+		scope := callerScope(c)
+		if scope == "" {
+			c.Next()
+			return
+		}
+
+		body, errRead := io.ReadAll(c.Request.Body)
+		if errRead != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, util.Err(errRead.Error()))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		cacheKey := fmt.Sprintf(CacheKeyFormat, scope, digest(c.Request.Method, c.FullPath(), key))
+		fingerprint := digest(c.Request.URL.Path, string(body))
+
+		pending, _ := json.Marshal(record{Status: recordStatusProcessing, Fingerprint: fingerprint})
+		// 处理中标记仅短暂保留，进程异常退出时不会长期阻塞客户端重试
+		ok, errSet := db.Redis.SetNX(ctx, cacheKey, pending, ProcessingTTL).Result()
+		if errSet != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, util.Err(errSet.Error()))
+			return
+		}
+		if !ok {
+			replay(c, cacheKey, fingerprint)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		// 处理结束后更新幂等记录，handler panic 时同样执行；客户端断开不影响记录写入
+		defer func() {
+			saveCtx := context.WithoutCancel(ctx)
+
+			if recovered := recover(); recovered != nil {
+				if err := db.Redis.Del(saveCtx, cacheKey).Err(); err != nil {
+					logger.ErrorF(saveCtx, "删除幂等记录[%s]失败: %v", cacheKey, err)
+				}
+				panic(recovered)
+			}
+
+			if recorder.Status() >= http.StatusInternalServerError {
+				if err := db.Redis.Del(saveCtx, cacheKey).Err(); err != nil {
+					logger.ErrorF(saveCtx, "删除幂等记录[%s]失败: %v", cacheKey, err)
+				}
+				return
+			}
+
+			completed, _ := json.Marshal(record{
+				Status:      recordStatusCompleted,
+				Fingerprint: fingerprint,
+				StatusCode:  recorder.Status(),
+				ContentType: recorder.Header().Get("Content-Type"),
+				Body:        recorder.body.Bytes(),
+			})
+			if err := db.Redis.Set(saveCtx, cacheKey, completed, CacheTTL).Err(); err != nil {
+				logger.ErrorF(saveCtx, "保存幂等记录[%s]失败: %v", cacheKey, err)
+			}
+		}()
+
+		c.Next()
+	}
+}
+
+// replay 返回已保存的响应；首次请求仍在处理或请求内容不同时返回错误
+func replay(c *gin.Context, cacheKey, fingerprint string) {
+	data, err := db.Redis.Get(c.Request.Context(), cacheKey).Bytes()
+	if err != nil {
+		// 首次请求在读取前失败并删除了记录
+		if errors.Is(err, redis.Nil) {
+			c.AbortWithStatusJSON(http.StatusConflict, util.Err(IdempotencyKeyInProgress))
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	var saved record
+	if err := json.Unmarshal(data, &saved); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	switch {
+	case saved.Fingerprint != fingerprint:
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, util.Err(IdempotencyKeyReused))
+	case saved.Status == recordStatusProcessing:
+		c.AbortWithStatusJSON(http.StatusConflict, util.Err(IdempotencyKeyInProgress))
+	default:
+		c.Header(HeaderReplayed, "true")
+		c.Data(saved.StatusCode, saved.ContentType, saved.Body)
+		c.Abort()
+	}
+}
+
+// callerScope 返回幂等键的调用方范围：登录用户或商户 API Key，均不存在时不启用幂等
+func callerScope(c *gin.Context) string {
+	if user, ok := util.GetFromContext[*model.User](c, oauth.UserObjKey); ok {
+		return fmt.Sprintf("user:%d", user.ID)
+	}
+	if apiKey, ok := util.GetFromContext[*model.MerchantAPIKey](c, payment.APIKeyObjKey); ok {
+		return "client:" + apiKey.ClientID
+	}
+	if apiKey, ok := util.GetFromContext[*model.MerchantAPIKey](c, merchant.APIKeyObjKey); ok {
+		return "client:" + apiKey.ClientID
+	}
+	return ""
+}
+
+// digest 计算参数拼接后的 SHA-256 摘要
+func digest(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
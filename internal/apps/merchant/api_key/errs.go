@@ -25,6 +25,7 @@
 package api_key
 
 const (
-	APIKeyNotFound   = "API Key 不存在"
-	NoFieldsToUpdate = "没有需要更新的字段"
+	APIKeyNotFound    = "API Key 不存在"
+	NoFieldsToUpdate  = "没有需要更新的字段"
+	MinAmountAboveMax = "单笔最低金额不能大于单笔最高金额"
 )
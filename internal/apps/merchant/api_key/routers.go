@@ -25,14 +25,19 @@
 package api_key
 
 import (
+	"errors"
 	"net/http"
+	"slices"
 
 	"github.com/gin-gonic/gin"
 	"github.com/linux-do/pay/internal/apps/merchant"
 	"github.com/linux-do/pay/internal/apps/oauth"
+	"github.com/linux-do/pay/internal/common"
 	"github.com/linux-do/pay/internal/db"
 	"github.com/linux-do/pay/internal/model"
 	"github.com/linux-do/pay/internal/util"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
 )
 
 type CreateAPIKeyRequest struct {
@@ -41,6 +46,13 @@ type CreateAPIKeyRequest struct {
 	AppDescription string `json:"app_description" binding:"max=100"`
 	RedirectURI    string `json:"redirect_uri" binding:"omitempty,max=100,url"`
 	NotifyURL      string `json:"notify_url" binding:"required,max=100,url"`
+	RequireNonce   bool   `json:"require_nonce"`
+	// 金额限制，不传或传 0 表示不限制
+	MinOrderAmount    *decimal.Decimal `json:"min_order_amount"`
+	MaxOrderAmount    *decimal.Decimal `json:"max_order_amount"`
+	DailyReceiveLimit *decimal.Decimal `json:"daily_receive_limit"`
+	// 权限范围，如 payout:write
+	Scopes []string `json:"scopes" binding:"omitempty,max=10,dive,oneof=payout:write"`
 }
 
 type UpdateAPIKeyRequest struct {
@@ -49,6 +61,13 @@ type UpdateAPIKeyRequest struct {
 	AppDescription string `json:"app_description" binding:"omitempty,max=100"`
 	RedirectURI    string `json:"redirect_uri" binding:"omitempty,max=100,url"`
 	NotifyURL      string `json:"notify_url" binding:"omitempty,max=100,url"`
+	RequireNonce   *bool  `json:"require_nonce"`
+	// 金额限制，传 0 表示取消限制
+	MinOrderAmount    *decimal.Decimal `json:"min_order_amount"`
+	MaxOrderAmount    *decimal.Decimal `json:"max_order_amount"`
+	DailyReceiveLimit *decimal.Decimal `json:"daily_receive_limit"`
+	// 权限范围，传空数组表示清空
+	Scopes []string `json:"scopes" binding:"omitempty,max=10,dive,oneof=payout:write"`
 }
 
 type APIKeyListResponse struct {
@@ -70,17 +89,42 @@ func CreateAPIKey(c *gin.Context) {
 		return
 	}
 
+	minAmount, errMin := normalizeAmountLimit(req.MinOrderAmount)
+	if errMin != nil {
+		c.JSON(http.StatusBadRequest, util.Err(errMin.Error()))
+		return
+	}
+	maxAmount, errMax := normalizeAmountLimit(req.MaxOrderAmount)
+	if errMax != nil {
+		c.JSON(http.StatusBadRequest, util.Err(errMax.Error()))
+		return
+	}
+	dailyLimit, errDaily := normalizeAmountLimit(req.DailyReceiveLimit)
+	if errDaily != nil {
+		c.JSON(http.StatusBadRequest, util.Err(errDaily.Error()))
+		return
+	}
+	if minAmount != nil && maxAmount != nil && minAmount.GreaterThan(*maxAmount) {
+		c.JSON(http.StatusBadRequest, util.Err(MinAmountAboveMax))
+		return
+	}
+
 	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
 
 	apiKey := model.MerchantAPIKey{
-		UserID:         user.ID,
-		ClientID:       util.GenerateUniqueIDSimple(),
-		ClientSecret:   util.GenerateUniqueIDSimple(),
-		AppName:        req.AppName,
-		AppHomepageURL: req.AppHomepageURL,
-		AppDescription: req.AppDescription,
-		RedirectURI:    req.RedirectURI,
-		NotifyURL:      req.NotifyURL,
+		UserID:            user.ID,
+		ClientID:          util.GenerateUniqueIDSimple(),
+		ClientSecret:      util.GenerateUniqueIDSimple(),
+		AppName:           req.AppName,
+		AppHomepageURL:    req.AppHomepageURL,
+		AppDescription:    req.AppDescription,
+		RedirectURI:       req.RedirectURI,
+		NotifyURL:         req.NotifyURL,
+		RequireNonce:      req.RequireNonce,
+		MinOrderAmount:    minAmount,
+		MaxOrderAmount:    maxAmount,
+		DailyReceiveLimit: dailyLimit,
+		Scopes:            normalizeScopes(req.Scopes),
 	}
 
 	if err := db.DB(c.Request.Context()).Create(&apiKey).Error; err != nil {
@@ -155,6 +199,45 @@ func UpdateAPIKey(c *gin.Context) {
 	if req.NotifyURL != "" {
 		updates["notify_url"] = req.NotifyURL
 	}
+	if req.RequireNonce != nil {
+		updates["require_nonce"] = *req.RequireNonce
+	}
+
+	// 金额限制：未传的字段保持原值，用合并后的值校验最低/最高金额
+	minAmount, maxAmount := apiKey.MinOrderAmount, apiKey.MaxOrderAmount
+	if req.MinOrderAmount != nil {
+		limit, err := normalizeAmountLimit(req.MinOrderAmount)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+			return
+		}
+		updates["min_order_amount"] = amountLimitColumn(limit)
+		minAmount = limit
+	}
+	if req.MaxOrderAmount != nil {
+		limit, err := normalizeAmountLimit(req.MaxOrderAmount)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+			return
+		}
+		updates["max_order_amount"] = amountLimitColumn(limit)
+		maxAmount = limit
+	}
+	if req.DailyReceiveLimit != nil {
+		limit, err := normalizeAmountLimit(req.DailyReceiveLimit)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+			return
+		}
+		updates["daily_receive_limit"] = amountLimitColumn(limit)
+	}
+	if minAmount != nil && maxAmount != nil && minAmount.GreaterThan(*maxAmount) {
+		c.JSON(http.StatusBadRequest, util.Err(MinAmountAboveMax))
+		return
+	}
+	if req.Scopes != nil {
+		updates["scopes"] = normalizeScopes(req.Scopes)
+	}
 
 	if len(updates) == 0 {
 		c.JSON(http.StatusBadRequest, util.Err(NoFieldsToUpdate))
@@ -171,6 +254,39 @@ func UpdateAPIKey(c *gin.Context) {
 	c.JSON(http.StatusOK, util.OKNil())
 }
 
+// normalizeAmountLimit 校验金额限制，nil 或 0 表示不限制
+func normalizeAmountLimit(amount *decimal.Decimal) (*decimal.Decimal, error) {
+	if amount == nil || amount.IsZero() {
+		return nil, nil
+	}
+	if amount.LessThan(decimal.Zero) {
+		return nil, errors.New(common.AmountMustBeGreaterThanZero)
+	}
+	if amount.Exponent() < -2 {
+		return nil, errors.New(common.AmountDecimalPlacesExceeded)
+	}
+	return amount, nil
+}
+
+// normalizeScopes 去重权限范围，保持传入顺序
+func normalizeScopes(scopes []string) util.StringArray {
+	result := make(util.StringArray, 0, len(scopes))
+	for _, scope := range scopes {
+		if !slices.Contains(result, scope) {
+			result = append(result, scope)
+		}
+	}
+	return result
+}
+
+// amountLimitColumn 将金额限制转换为更新值，nil 表示清空限制
+func amountLimitColumn(limit *decimal.Decimal) interface{} {
+	if limit == nil {
+		return gorm.Expr("NULL")
+	}
+	return *limit
+}
+
 // DeleteAPIKey 删除商户 API Key
 // @Tags merchant
 // @Produce json
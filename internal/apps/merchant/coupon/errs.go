@@ -0,0 +1,36 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package coupon
+
+const (
+	CouponNotFound            = "优惠券不存在"
+	CouponCodeExists          = "该应用下优惠码已存在"
+	DiscountPercentageInvalid = "百分比折扣须大于 0 且不超过 100"
+	MinSpendInvalid           = "最低消费金额必须大于0且最多2位小数"
+	CouponLinksRequired       = "限定支付链接时至少需要指定一个支付链接"
+	CouponLinksInvalid        = "支付链接不存在或不属于该应用"
+	EndsAtBeforeStartsAt      = "结束时间必须晚于开始时间"
+	EndsAtMustBeFuture        = "结束时间必须晚于当前时间"
+)
@@ -0,0 +1,286 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package coupon
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/pay/internal/apps/merchant"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/service"
+	"github.com/linux-do/pay/internal/util"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// CouponRulesRequest 优惠规则，scope 为 links 时仅对 payment_link_ids 中的支付链接生效，可选限制为空表示不限制
+type CouponRulesRequest struct {
+	DiscountType   model.CouponDiscountType `json:"discount_type" binding:"required,oneof=percentage fixed"`
+	DiscountValue  decimal.Decimal          `json:"discount_value" binding:"required"`
+	MinSpend       *decimal.Decimal         `json:"min_spend"`
+	Scope          model.CouponScope        `json:"scope" binding:"omitempty,oneof=all links"`
+	PaymentLinkIDs []uint64                 `json:"payment_link_ids" binding:"omitempty,max=50,unique"`
+	MaxUses        *int64                   `json:"max_uses" binding:"omitempty,min=1"`
+	PerUserLimit   *int64                   `json:"per_user_limit" binding:"omitempty,min=1"`
+	StartsAt       *time.Time               `json:"starts_at"`
+	EndsAt         *time.Time               `json:"ends_at"`
+}
+
+// CreateCouponRequest 创建优惠券请求，优惠码不区分大小写
+type CreateCouponRequest struct {
+	Code string `json:"code" binding:"required,min=3,max=32,alphanum"`
+	CouponRulesRequest
+}
+
+// UpdateCouponRequest 更新优惠券请求，优惠码创建后不可修改
+type UpdateCouponRequest struct {
+	CouponRulesRequest
+	IsActive *bool `json:"is_active" binding:"required"`
+}
+
+// ListCouponRedemptionsRequest 查询优惠券核销记录请求
+type ListCouponRedemptionsRequest struct {
+	Page     int `form:"page" binding:"min=1"`
+	PageSize int `form:"page_size" binding:"min=1,max=100"`
+}
+
+// ListCouponRedemptionsResponse 优惠券核销记录
+type ListCouponRedemptionsResponse struct {
+	Total         int64                            `json:"total"`
+	Page          int                              `json:"page"`
+	PageSize      int                              `json:"page_size"`
+	UsedCount     int64                            `json:"used_count"`
+	TotalDiscount decimal.Decimal                  `json:"total_discount"`
+	Redemptions   []model.MerchantCouponRedemption `json:"redemptions"`
+}
+
+// CreateCoupon 创建优惠券
+// @Tags merchant
+// @Accept json
+// @Produce json
+// @Param id path uint64 true "API Key ID"
+// @Param request body CreateCouponRequest true "创建优惠券请求"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/merchant/api-keys/{id}/coupons [post]
+func CreateCoupon(c *gin.Context) {
+	var req CreateCouponRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, merchant.APIKeyObjKey)
+
+	if err := req.validate(db.DB(c.Request.Context()), apiKey.ID); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	coupon := model.MerchantCoupon{
+		MerchantAPIKeyID: apiKey.ID,
+		Code:             service.NormalizeCouponCode(req.Code),
+		IsActive:         true,
+	}
+	req.applyTo(&coupon)
+
+	var exists int64
+	if err := db.DB(c.Request.Context()).
+		Model(&model.MerchantCoupon{}).
+		Where("merchant_api_key_id = ? AND code = ?", apiKey.ID, coupon.Code).
+		Count(&exists).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+	if exists > 0 {
+		c.JSON(http.StatusBadRequest, util.Err(CouponCodeExists))
+		return
+	}
+
+	if err := db.DB(c.Request.Context()).Create(&coupon).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(coupon))
+}
+
+// ListCoupons 获取商户应用的优惠券列表
+// @Tags merchant
+// @Produce json
+// @Param id path uint64 true "API Key ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/merchant/api-keys/{id}/coupons [get]
+func ListCoupons(c *gin.Context) {
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, merchant.APIKeyObjKey)
+
+	coupons := make([]model.MerchantCoupon, 0)
+	if err := db.DB(c.Request.Context()).
+		Where("merchant_api_key_id = ?", apiKey.ID).
+		Order("created_at DESC").
+		Find(&coupons).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(coupons))
+}
+
+// UpdateCoupon 更新优惠券规则与启用状态，已核销的记录不受影响
+// @Tags merchant
+// @Accept json
+// @Produce json
+// @Param id path uint64 true "API Key ID"
+// @Param couponId path uint64 true "优惠券 ID"
+// @Param request body UpdateCouponRequest true "更新优惠券请求"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/merchant/api-keys/{id}/coupons/{couponId} [put]
+func UpdateCoupon(c *gin.Context) {
+	var req UpdateCouponRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, merchant.APIKeyObjKey)
+
+	if err := req.validate(db.DB(c.Request.Context()), apiKey.ID); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	var coupon model.MerchantCoupon
+	req.applyTo(&coupon)
+	coupon.IsActive = *req.IsActive
+
+	result := db.DB(c.Request.Context()).
+		Model(&model.MerchantCoupon{}).
+		Where("id = ? AND merchant_api_key_id = ?", c.Param("couponId"), apiKey.ID).
+		Select("discount_type", "discount_value", "min_spend", "scope", "payment_link_ids",
+			"max_uses", "per_user_limit", "starts_at", "ends_at", "is_active").
+		Updates(&coupon)
+
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(result.Error.Error()))
+		return
+	}
+
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, util.Err(CouponNotFound))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
+
+// DeleteCoupon 删除优惠券
+// @Tags merchant
+// @Produce json
+// @Param id path uint64 true "API Key ID"
+// @Param couponId path uint64 true "优惠券 ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/merchant/api-keys/{id}/coupons/{couponId} [delete]
+func DeleteCoupon(c *gin.Context) {
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, merchant.APIKeyObjKey)
+
+	result := db.DB(c.Request.Context()).
+		Where("id = ? AND merchant_api_key_id = ?", c.Param("couponId"), apiKey.ID).
+		Delete(&model.MerchantCoupon{})
+
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(result.Error.Error()))
+		return
+	}
+
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, util.Err(CouponNotFound))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
+
+// ListCouponRedemptions 分页查询优惠券核销记录及累计减免
+// @Tags merchant
+// @Produce json
+// @Param id path uint64 true "API Key ID"
+// @Param couponId path uint64 true "优惠券 ID"
+// @Param request query ListCouponRedemptionsRequest true "查询参数"
+// @Success 200 {object} ListCouponRedemptionsResponse
+// @Router /api/v1/merchant/api-keys/{id}/coupons/{couponId}/redemptions [get]
+func ListCouponRedemptions(c *gin.Context) {
+	var req ListCouponRedemptionsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, merchant.APIKeyObjKey)
+
+	var coupon model.MerchantCoupon
+	if err := db.DB(c.Request.Context()).
+		Unscoped().
+		Where("id = ? AND merchant_api_key_id = ?", c.Param("couponId"), apiKey.ID).
+		First(&coupon).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(CouponNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	response := ListCouponRedemptionsResponse{
+		Page:          req.Page,
+		PageSize:      req.PageSize,
+		UsedCount:     coupon.UsedCount,
+		TotalDiscount: coupon.TotalDiscount,
+		Redemptions:   make([]model.MerchantCouponRedemption, 0),
+	}
+
+	query := db.DB(c.Request.Context()).
+		Model(&model.MerchantCouponRedemption{}).
+		Where("merchant_coupon_redemptions.coupon_id = ?", coupon.ID)
+	if err := query.Count(&response.Total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	if err := query.
+		Select("merchant_coupon_redemptions.*, users.username").
+		Joins("LEFT JOIN users ON users.id = merchant_coupon_redemptions.user_id").
+		Order("merchant_coupon_redemptions.id DESC").
+		Offset((req.Page - 1) * req.PageSize).
+		Limit(req.PageSize).
+		Find(&response.Redemptions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
@@ -0,0 +1,94 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package coupon
+
+import (
+	"errors"
+	"time"
+
+	"github.com/linux-do/pay/internal/common"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/util"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// validate 校验优惠规则，限定支付链接时链接必须属于当前商户应用
+func (r *CouponRulesRequest) validate(tx *gorm.DB, apiKeyID uint64) error {
+	if r.DiscountValue.LessThanOrEqual(decimal.Zero) {
+		return errors.New(common.AmountMustBeGreaterThanZero)
+	}
+	if r.DiscountValue.Exponent() < -2 {
+		return errors.New(common.AmountDecimalPlacesExceeded)
+	}
+	if r.DiscountType == model.CouponDiscountTypePercentage && r.DiscountValue.GreaterThan(decimal.NewFromInt(100)) {
+		return errors.New(DiscountPercentageInvalid)
+	}
+	if r.MinSpend != nil && (r.MinSpend.LessThanOrEqual(decimal.Zero) || r.MinSpend.Exponent() < -2) {
+		return errors.New(MinSpendInvalid)
+	}
+
+	if r.StartsAt != nil && r.EndsAt != nil && !r.EndsAt.After(*r.StartsAt) {
+		return errors.New(EndsAtBeforeStartsAt)
+	}
+	if r.EndsAt != nil && !r.EndsAt.After(time.Now()) {
+		return errors.New(EndsAtMustBeFuture)
+	}
+
+	if r.Scope == "" {
+		r.Scope = model.CouponScopeAll
+	}
+	if r.Scope == model.CouponScopeAll {
+		r.PaymentLinkIDs = nil
+		return nil
+	}
+
+	if len(r.PaymentLinkIDs) == 0 {
+		return errors.New(CouponLinksRequired)
+	}
+	var count int64
+	if err := tx.Model(&model.MerchantPaymentLink{}).
+		Where("id IN ? AND merchant_api_key_id = ?", r.PaymentLinkIDs, apiKeyID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count != int64(len(r.PaymentLinkIDs)) {
+		return errors.New(CouponLinksInvalid)
+	}
+	return nil
+}
+
+// applyTo 将优惠规则写入优惠券
+func (r *CouponRulesRequest) applyTo(coupon *model.MerchantCoupon) {
+	coupon.DiscountType = r.DiscountType
+	coupon.DiscountValue = r.DiscountValue
+	coupon.MinSpend = r.MinSpend
+	coupon.Scope = r.Scope
+	coupon.PaymentLinkIDs = util.Uint64Array(r.PaymentLinkIDs)
+	coupon.MaxUses = r.MaxUses
+	coupon.PerUserLimit = r.PerUserLimit
+	coupon.StartsAt = r.StartsAt
+	coupon.EndsAt = r.EndsAt
+}
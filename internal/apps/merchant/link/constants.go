@@ -0,0 +1,40 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package link
+
+import "time"
+
+const (
+	// LinkViewKeyFormat Redis key 格式，用于按天对支付链接浏览去重，key中包含链接ID、日期和用户ID
+	LinkViewKeyFormat = "payment:link:view:%d:%s:%d"
+	// LinkViewDedupTTL 浏览去重 key 的保留时间，覆盖当天剩余时间即可
+	LinkViewDedupTTL = 25 * time.Hour
+	// LinkStatsDefaultDays 统计接口默认天数
+	LinkStatsDefaultDays = 30
+	// statsTimezone 浏览和订单按天分桶使用的时区，与定时任务调度器保持一致
+	statsTimezone = "Asia/Shanghai"
+	// OutTradeNoPrefix 未传入商户订单号时自动生成的前缀
+	OutTradeNoPrefix = "link"
+)
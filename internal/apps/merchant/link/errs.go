@@ -25,5 +25,18 @@
 package link
 
 const (
-	PaymentLinkNotFound = "支付链接不存在"
+	PaymentLinkNotFound        = "支付链接不存在"
+	PaymentLinkInactive        = "支付链接已暂停"
+	PaymentLinkExpired         = "支付链接已过期"
+	PaymentLinkUsageExceeded   = "支付链接已达到最大使用次数"
+	PaymentLinkSoldOut         = "商品已售罄"
+	PaymentLinkPerUserExceeded = "已达到该支付链接的个人购买上限"
+	ExpiresAtMustBeFuture      = "过期时间必须晚于当前时间"
+	AmountTiersRequired        = "档位模式至少需要一个金额档位"
+	MinAmountAboveMax          = "最低金额不能高于最高金额"
+	PayAmountRequired          = "请输入支付金额"
+	PayAmountBelowMinimum      = "支付金额低于该链接的最低金额"
+	PayAmountAboveMaximum      = "支付金额超过该链接的最高金额"
+	PayAmountNotInTiers        = "支付金额不在可选档位中"
+	MaxUsesBelowUsedCount      = "最大使用次数不能小于已使用次数"
 )
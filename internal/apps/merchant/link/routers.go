@@ -29,11 +29,13 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/linux-do/pay/internal/apps/merchant"
 	"github.com/linux-do/pay/internal/apps/oauth"
+	"github.com/linux-do/pay/internal/apps/payment"
 	"github.com/linux-do/pay/internal/common"
 	"github.com/linux-do/pay/internal/db"
 	"github.com/linux-do/pay/internal/model"
@@ -41,20 +43,48 @@ import (
 	"github.com/linux-do/pay/internal/util"
 	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // PayByLinkRequest 通过支付链接支付请求
 type PayByLinkRequest struct {
-	Token  string `json:"token" binding:"required"`
-	PayKey string `json:"pay_key" binding:"required,max=6"`
-	Remark string `json:"remark" binding:"max=100"`
+	Token      string           `json:"token" binding:"required"`
+	Amount     *decimal.Decimal `json:"amount"`
+	PayKey     string           `json:"pay_key" binding:"max=6"`
+	Remark     string           `json:"remark" binding:"max=100"`
+	OutTradeNo string           `json:"out_trade_no" binding:"max=64"`
+	CouponCode string           `json:"coupon_code" binding:"max=32"`
+}
+
+// PayByLinkResponse 通过支付链接支付响应
+type PayByLinkResponse struct {
+	TradeNo    string          `json:"trade_no"`
+	OutTradeNo string          `json:"out_trade_no"`
+	LinkID     uint64          `json:"link_id"`
+	Amount     decimal.Decimal `json:"amount"`
 }
 
 // CreatePaymentLinkRequest 创建支付链接请求
 type CreatePaymentLinkRequest struct {
-	Amount      decimal.Decimal `json:"amount" binding:"required"`
-	ProductName string          `json:"product_name" binding:"required,max=30"`
-	Remark      string          `json:"remark" binding:"max=100"`
+	AmountMode   model.PaymentLinkAmountMode `json:"amount_mode" binding:"omitempty,oneof=fixed open tiers"`
+	Amount       decimal.Decimal             `json:"amount"`
+	MinAmount    *decimal.Decimal            `json:"min_amount"`
+	MaxAmount    *decimal.Decimal            `json:"max_amount"`
+	AmountTiers  []decimal.Decimal           `json:"amount_tiers" binding:"omitempty,max=10"`
+	GoalAmount   *decimal.Decimal            `json:"goal_amount"`
+	ProductName  string                      `json:"product_name" binding:"required,max=30"`
+	Remark       string                      `json:"remark" binding:"max=100"`
+	ExpiresAt    *time.Time                  `json:"expires_at"`
+	MaxUses      *int64                      `json:"max_uses" binding:"omitempty,min=1"`
+	PerUserLimit *int64                      `json:"per_user_limit" binding:"omitempty,min=1"`
+	Stock        *int64                      `json:"stock" binding:"omitempty,min=0"`
+}
+
+// UpdatePaymentLinkRequest 更新支付链接请求，未传的使用限制保持原值，需取消的限制通过 clear_limits 指定
+type UpdatePaymentLinkRequest struct {
+	CreatePaymentLinkRequest
+	IsActive    *bool    `json:"is_active" binding:"required"`
+	ClearLimits []string `json:"clear_limits" binding:"omitempty,dive,oneof=max_uses per_user_limit stock"`
 }
 
 // CreatePaymentLink 创建支付链接
@@ -72,13 +102,8 @@ func CreatePaymentLink(c *gin.Context) {
 		return
 	}
 
-	// 验证金额
-	if req.Amount.LessThanOrEqual(decimal.Zero) {
-		c.JSON(http.StatusBadRequest, util.Err(common.AmountMustBeGreaterThanZero))
-		return
-	}
-	if req.Amount.Exponent() < -2 {
-		c.JSON(http.StatusBadRequest, util.Err(common.AmountDecimalPlacesExceeded))
+	if err := req.validate(); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
 		return
 	}
 
@@ -87,9 +112,19 @@ func CreatePaymentLink(c *gin.Context) {
 	paymentLink := model.MerchantPaymentLink{
 		MerchantAPIKeyID: apiKey.ID,
 		Token:            util.GenerateUniqueIDSimple(),
+		AmountMode:       req.AmountMode,
 		Amount:           req.Amount,
+		MinAmount:        req.MinAmount,
+		MaxAmount:        req.MaxAmount,
+		AmountTiers:      req.AmountTiers,
+		GoalAmount:       req.GoalAmount,
 		ProductName:      req.ProductName,
 		Remark:           req.Remark,
+		IsActive:         true,
+		ExpiresAt:        req.ExpiresAt,
+		MaxUses:          req.MaxUses,
+		PerUserLimit:     req.PerUserLimit,
+		Stock:            req.Stock,
 	}
 
 	if err := db.DB(c.Request.Context()).Create(&paymentLink).Error; err != nil {
@@ -100,15 +135,108 @@ func CreatePaymentLink(c *gin.Context) {
 	c.JSON(http.StatusOK, util.OK(paymentLink))
 }
 
+// UpdatePaymentLink 更新支付链接
+// @Tags merchant
+// @Accept json
+// @Produce json
+// @Param id path uint64 true "API Key ID"
+// @Param linkId path uint64 true "Payment Link ID"
+// @Param request body UpdatePaymentLinkRequest true "更新支付链接请求"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/merchant/api-keys/{id}/payment-links/{linkId} [put]
+func UpdatePaymentLink(c *gin.Context) {
+	var req UpdatePaymentLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	if err := req.validate(); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, merchant.APIKeyObjKey)
+
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			// 锁定支付链接，避免与支付时的使用次数和库存扣减并发
+			var paymentLink model.MerchantPaymentLink
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("id = ? AND merchant_api_key_id = ?", c.Param("linkId"), apiKey.ID).
+				First(&paymentLink).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New(PaymentLinkNotFound)
+				}
+				return err
+			}
+
+			req.applyLimits(&paymentLink)
+			if paymentLink.MaxUses != nil && *paymentLink.MaxUses < paymentLink.UsedCount {
+				return errors.New(MaxUsesBelowUsedCount)
+			}
+
+			return tx.Model(&paymentLink).
+				Select("amount_mode", "amount", "min_amount", "max_amount", "amount_tiers", "goal_amount",
+					"product_name", "remark", "is_active", "expires_at", "max_uses", "per_user_limit", "stock").
+				Updates(model.MerchantPaymentLink{
+					AmountMode:   req.AmountMode,
+					Amount:       req.Amount,
+					MinAmount:    req.MinAmount,
+					MaxAmount:    req.MaxAmount,
+					AmountTiers:  req.AmountTiers,
+					GoalAmount:   req.GoalAmount,
+					ProductName:  req.ProductName,
+					Remark:       req.Remark,
+					IsActive:     *req.IsActive,
+					ExpiresAt:    req.ExpiresAt,
+					MaxUses:      paymentLink.MaxUses,
+					PerUserLimit: paymentLink.PerUserLimit,
+					Stock:        paymentLink.Stock,
+				}).Error
+		},
+	); err != nil {
+		switch err.Error() {
+		case PaymentLinkNotFound:
+			c.JSON(http.StatusNotFound, util.Err(PaymentLinkNotFound))
+		case MaxUsesBelowUsedCount:
+			c.JSON(http.StatusBadRequest, util.Err(MaxUsesBelowUsedCount))
+		default:
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
+
 // PaymentLinkDetail 支付链接详情
 type PaymentLinkDetail struct {
-	ID          uint64          `json:"id"`
-	Token       string          `json:"token"`
-	Amount      decimal.Decimal `json:"amount"`
-	ProductName string          `json:"product_name"`
-	Remark      string          `json:"remark"`
-	CreatedAt   time.Time       `json:"created_at"`
-	AppName     string          `json:"app_name"`
+	ID           uint64                      `json:"id"`
+	Token        string                      `json:"token"`
+	AmountMode   model.PaymentLinkAmountMode `json:"amount_mode"`
+	Amount       decimal.Decimal             `json:"amount"`
+	MinAmount    *decimal.Decimal            `json:"min_amount"`
+	MaxAmount    *decimal.Decimal            `json:"max_amount"`
+	AmountTiers  util.DecimalArray           `json:"amount_tiers" swaggertype:"array,number"`
+	GoalAmount   *decimal.Decimal            `json:"goal_amount"`
+	ProductName  string                      `json:"product_name"`
+	Remark       string                      `json:"remark"`
+	IsActive     bool                        `json:"is_active"`
+	ExpiresAt    *time.Time                  `json:"expires_at"`
+	MaxUses      *int64                      `json:"max_uses"`
+	PerUserLimit *int64                      `json:"per_user_limit"`
+	Stock        *int64                      `json:"stock"`
+	UsedCount    int64                       `json:"used_count"`
+	CreatedAt    time.Time                   `json:"created_at"`
+	AppName      string                      `json:"app_name"`
+	Progress     *PaymentLinkProgress        `json:"progress,omitempty" gorm:"-"`
+}
+
+// PaymentLinkProgress 募集目标进度
+type PaymentLinkProgress struct {
+	RaisedAmount decimal.Decimal `json:"raised_amount"`
+	PayerCount   int64           `json:"payer_count"`
 }
 
 // ListPaymentLinks 获取支付链接列表
@@ -123,7 +251,7 @@ func ListPaymentLinks(c *gin.Context) {
 	var paymentLinks []PaymentLinkDetail
 	if err := db.DB(c.Request.Context()).
 		Table("merchant_payment_links").
-		Select("merchant_payment_links.id, merchant_payment_links.token, merchant_payment_links.amount, merchant_payment_links.product_name, merchant_payment_links.remark, merchant_payment_links.created_at, merchant_api_keys.app_name").
+		Select(paymentLinkDetailColumns).
 		Joins("JOIN merchant_api_keys ON merchant_api_keys.id = merchant_payment_links.merchant_api_key_id").
 		Where("merchant_payment_links.merchant_api_key_id = ? AND merchant_payment_links.deleted_at IS NULL", apiKey.ID).
 		Order("merchant_payment_links.created_at DESC").
@@ -145,7 +273,7 @@ func GetPaymentLinkByToken(c *gin.Context) {
 	var paymentLink PaymentLinkDetail
 	if err := db.DB(c.Request.Context()).
 		Table("merchant_payment_links").
-		Select("merchant_payment_links.id, merchant_payment_links.token, merchant_payment_links.amount, merchant_payment_links.product_name, merchant_payment_links.remark, merchant_payment_links.created_at, merchant_api_keys.app_name").
+		Select(paymentLinkDetailColumns).
 		Joins("JOIN merchant_api_keys ON merchant_api_keys.id = merchant_payment_links.merchant_api_key_id").
 		Where("merchant_payment_links.token = ? AND merchant_payment_links.deleted_at IS NULL", c.Param("token")).
 		First(&paymentLink).Error; err != nil {
@@ -157,9 +285,158 @@ func GetPaymentLinkByToken(c *gin.Context) {
 		return
 	}
 
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+	recordPaymentLinkView(c.Request.Context(), paymentLink.ID, currentUser.ID)
+
+	// 设置了募集目标时返回进度
+	if paymentLink.GoalAmount != nil {
+		var progress PaymentLinkProgress
+		if err := db.DB(c.Request.Context()).
+			Model(&model.Order{}).
+			Select("COALESCE(SUM(amount), 0) AS raised_amount, COUNT(DISTINCT payer_user_id) AS payer_count").
+			Where("merchant_payment_link_id = ? AND status IN ?", paymentLink.ID, paidLinkOrderStatuses).
+			Scan(&progress).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+			return
+		}
+		paymentLink.Progress = &progress
+	}
+
 	c.JSON(http.StatusOK, util.OK(paymentLink))
 }
 
+// GetPaymentLinkQRCode 获取支付链接二维码
+// @Tags merchant
+// @Produce png
+// @Produce image/svg+xml
+// @Param token path string true "支付链接 Token"
+// @Param request query util.QRCodeRequest false "二维码参数"
+// @Success 200 {file} binary
+// @Router /api/v1/merchant/payment-links/{token}/qrcode [get]
+func GetPaymentLinkQRCode(c *gin.Context) {
+	var req util.QRCodeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	paymentLink, _ := util.GetFromContext[*model.MerchantPaymentLink](c, merchant.PaymentLinkObjKey)
+	if err := checkPaymentLinkAvailable(paymentLink); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	util.RenderQRCode(c, paymentLinkURL(paymentLink.Token), &req)
+}
+
+// PayButtonRequest 支付按钮参数
+type PayButtonRequest struct {
+	Text string `form:"text" binding:"max=20"`
+}
+
+// GetPaymentLinkButton 获取可嵌入网页的支付按钮 HTML 片段
+// @Tags merchant
+// @Produce html
+// @Param token path string true "支付链接 Token"
+// @Param request query PayButtonRequest false "按钮参数"
+// @Success 200 {string} string
+// @Router /api/v1/merchant/payment-links/{token}/button [get]
+func GetPaymentLinkButton(c *gin.Context) {
+	var req PayButtonRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	paymentLink, _ := util.GetFromContext[*model.MerchantPaymentLink](c, merchant.PaymentLinkObjKey)
+	if err := checkPaymentLinkAvailable(paymentLink); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	text := req.Text
+	if text == "" {
+		if paymentLink.AmountMode == model.PaymentLinkAmountModeFixed {
+			text = fmt.Sprintf("支付 %s LDC", paymentLink.Amount.StringFixed(2))
+		} else {
+			text = "立即支付"
+		}
+	}
+
+	var snippet strings.Builder
+	if err := payButtonTemplate.Execute(&snippet, map[string]string{
+		"URL":  paymentLinkURL(paymentLink.Token),
+		"Text": text,
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(snippet.String()))
+}
+
+// PaymentLinkStatsRequest 支付链接统计请求
+type PaymentLinkStatsRequest struct {
+	Days int `form:"days" binding:"omitempty,min=1,max=90"`
+}
+
+// PaymentLinkStatsBucket 支付链接统计数据
+type PaymentLinkStatsBucket struct {
+	Date         string          `json:"date,omitempty"`
+	Views        int64           `json:"views"`
+	Orders       int64           `json:"orders"`
+	Revenue      decimal.Decimal `json:"revenue"`
+	UniquePayers int64           `json:"unique_payers"`
+	Conversion   decimal.Decimal `json:"conversion"`
+}
+
+// PaymentLinkStatsResponse 支付链接统计响应
+type PaymentLinkStatsResponse struct {
+	Total PaymentLinkStatsBucket   `json:"total"`
+	Daily []PaymentLinkStatsBucket `json:"daily"`
+}
+
+// GetPaymentLinkStats 获取支付链接统计数据
+// @Tags merchant
+// @Produce json
+// @Param id path uint64 true "API Key ID"
+// @Param linkId path uint64 true "Payment Link ID"
+// @Param request query PaymentLinkStatsRequest false "统计参数"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/merchant/api-keys/{id}/payment-links/{linkId}/stats [get]
+func GetPaymentLinkStats(c *gin.Context) {
+	var req PaymentLinkStatsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+	if req.Days == 0 {
+		req.Days = LinkStatsDefaultDays
+	}
+
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, merchant.APIKeyObjKey)
+
+	var paymentLink model.MerchantPaymentLink
+	if err := db.DB(c.Request.Context()).
+		Where("id = ? AND merchant_api_key_id = ?", c.Param("linkId"), apiKey.ID).
+		First(&paymentLink).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(PaymentLinkNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	stats, err := queryPaymentLinkStats(db.DB(c.Request.Context()), paymentLink.ID, req.Days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(stats))
+}
+
 // DeletePaymentLink 删除支付链接
 // @Tags merchant
 // @Produce json
@@ -193,6 +470,7 @@ func DeletePaymentLink(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param request body PayByLinkRequest true "支付请求"
+// @Param Idempotency-Key header string false "幂等键，重复请求返回首次请求的响应"
 // @Success 200 {object} util.ResponseAny
 // @Router /api/v1/merchant/payment-links/pay [post]
 func PayByLink(c *gin.Context) {
@@ -210,14 +488,21 @@ func PayByLink(c *gin.Context) {
 
 	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
 
-	if subtle.ConstantTimeCompare([]byte(currentUser.PayKey), []byte(req.PayKey)) != 1 {
+	// 未提供支付密钥时在事务内校验免密支付额度
+	if req.PayKey != "" && subtle.ConstantTimeCompare([]byte(currentUser.PayKey), []byte(req.PayKey)) != 1 {
 		c.JSON(http.StatusBadRequest, util.Err(common.PayKeyIncorrect))
 		return
 	}
 
-	// 检查余额是否足够
-	if currentUser.AvailableBalance.LessThan(paymentLink.Amount) {
-		c.JSON(http.StatusBadRequest, util.Err(common.InsufficientBalance))
+	if err := checkPaymentLinkAvailable(&paymentLink); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	// 确定支付金额
+	amount, err := resolvePayAmount(&paymentLink, req.Amount)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
 		return
 	}
 
@@ -255,16 +540,61 @@ func PayByLink(c *gin.Context) {
 		return
 	}
 
+	outTradeNo := req.OutTradeNo
+	if outTradeNo == "" {
+		outTradeNo = fmt.Sprintf("%s_%d_%s", OutTradeNoPrefix, paymentLink.ID, util.GenerateUniqueIDSimple()[:16])
+	}
+
+	var order model.Order
 	if err := db.DB(c.Request.Context()).Transaction(
 		func(tx *gorm.DB) error {
+			// 锁定支付链接并占用库存和使用次数
+			lockedLink, err := consumePaymentLink(tx, paymentLink.ID, currentUser.ID)
+			if err != nil {
+				return err
+			}
+
+			// 以锁定后的链接重新确定金额和订单信息，避免与链接更新并发时按旧价格扣款
+			if amount, err = resolvePayAmount(lockedLink, req.Amount); err != nil {
+				return err
+			}
+
+			// 使用优惠券时核销并改按减免后的金额支付
+			var discount *service.CouponDiscount
+			if req.CouponCode != "" {
+				var errCoupon error
+				discount, errCoupon = service.ApplyCoupon(tx, merchantAPIKey.ID, lockedLink.ID, currentUser.ID, req.CouponCode, amount)
+				if errCoupon != nil {
+					return errCoupon
+				}
+				amount = discount.PayableAmount
+			}
+
+			var allowanceID uint64
+			if req.PayKey == "" {
+				allowance, err := service.ConsumeAllowance(tx, currentUser.ID, merchantAPIKey.ClientID, amount)
+				if err != nil {
+					return err
+				}
+				allowanceID = allowance.ID
+			}
+
 			// 检查每日限额
-			if err := service.CheckDailyLimit(tx, currentUser.ID, paymentLink.Amount, payerPayConfig.DailyLimit); err != nil {
+			if err := service.CheckDailyLimit(tx, currentUser.ID, amount, payerPayConfig.DailyLimit); err != nil {
+				return err
+			}
+
+			// 检查商户应用的金额范围和每日收款限额
+			if err := service.CheckMerchantLimits(tx, &merchantAPIKey, amount); err != nil {
 				return err
 			}
 
 			// 计算手续费
-			_, merchantAmount, feePercent := service.CalculateFee(paymentLink.Amount, merchantPayConfig.FeeRate)
+			_, merchantAmount, feePercent := service.CalculateFee(amount, merchantPayConfig.FeeRate)
 			feeRemark := fmt.Sprintf("[系统]: 收取商家%d%%手续费", feePercent)
+			if allowanceID != 0 {
+				feeRemark = payment.AllowanceRemark + " " + feeRemark
+			}
 
 			remark := req.Remark
 			if remark != "" {
@@ -274,34 +604,46 @@ func PayByLink(c *gin.Context) {
 			}
 
 			// 创建订单
-			order := model.Order{
-				OrderName:   paymentLink.ProductName,
-				PayerUserID: currentUser.ID,
-				PayeeUserID: merchantUser.ID,
-				ClientID:    merchantAPIKey.ClientID,
-				Amount:      paymentLink.Amount,
-				Status:      model.OrderStatusSuccess,
-				Type:        model.OrderTypePayment,
-				Remark:      remark,
-				TradeTime:   time.Now(),
-				ExpiresAt:   time.Now(),
+			order = model.Order{
+				OrderName:             lockedLink.ProductName,
+				MerchantOrderNo:       outTradeNo,
+				PayerUserID:           currentUser.ID,
+				PayeeUserID:           merchantUser.ID,
+				ClientID:              merchantAPIKey.ClientID,
+				Amount:                amount,
+				Status:                model.OrderStatusSuccess,
+				Type:                  model.OrderTypePayment,
+				Remark:                remark,
+				TradeTime:             time.Now(),
+				ExpiresAt:             time.Now(),
+				MerchantPaymentLinkID: lockedLink.ID,
+				AllowanceID:           allowanceID,
+			}
+			if discount != nil {
+				discount.ApplyToOrder(&order)
 			}
 			if err := tx.Create(&order).Error; err != nil {
 				return err
 			}
+			if discount != nil {
+				if err := service.RecordCouponRedemption(tx, discount, order.ID, currentUser.ID); err != nil {
+					return err
+				}
+			}
 
-			// 扣减用户余额
-			if err := service.DeductUserBalance(tx, currentUser.ID, paymentLink.Amount); err != nil {
+			// 扣减用户余额，余额校验在事务内完成
+			if err := service.DeductUserBalance(tx, currentUser.ID, amount); err != nil {
 				return err
 			}
 
 			// 增加商户余额和积分
-			merchantScoreIncrease := paymentLink.Amount.Mul(merchantPayConfig.ScoreRate).Round(0).IntPart()
+			merchantScoreIncrease := amount.Mul(merchantPayConfig.ScoreRate).Round(0).IntPart()
 			if err := service.AddMerchantBalance(tx, merchantUser.ID, merchantAmount, merchantScoreIncrease); err != nil {
 				return err
 			}
 
-			return nil
+			// 下发商户回调任务
+			return payment.EnqueueMerchantNotify(&order)
 		},
 	); err != nil {
 		errMsg := err.Error()
@@ -310,11 +652,64 @@ func PayByLink(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, util.Err(common.InsufficientBalance))
 		case common.DailyLimitExceeded:
 			c.JSON(http.StatusBadRequest, util.Err(common.DailyLimitExceeded))
+		case common.OrderAmountBelowMinimum, common.OrderAmountAboveMaximum, common.MerchantDailyReceiveLimit,
+			PaymentLinkInactive, PaymentLinkExpired, PaymentLinkUsageExceeded, PaymentLinkSoldOut, PaymentLinkPerUserExceeded,
+			PayAmountRequired, PayAmountBelowMinimum, PayAmountAboveMaximum, PayAmountNotInTiers,
+			common.AllowanceNotGranted, common.AllowancePerPaymentExceeded, common.AllowanceDailyExceeded,
+			common.CouponNotFound, common.CouponNotStarted, common.CouponExpired, common.CouponUsageExceeded,
+			common.CouponPerUserExceeded, common.CouponMinSpendNotMet, common.CouponNotApplicable:
+			c.JSON(http.StatusBadRequest, util.Err(errMsg))
+		case PaymentLinkNotFound:
+			c.JSON(http.StatusNotFound, util.Err(errMsg))
 		default:
 			c.JSON(http.StatusInternalServerError, util.Err(errMsg))
 		}
 		return
 	}
 
-	c.JSON(http.StatusOK, util.OKNil())
+	service.PublishOrderEvent(c.Request.Context(), &order)
+
+	c.JSON(http.StatusOK, util.OK(newPayByLinkResponse(&order)))
+}
+
+// PreviewPaymentLinkCouponRequest 支付链接试算优惠券请求
+type PreviewPaymentLinkCouponRequest struct {
+	CouponCode string           `form:"coupon_code" binding:"required,max=32"`
+	Amount     *decimal.Decimal `form:"amount"`
+}
+
+// PreviewPaymentLinkCoupon 试算优惠券对支付链接的减免金额，不核销优惠券
+// @Tags merchant
+// @Produce json
+// @Param token path string true "支付链接 Token"
+// @Param request query PreviewPaymentLinkCouponRequest true "试算参数"
+// @Success 200 {object} service.CouponDiscount
+// @Router /api/v1/merchant/payment-links/{token}/coupon [get]
+func PreviewPaymentLinkCoupon(c *gin.Context) {
+	var req PreviewPaymentLinkCouponRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	paymentLink, _ := util.GetFromContext[*model.MerchantPaymentLink](c, merchant.PaymentLinkObjKey)
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	amount, err := resolvePayAmount(paymentLink, req.Amount)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	discount, err := service.ResolveCoupon(db.DB(c.Request.Context()), paymentLink.MerchantAPIKeyID, paymentLink.ID, currentUser.ID, req.CouponCode, amount, false)
+	if err != nil {
+		if service.IsCouponError(err) {
+			c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(discount))
 }
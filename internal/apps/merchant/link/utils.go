@@ -0,0 +1,367 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package link
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/linux-do/pay/internal/config"
+	"github.com/linux-do/pay/internal/db"
+
+	"github.com/linux-do/pay/internal/common"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	statsLocation     *time.Location
+	statsLocationOnce sync.Once
+)
+
+// location 返回按天统计使用的时区，加载失败时回退到本地时区
+func location() *time.Location {
+	statsLocationOnce.Do(func() {
+		loc, err := time.LoadLocation(statsTimezone)
+		if err != nil {
+			loc = time.Local
+		}
+		statsLocation = loc
+	})
+	return statsLocation
+}
+
+// paymentLinkDetailColumns 支付链接详情查询字段
+const paymentLinkDetailColumns = "merchant_payment_links.id, merchant_payment_links.token, merchant_payment_links.amount_mode, merchant_payment_links.amount, " +
+	"merchant_payment_links.min_amount, merchant_payment_links.max_amount, merchant_payment_links.amount_tiers, merchant_payment_links.goal_amount, merchant_payment_links.product_name, merchant_payment_links.remark, " +
+	"merchant_payment_links.is_active, merchant_payment_links.expires_at, merchant_payment_links.max_uses, merchant_payment_links.per_user_limit, merchant_payment_links.stock, merchant_payment_links.used_count, " +
+	"merchant_payment_links.created_at, merchant_api_keys.app_name"
+
+// payButtonTemplate 可嵌入的支付按钮 HTML 片段
+var payButtonTemplate = template.Must(template.New("pay_button").Parse(
+	`<a href="{{.URL}}" target="_blank" rel="noopener noreferrer" ` +
+		`style="display:inline-block;padding:10px 24px;border-radius:8px;background:#2563eb;color:#ffffff;` +
+		`font-size:14px;font-weight:600;text-decoration:none;font-family:sans-serif;">{{.Text}}</a>`,
+))
+
+// paidLinkOrderStatuses 计入已购买的链接订单状态
+var paidLinkOrderStatuses = []model.OrderStatus{model.OrderStatusSuccess, model.OrderStatusDisputing, model.OrderStatusRefused}
+
+// validateAmount 校验金额大于 0 且最多两位小数
+func validateAmount(amount decimal.Decimal) error {
+	if amount.LessThanOrEqual(decimal.Zero) {
+		return errors.New(common.AmountMustBeGreaterThanZero)
+	}
+	if amount.Exponent() < -2 {
+		return errors.New(common.AmountDecimalPlacesExceeded)
+	}
+	return nil
+}
+
+// validate 校验支付链接参数，并按金额模式清理无关字段
+func (req *CreatePaymentLinkRequest) validate() error {
+	if req.AmountMode == "" {
+		req.AmountMode = model.PaymentLinkAmountModeFixed
+	}
+
+	switch req.AmountMode {
+	case model.PaymentLinkAmountModeFixed:
+		if err := validateAmount(req.Amount); err != nil {
+			return err
+		}
+		req.MinAmount, req.MaxAmount, req.AmountTiers = nil, nil, nil
+	case model.PaymentLinkAmountModeOpen:
+		if req.MinAmount != nil {
+			if err := validateAmount(*req.MinAmount); err != nil {
+				return err
+			}
+		}
+		if req.MaxAmount != nil {
+			if err := validateAmount(*req.MaxAmount); err != nil {
+				return err
+			}
+		}
+		if req.MinAmount != nil && req.MaxAmount != nil && req.MinAmount.GreaterThan(*req.MaxAmount) {
+			return errors.New(MinAmountAboveMax)
+		}
+		req.Amount, req.AmountTiers = decimal.Zero, nil
+	case model.PaymentLinkAmountModeTiers:
+		if len(req.AmountTiers) == 0 {
+			return errors.New(AmountTiersRequired)
+		}
+		for _, tier := range req.AmountTiers {
+			if err := validateAmount(tier); err != nil {
+				return err
+			}
+		}
+		req.Amount, req.MinAmount, req.MaxAmount = decimal.Zero, nil, nil
+	}
+
+	if req.GoalAmount != nil {
+		if err := validateAmount(*req.GoalAmount); err != nil {
+			return err
+		}
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return errors.New(ExpiresAtMustBeFuture)
+	}
+	return nil
+}
+
+// resolvePayAmount 根据支付链接的金额模式确定本次支付金额
+func resolvePayAmount(paymentLink *model.MerchantPaymentLink, amount *decimal.Decimal) (decimal.Decimal, error) {
+	if paymentLink.AmountMode == model.PaymentLinkAmountModeFixed {
+		return paymentLink.Amount, nil
+	}
+
+	if amount == nil {
+		return decimal.Zero, errors.New(PayAmountRequired)
+	}
+	if err := validateAmount(*amount); err != nil {
+		return decimal.Zero, err
+	}
+
+	if paymentLink.AmountMode == model.PaymentLinkAmountModeTiers {
+		for _, tier := range paymentLink.AmountTiers {
+			if tier.Equal(*amount) {
+				return tier, nil
+			}
+		}
+		return decimal.Zero, errors.New(PayAmountNotInTiers)
+	}
+
+	if paymentLink.MinAmount != nil && amount.LessThan(*paymentLink.MinAmount) {
+		return decimal.Zero, errors.New(PayAmountBelowMinimum)
+	}
+	if paymentLink.MaxAmount != nil && amount.GreaterThan(*paymentLink.MaxAmount) {
+		return decimal.Zero, errors.New(PayAmountAboveMaximum)
+	}
+	return *amount, nil
+}
+
+// paymentLinkURL 支付链接的前端支付页地址
+func paymentLinkURL(token string) string {
+	return fmt.Sprintf("%s/online?token=%s", strings.TrimRight(config.Config.App.FrontendPayURL, "/"), url.QueryEscape(token))
+}
+
+// recordPaymentLinkView 记录支付链接浏览，同一用户每天只计一次
+func recordPaymentLinkView(ctx context.Context, linkID uint64, userID uint64) {
+	now := time.Now().In(location())
+	dateKey := now.Format("2006-01-02")
+
+	firstView, err := db.Redis.SetNX(ctx, fmt.Sprintf(LinkViewKeyFormat, linkID, dateKey, userID), 1, LinkViewDedupTTL).Result()
+	if err != nil {
+		log.Printf("[PaymentLink] 浏览去重失败: link_id=%d, error=%v", linkID, err)
+		return
+	}
+	if !firstView {
+		return
+	}
+
+	view := model.MerchantPaymentLinkView{
+		MerchantPaymentLinkID: linkID,
+		Date:                  time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+		Views:                 1,
+	}
+	if err := db.DB(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "merchant_payment_link_id"}, {Name: "date"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"views": gorm.Expr("merchant_payment_link_views.views + 1")}),
+	}).Create(&view).Error; err != nil {
+		log.Printf("[PaymentLink] 记录浏览失败: link_id=%d, error=%v", linkID, err)
+	}
+}
+
+// newPayByLinkResponse 根据订单构造支付响应
+func newPayByLinkResponse(order *model.Order) PayByLinkResponse {
+	return PayByLinkResponse{
+		TradeNo:    strconv.FormatUint(order.ID, 10),
+		OutTradeNo: order.MerchantOrderNo,
+		LinkID:     order.MerchantPaymentLinkID,
+		Amount:     order.Amount,
+	}
+}
+
+// applyLimits 合并使用限制：请求中传入的限制覆盖原值，未传的保持不变，clear_limits 中的限制改为不限制
+func (req *UpdatePaymentLinkRequest) applyLimits(paymentLink *model.MerchantPaymentLink) {
+	if req.MaxUses != nil {
+		paymentLink.MaxUses = req.MaxUses
+	}
+	if req.PerUserLimit != nil {
+		paymentLink.PerUserLimit = req.PerUserLimit
+	}
+	if req.Stock != nil {
+		paymentLink.Stock = req.Stock
+	}
+
+	for _, limit := range req.ClearLimits {
+		switch limit {
+		case "max_uses":
+			paymentLink.MaxUses = nil
+		case "per_user_limit":
+			paymentLink.PerUserLimit = nil
+		case "stock":
+			paymentLink.Stock = nil
+		}
+	}
+}
+
+// checkPaymentLinkAvailable 检查支付链接当前是否可购买
+func checkPaymentLinkAvailable(paymentLink *model.MerchantPaymentLink) error {
+	if !paymentLink.IsActive {
+		return errors.New(PaymentLinkInactive)
+	}
+	if paymentLink.ExpiresAt != nil && !paymentLink.ExpiresAt.After(time.Now()) {
+		return errors.New(PaymentLinkExpired)
+	}
+	if paymentLink.MaxUses != nil && paymentLink.UsedCount >= *paymentLink.MaxUses {
+		return errors.New(PaymentLinkUsageExceeded)
+	}
+	if paymentLink.Stock != nil && *paymentLink.Stock <= 0 {
+		return errors.New(PaymentLinkSoldOut)
+	}
+	return nil
+}
+
+// consumePaymentLink 在事务内锁定支付链接，校验可用性与个人购买上限，并占用一次使用次数和库存
+func consumePaymentLink(tx *gorm.DB, linkID uint64, payerUserID uint64) (*model.MerchantPaymentLink, error) {
+	var paymentLink model.MerchantPaymentLink
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", linkID).
+		First(&paymentLink).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New(PaymentLinkNotFound)
+		}
+		return nil, err
+	}
+
+	if err := checkPaymentLinkAvailable(&paymentLink); err != nil {
+		return nil, err
+	}
+
+	if paymentLink.PerUserLimit != nil {
+		var purchased int64
+		if err := tx.Model(&model.Order{}).
+			Where("merchant_payment_link_id = ? AND payer_user_id = ? AND status IN ?", paymentLink.ID, payerUserID, paidLinkOrderStatuses).
+			Count(&purchased).Error; err != nil {
+			return nil, err
+		}
+		if purchased >= *paymentLink.PerUserLimit {
+			return nil, errors.New(PaymentLinkPerUserExceeded)
+		}
+	}
+
+	updates := map[string]interface{}{"used_count": gorm.Expr("used_count + 1")}
+	if paymentLink.Stock != nil {
+		updates["stock"] = gorm.Expr("stock - 1")
+	}
+	if err := tx.Model(&paymentLink).UpdateColumns(updates).Error; err != nil {
+		return nil, err
+	}
+
+	return &paymentLink, nil
+}
+
+// queryPaymentLinkStats 统计支付链接最近若干天的浏览、购买、收入和付款人数，按天分桶
+func queryPaymentLinkStats(tx *gorm.DB, linkID uint64, days int) (*PaymentLinkStatsResponse, error) {
+	now := time.Now().In(location())
+	startDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, -(days - 1))
+
+	var views []model.MerchantPaymentLinkView
+	if err := tx.Where("merchant_payment_link_id = ? AND date >= ?", linkID, startDate.Format("2006-01-02")).
+		Find(&views).Error; err != nil {
+		return nil, err
+	}
+
+	var dailyOrders []struct {
+		Date         time.Time
+		Orders       int64
+		Revenue      decimal.Decimal
+		UniquePayers int64
+	}
+	if err := tx.Model(&model.Order{}).
+		Select("DATE(trade_time AT TIME ZONE ?) AS date, COUNT(*) AS orders, COALESCE(SUM(amount), 0) AS revenue, COUNT(DISTINCT payer_user_id) AS unique_payers", statsTimezone).
+		Where("merchant_payment_link_id = ? AND status IN ? AND trade_time >= ?", linkID, paidLinkOrderStatuses, startDate).
+		Group("date").
+		Scan(&dailyOrders).Error; err != nil {
+		return nil, err
+	}
+
+	stats := &PaymentLinkStatsResponse{Daily: make([]PaymentLinkStatsBucket, days)}
+	bucketIndex := make(map[string]int, days)
+	for i := range stats.Daily {
+		date := startDate.AddDate(0, 0, i).Format("2006-01-02")
+		stats.Daily[i] = PaymentLinkStatsBucket{Date: date, Revenue: decimal.Zero, Conversion: decimal.Zero}
+		bucketIndex[date] = i
+	}
+
+	for _, view := range views {
+		if i, ok := bucketIndex[view.Date.Format("2006-01-02")]; ok {
+			stats.Daily[i].Views = view.Views
+			stats.Total.Views += view.Views
+		}
+	}
+	for _, row := range dailyOrders {
+		if i, ok := bucketIndex[row.Date.Format("2006-01-02")]; ok {
+			stats.Daily[i].Orders = row.Orders
+			stats.Daily[i].Revenue = row.Revenue
+			stats.Daily[i].UniquePayers = row.UniquePayers
+			stats.Total.Orders += row.Orders
+			stats.Total.Revenue = stats.Total.Revenue.Add(row.Revenue)
+		}
+	}
+
+	// 去重付款人数不能按天累加，需单独统计
+	if err := tx.Model(&model.Order{}).
+		Select("COUNT(DISTINCT payer_user_id)").
+		Where("merchant_payment_link_id = ? AND status IN ? AND trade_time >= ?", linkID, paidLinkOrderStatuses, startDate).
+		Scan(&stats.Total.UniquePayers).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range stats.Daily {
+		stats.Daily[i].Conversion = conversionRate(stats.Daily[i].Orders, stats.Daily[i].Views)
+	}
+	stats.Total.Conversion = conversionRate(stats.Total.Orders, stats.Total.Views)
+
+	return stats, nil
+}
+
+// conversionRate 计算转化率（购买数 / 浏览数），保留四位小数
+func conversionRate(orders int64, views int64) decimal.Decimal {
+	if views == 0 {
+		return decimal.Zero
+	}
+	return decimal.NewFromInt(orders).Div(decimal.NewFromInt(views)).Round(4)
+}
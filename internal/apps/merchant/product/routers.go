@@ -0,0 +1,653 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package product
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/pay/internal/apps/merchant"
+	"github.com/linux-do/pay/internal/apps/oauth"
+	"github.com/linux-do/pay/internal/apps/payment"
+	"github.com/linux-do/pay/internal/common"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/service"
+	"github.com/linux-do/pay/internal/util"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateProductRequest 创建商品请求，虚拟商品的库存由上传的发货内容决定，stock 为空表示不限库存
+type CreateProductRequest struct {
+	Name        string          `json:"name" binding:"required,max=64"`
+	Description string          `json:"description" binding:"max=500"`
+	Price       decimal.Decimal `json:"price" binding:"required"`
+	Stock       *int64          `json:"stock" binding:"omitempty,min=0"`
+	IsDigital   bool            `json:"is_digital"`
+}
+
+// UpdateProductRequest 更新商品请求，商品类型创建后不可修改
+type UpdateProductRequest struct {
+	Name        string          `json:"name" binding:"required,max=64"`
+	Description string          `json:"description" binding:"max=500"`
+	Price       decimal.Decimal `json:"price" binding:"required"`
+	Stock       *int64          `json:"stock" binding:"omitempty,min=0"`
+	IsActive    *bool           `json:"is_active" binding:"required"`
+}
+
+// UploadProductCodesRequest 上传虚拟商品发货内容请求
+type UploadProductCodesRequest struct {
+	Contents []string `json:"contents" binding:"required,min=1,max=1000,dive,required,max=1000"`
+}
+
+// ListProductCodesRequest 查询虚拟商品发货内容请求
+type ListProductCodesRequest struct {
+	Page      int   `form:"page" binding:"min=1"`
+	PageSize  int   `form:"page_size" binding:"min=1,max=100"`
+	Delivered *bool `form:"delivered"`
+}
+
+// ListProductCodesResponse 虚拟商品发货内容列表
+type ListProductCodesResponse struct {
+	Total    int64                       `json:"total"`
+	Page     int                         `json:"page"`
+	PageSize int                         `json:"page_size"`
+	Codes    []model.MerchantProductCode `json:"codes"`
+}
+
+// StoreProduct 店铺展示的商品信息
+type StoreProduct struct {
+	ID          uint64          `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Price       decimal.Decimal `json:"price"`
+	Stock       *int64          `json:"stock"`
+	IsDigital   bool            `json:"is_digital"`
+	SoldCount   int64           `json:"sold_count"`
+}
+
+// StoreResponse 店铺信息及在售商品
+type StoreResponse struct {
+	AppName        string         `json:"app_name"`
+	AppHomepageURL string         `json:"app_homepage_url"`
+	AppDescription string         `json:"app_description"`
+	Products       []StoreProduct `json:"products"`
+}
+
+// BuyProductRequest 购买商品请求
+type BuyProductRequest struct {
+	Quantity int    `json:"quantity" binding:"omitempty,min=1,max=100"`
+	PayKey   string `json:"pay_key" binding:"max=6"`
+	Remark   string `json:"remark" binding:"max=100"`
+}
+
+// BuyProductResponse 购买商品响应，虚拟商品返回本次发货内容
+type BuyProductResponse struct {
+	TradeNo    string          `json:"trade_no"`
+	OutTradeNo string          `json:"out_trade_no"`
+	ProductID  uint64          `json:"product_id"`
+	Quantity   int             `json:"quantity"`
+	Amount     decimal.Decimal `json:"amount"`
+	Deliveries []string        `json:"deliveries"`
+}
+
+// ProductDelivery 订单的发货内容
+type ProductDelivery struct {
+	Content     string     `json:"content"`
+	DeliveredAt *time.Time `json:"delivered_at"`
+}
+
+// CreateProduct 创建商品
+// @Tags merchant
+// @Accept json
+// @Produce json
+// @Param id path uint64 true "API Key ID"
+// @Param request body CreateProductRequest true "创建商品请求"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/merchant/api-keys/{id}/products [post]
+func CreateProduct(c *gin.Context) {
+	var req CreateProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	if err := validatePrice(req.Price); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	stock := req.Stock
+	if req.IsDigital {
+		if stock != nil {
+			c.JSON(http.StatusBadRequest, util.Err(ProductStockManagedByCodes))
+			return
+		}
+		var zero int64
+		stock = &zero
+	}
+
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, merchant.APIKeyObjKey)
+
+	product := model.MerchantProduct{
+		MerchantAPIKeyID: apiKey.ID,
+		Name:             req.Name,
+		Description:      req.Description,
+		Price:            req.Price,
+		Stock:            stock,
+		IsDigital:        req.IsDigital,
+		IsActive:         true,
+	}
+	if err := db.DB(c.Request.Context()).Create(&product).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(product))
+}
+
+// ListProducts 获取商户应用的商品列表
+// @Tags merchant
+// @Produce json
+// @Param id path uint64 true "API Key ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/merchant/api-keys/{id}/products [get]
+func ListProducts(c *gin.Context) {
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, merchant.APIKeyObjKey)
+
+	products := make([]model.MerchantProduct, 0)
+	if err := db.DB(c.Request.Context()).
+		Where("merchant_api_key_id = ?", apiKey.ID).
+		Order("created_at DESC").
+		Find(&products).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(products))
+}
+
+// UpdateProduct 更新商品，虚拟商品的库存不可手动修改
+// @Tags merchant
+// @Accept json
+// @Produce json
+// @Param id path uint64 true "API Key ID"
+// @Param productId path uint64 true "商品 ID"
+// @Param request body UpdateProductRequest true "更新商品请求"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/merchant/api-keys/{id}/products/{productId} [put]
+func UpdateProduct(c *gin.Context) {
+	var req UpdateProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	if err := validatePrice(req.Price); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, merchant.APIKeyObjKey)
+
+	product, err := findProduct(db.DB(c.Request.Context()), apiKey.ID, c.Param("productId"))
+	if err != nil {
+		if err.Error() == ProductNotFound {
+			c.JSON(http.StatusNotFound, util.Err(err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	columns := []string{"name", "description", "price", "is_active"}
+	if product.IsDigital {
+		if req.Stock != nil {
+			c.JSON(http.StatusBadRequest, util.Err(ProductStockManagedByCodes))
+			return
+		}
+	} else {
+		columns = append(columns, "stock")
+	}
+
+	product.Name = req.Name
+	product.Description = req.Description
+	product.Price = req.Price
+	product.IsActive = *req.IsActive
+	if !product.IsDigital {
+		product.Stock = req.Stock
+	}
+	if err := db.DB(c.Request.Context()).Model(product).Select(columns).Updates(product).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(product))
+}
+
+// DeleteProduct 删除商品
+// @Tags merchant
+// @Produce json
+// @Param id path uint64 true "API Key ID"
+// @Param productId path uint64 true "商品 ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/merchant/api-keys/{id}/products/{productId} [delete]
+func DeleteProduct(c *gin.Context) {
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, merchant.APIKeyObjKey)
+
+	result := db.DB(c.Request.Context()).
+		Where("id = ? AND merchant_api_key_id = ?", c.Param("productId"), apiKey.ID).
+		Delete(&model.MerchantProduct{})
+
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(result.Error.Error()))
+		return
+	}
+
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, util.Err(ProductNotFound))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
+
+// UploadProductCodes 上传虚拟商品发货内容，库存按上传数量增加
+// @Tags merchant
+// @Accept json
+// @Produce json
+// @Param id path uint64 true "API Key ID"
+// @Param productId path uint64 true "商品 ID"
+// @Param request body UploadProductCodesRequest true "发货内容"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/merchant/api-keys/{id}/products/{productId}/codes [post]
+func UploadProductCodes(c *gin.Context) {
+	var req UploadProductCodesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, merchant.APIKeyObjKey)
+
+	var product model.MerchantProduct
+	if err := db.DB(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND merchant_api_key_id = ?", c.Param("productId"), apiKey.ID).
+			First(&product).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New(ProductNotFound)
+			}
+			return err
+		}
+		if !product.IsDigital {
+			return errors.New(ProductNotDigital)
+		}
+
+		codes := make([]model.MerchantProductCode, 0, len(req.Contents))
+		for _, content := range req.Contents {
+			codes = append(codes, model.MerchantProductCode{ProductID: product.ID, Content: content})
+		}
+		if err := tx.Create(&codes).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&product).
+			UpdateColumn("stock", gorm.Expr("stock + ?", len(codes))).Error
+	}); err != nil {
+		switch err.Error() {
+		case ProductNotFound:
+			c.JSON(http.StatusNotFound, util.Err(err.Error()))
+		case ProductNotDigital:
+			c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(gin.H{"uploaded": len(req.Contents)}))
+}
+
+// ListProductCodes 分页查询虚拟商品发货内容及发货情况
+// @Tags merchant
+// @Produce json
+// @Param id path uint64 true "API Key ID"
+// @Param productId path uint64 true "商品 ID"
+// @Param request query ListProductCodesRequest true "查询参数"
+// @Success 200 {object} ListProductCodesResponse
+// @Router /api/v1/merchant/api-keys/{id}/products/{productId}/codes [get]
+func ListProductCodes(c *gin.Context) {
+	var req ListProductCodesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, merchant.APIKeyObjKey)
+
+	product, err := findProduct(db.DB(c.Request.Context()), apiKey.ID, c.Param("productId"))
+	if err != nil {
+		if err.Error() == ProductNotFound {
+			c.JSON(http.StatusNotFound, util.Err(err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	query := db.DB(c.Request.Context()).Model(&model.MerchantProductCode{}).Where("product_id = ?", product.ID)
+	if req.Delivered != nil {
+		if *req.Delivered {
+			query = query.Where("order_id IS NOT NULL")
+		} else {
+			query = query.Where("order_id IS NULL")
+		}
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	response := ListProductCodesResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		Codes:    make([]model.MerchantProductCode, 0),
+	}
+	if err := query.Order("id DESC").
+		Offset((req.Page - 1) * req.PageSize).
+		Limit(req.PageSize).
+		Find(&response.Codes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// GetStore 查询商户应用的公开店铺及在售商品
+// @Tags merchant
+// @Produce json
+// @Param clientId path string true "商户应用 Client ID"
+// @Success 200 {object} StoreResponse
+// @Router /api/v1/merchant/stores/{clientId} [get]
+func GetStore(c *gin.Context) {
+	var apiKey model.MerchantAPIKey
+	if err := apiKey.GetByClientID(db.DB(c.Request.Context()), c.Param("clientId")); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(StoreNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	response := StoreResponse{
+		AppName:        apiKey.AppName,
+		AppHomepageURL: apiKey.AppHomepageURL,
+		AppDescription: apiKey.AppDescription,
+		Products:       make([]StoreProduct, 0),
+	}
+	if err := db.DB(c.Request.Context()).
+		Model(&model.MerchantProduct{}).
+		Where("merchant_api_key_id = ? AND is_active = ?", apiKey.ID, true).
+		Order("created_at DESC").
+		Find(&response.Products).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// BuyProduct 购买商品，库存在支付事务内扣减，虚拟商品支付成功后立即发货
+// @Tags merchant
+// @Accept json
+// @Produce json
+// @Param productId path uint64 true "商品 ID"
+// @Param request body BuyProductRequest true "购买请求"
+// @Param Idempotency-Key header string false "幂等键，重复请求返回首次请求的响应"
+// @Success 200 {object} BuyProductResponse
+// @Router /api/v1/merchant/products/{productId}/buy [post]
+func BuyProduct(c *gin.Context) {
+	var req BuyProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+	if req.Quantity == 0 {
+		req.Quantity = DefaultBuyQuantity
+	}
+
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	// 未提供支付密钥时在事务内校验免密支付额度
+	if req.PayKey != "" && subtle.ConstantTimeCompare([]byte(currentUser.PayKey), []byte(req.PayKey)) != 1 {
+		c.JSON(http.StatusBadRequest, util.Err(common.PayKeyIncorrect))
+		return
+	}
+
+	var product model.MerchantProduct
+	if err := db.DB(c.Request.Context()).Where("id = ?", c.Param("productId")).First(&product).Error; err != nil {
+		c.JSON(http.StatusNotFound, util.Err(ProductNotFound))
+		return
+	}
+
+	// 查询商户 API Key
+	var merchantAPIKey model.MerchantAPIKey
+	if err := merchantAPIKey.GetByID(db.DB(c.Request.Context()), product.MerchantAPIKeyID); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	// 查询商户用户
+	var merchantUser model.User
+	if err := merchantUser.GetByID(db.DB(c.Request.Context()), merchantAPIKey.UserID); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	// 不能给自己付款
+	if currentUser.ID == merchantUser.ID {
+		c.JSON(http.StatusBadRequest, util.Err(common.CannotPaySelf))
+		return
+	}
+
+	// 获取商户和付款方的支付配置
+	var merchantPayConfig, payerPayConfig model.UserPayConfig
+	if err := merchantPayConfig.GetByPayScore(db.DB(c.Request.Context()), merchantUser.PayScore); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+	if err := payerPayConfig.GetByPayScore(db.DB(c.Request.Context()), currentUser.PayScore); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	var order model.Order
+	var deliveries []string
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			// 锁定商品并扣减库存，以锁定后的价格结算
+			lockedProduct, err := consumeProductStock(tx, product.ID, req.Quantity)
+			if err != nil {
+				return err
+			}
+			amount := lockedProduct.Price.Mul(decimal.NewFromInt(int64(req.Quantity)))
+
+			var allowanceID uint64
+			if req.PayKey == "" {
+				allowance, err := service.ConsumeAllowance(tx, currentUser.ID, merchantAPIKey.ClientID, amount)
+				if err != nil {
+					return err
+				}
+				allowanceID = allowance.ID
+			}
+
+			// 检查每日限额
+			if err := service.CheckDailyLimit(tx, currentUser.ID, amount, payerPayConfig.DailyLimit); err != nil {
+				return err
+			}
+
+			// 检查商户应用的金额范围和每日收款限额
+			if err := service.CheckMerchantLimits(tx, &merchantAPIKey, amount); err != nil {
+				return err
+			}
+
+			// 计算手续费
+			_, merchantAmount, feePercent := service.CalculateFee(amount, merchantPayConfig.FeeRate)
+			feeRemark := fmt.Sprintf("[系统]: 收取商家%d%%手续费", feePercent)
+			if allowanceID != 0 {
+				feeRemark = payment.AllowanceRemark + " " + feeRemark
+			}
+
+			remark := req.Remark
+			if remark != "" {
+				remark = remark + " " + feeRemark
+			} else {
+				remark = feeRemark
+			}
+
+			// 创建订单及商品明细
+			now := time.Now()
+			order = model.Order{
+				OrderName:         lockedProduct.Name,
+				MerchantOrderNo:   fmt.Sprintf("%s_%d_%s", OutTradeNoPrefix, lockedProduct.ID, util.GenerateUniqueIDSimple()[:16]),
+				PayerUserID:       currentUser.ID,
+				PayeeUserID:       merchantUser.ID,
+				ClientID:          merchantAPIKey.ClientID,
+				Amount:            amount,
+				Status:            model.OrderStatusSuccess,
+				Type:              model.OrderTypePayment,
+				Remark:            remark,
+				TradeTime:         now,
+				ExpiresAt:         now,
+				MerchantProductID: lockedProduct.ID,
+				AllowanceID:       allowanceID,
+			}
+			if err := tx.Create(&order).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(&model.OrderItem{
+				OrderID:   order.ID,
+				LineNo:    1,
+				Name:      lockedProduct.Name,
+				SKU:       strconv.FormatUint(lockedProduct.ID, 10),
+				UnitPrice: lockedProduct.Price,
+				Quantity:  req.Quantity,
+				Amount:    amount,
+			}).Error; err != nil {
+				return err
+			}
+
+			// 扣减用户余额，余额校验在事务内完成
+			if err := service.DeductUserBalance(tx, currentUser.ID, amount); err != nil {
+				return err
+			}
+
+			// 增加商户余额和积分
+			merchantScoreIncrease := amount.Mul(merchantPayConfig.ScoreRate).Round(0).IntPart()
+			if err := service.AddMerchantBalance(tx, merchantUser.ID, merchantAmount, merchantScoreIncrease); err != nil {
+				return err
+			}
+
+			// 虚拟商品发货
+			if lockedProduct.IsDigital {
+				deliveries, err = deliverProductCodes(tx, lockedProduct.ID, order.ID, currentUser.ID, req.Quantity)
+				if err != nil {
+					return err
+				}
+			}
+
+			// 下发商户回调任务
+			return payment.EnqueueMerchantNotify(&order)
+		},
+	); err != nil {
+		errMsg := err.Error()
+		switch errMsg {
+		case common.InsufficientBalance, common.DailyLimitExceeded,
+			common.OrderAmountBelowMinimum, common.OrderAmountAboveMaximum, common.MerchantDailyReceiveLimit,
+			ProductInactive, ProductSoldOut,
+			common.AllowanceNotGranted, common.AllowancePerPaymentExceeded, common.AllowanceDailyExceeded:
+			c.JSON(http.StatusBadRequest, util.Err(errMsg))
+		case ProductNotFound:
+			c.JSON(http.StatusNotFound, util.Err(errMsg))
+		default:
+			c.JSON(http.StatusInternalServerError, util.Err(errMsg))
+		}
+		return
+	}
+
+	service.PublishOrderEvent(c.Request.Context(), &order)
+
+	if deliveries == nil {
+		deliveries = make([]string, 0)
+	}
+	c.JSON(http.StatusOK, util.OK(BuyProductResponse{
+		TradeNo:    strconv.FormatUint(order.ID, 10),
+		OutTradeNo: order.MerchantOrderNo,
+		ProductID:  order.MerchantProductID,
+		Quantity:   req.Quantity,
+		Amount:     order.Amount,
+		Deliveries: deliveries,
+	}))
+}
+
+// ListOrderDeliveries 买家查询商品订单的发货内容
+// @Tags merchant
+// @Produce json
+// @Param tradeNo path string true "平台订单号"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/merchant/product-orders/{tradeNo}/deliveries [get]
+func ListOrderDeliveries(c *gin.Context) {
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	deliveries := make([]ProductDelivery, 0)
+	if err := db.DB(c.Request.Context()).
+		Model(&model.MerchantProductCode{}).
+		Where("order_id = ? AND buyer_user_id = ?", c.Param("tradeNo"), currentUser.ID).
+		Order("id ASC").
+		Find(&deliveries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	if len(deliveries) == 0 {
+		c.JSON(http.StatusNotFound, util.Err(DeliveryNotFound))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(deliveries))
+}
@@ -0,0 +1,123 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package product
+
+import (
+	"errors"
+	"time"
+
+	"github.com/linux-do/pay/internal/common"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// validatePrice 校验商品价格大于 0 且最多两位小数
+func validatePrice(price decimal.Decimal) error {
+	if price.LessThanOrEqual(decimal.Zero) {
+		return errors.New(common.AmountMustBeGreaterThanZero)
+	}
+	if price.Exponent() < -2 {
+		return errors.New(common.AmountDecimalPlacesExceeded)
+	}
+	return nil
+}
+
+// findProduct 查询商户应用下未删除的商品
+func findProduct(tx *gorm.DB, apiKeyID uint64, productID string) (*model.MerchantProduct, error) {
+	var product model.MerchantProduct
+	if err := tx.Where("id = ? AND merchant_api_key_id = ?", productID, apiKeyID).First(&product).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New(ProductNotFound)
+		}
+		return nil, err
+	}
+	return &product, nil
+}
+
+// consumeProductStock 在事务内锁定商品，校验上架状态与库存，并扣减库存、累加销量
+func consumeProductStock(tx *gorm.DB, productID uint64, quantity int) (*model.MerchantProduct, error) {
+	var product model.MerchantProduct
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", productID).
+		First(&product).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New(ProductNotFound)
+		}
+		return nil, err
+	}
+
+	if !product.IsActive {
+		return nil, errors.New(ProductInactive)
+	}
+	if product.Stock != nil && *product.Stock < int64(quantity) {
+		return nil, errors.New(ProductSoldOut)
+	}
+
+	updates := map[string]interface{}{"sold_count": gorm.Expr("sold_count + ?", quantity)}
+	if product.Stock != nil {
+		updates["stock"] = gorm.Expr("stock - ?", quantity)
+	}
+	if err := tx.Model(&product).UpdateColumns(updates).Error; err != nil {
+		return nil, err
+	}
+
+	return &product, nil
+}
+
+// deliverProductCodes 按上传顺序取出虚拟商品的发货内容，记录到订单和买家名下
+func deliverProductCodes(tx *gorm.DB, productID uint64, orderID uint64, buyerUserID uint64, quantity int) ([]string, error) {
+	var codes []model.MerchantProductCode
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("product_id = ? AND order_id IS NULL", productID).
+		Order("id ASC").
+		Limit(quantity).
+		Find(&codes).Error; err != nil {
+		return nil, err
+	}
+	if len(codes) < quantity {
+		return nil, errors.New(ProductSoldOut)
+	}
+
+	codeIDs := make([]uint64, 0, len(codes))
+	contents := make([]string, 0, len(codes))
+	for _, code := range codes {
+		codeIDs = append(codeIDs, code.ID)
+		contents = append(contents, code.Content)
+	}
+
+	if err := tx.Model(&model.MerchantProductCode{}).
+		Where("id IN ?", codeIDs).
+		UpdateColumns(map[string]interface{}{
+			"order_id":      orderID,
+			"buyer_user_id": buyerUserID,
+			"delivered_at":  time.Now(),
+		}).Error; err != nil {
+		return nil, err
+	}
+
+	return contents, nil
+}
@@ -0,0 +1,38 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package money_request
+
+const (
+	MoneyRequestNotFound      = "收款请求不存在"
+	MoneyRequestNotPending    = "收款请求已处理或已关闭"
+	MoneyRequestExpired       = "收款请求已过期"
+	PayerNotFound             = "付款用户不存在"
+	CannotRequestFromSelf     = "不能向自己发起收款请求"
+	CannotPayOwnRequest       = "不能支付自己发起的收款请求"
+	ExpiresAtMustBeFuture     = "过期时间必须晚于当前时间"
+	PayerUsernameRequired     = "指定付款用户时必须同时提供用户名"
+	NotMoneyRequestPayer      = "您不是该收款请求的付款人"
+	MoneyRequestNotDeclinable = "收款链接无法拒绝"
+)
@@ -0,0 +1,385 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package money_request
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/pay/internal/apps/oauth"
+	"github.com/linux-do/pay/internal/common"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/service"
+	"github.com/linux-do/pay/internal/util"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// CreateMoneyRequestRequest 发起收款请求，不指定付款用户时生成可分享的收款链接
+type CreateMoneyRequestRequest struct {
+	PayerID       uint64          `json:"payer_id"`
+	PayerUsername string          `json:"payer_username"`
+	Amount        decimal.Decimal `json:"amount" binding:"required"`
+	Note          string          `json:"note" binding:"max=100"`
+	ExpiresAt     *time.Time      `json:"expires_at"`
+}
+
+// CreateMoneyRequest 发起收款请求
+// @Tags money_request
+// @Accept json
+// @Produce json
+// @Param request body CreateMoneyRequestRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/money-requests [post]
+func CreateMoneyRequest(c *gin.Context) {
+	var req CreateMoneyRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	if req.Amount.LessThanOrEqual(decimal.Zero) {
+		c.JSON(http.StatusBadRequest, util.Err(common.AmountMustBeGreaterThanZero))
+		return
+	}
+	if req.Amount.Exponent() < -2 {
+		c.JSON(http.StatusBadRequest, util.Err(common.AmountDecimalPlacesExceeded))
+		return
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, util.Err(ExpiresAtMustBeFuture))
+		return
+	}
+
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	moneyRequest := model.MoneyRequest{
+		Token:           util.GenerateUniqueIDSimple(),
+		RequesterUserID: currentUser.ID,
+		Amount:          req.Amount,
+		Note:            req.Note,
+		Status:          model.MoneyRequestStatusPending,
+		ExpiresAt:       req.ExpiresAt,
+	}
+
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			var payer model.User
+			if req.PayerID != 0 {
+				if req.PayerUsername == "" {
+					return errors.New(PayerUsernameRequired)
+				}
+				if req.PayerID == currentUser.ID {
+					return errors.New(CannotRequestFromSelf)
+				}
+				if err := tx.Where("id = ? AND username = ? AND is_active = ?", req.PayerID, req.PayerUsername, true).First(&payer).Error; err != nil {
+					if errors.Is(err, gorm.ErrRecordNotFound) {
+						return errors.New(PayerNotFound)
+					}
+					return err
+				}
+				moneyRequest.PayerUserID = &payer.ID
+				moneyRequest.PayerUsername = payer.Username
+			}
+
+			if err := tx.Create(&moneyRequest).Error; err != nil {
+				return err
+			}
+			moneyRequest.RequesterUsername = currentUser.Username
+
+			if moneyRequest.PayerUserID == nil {
+				return nil
+			}
+			return service.CreateNotification(tx, payer.ID, model.NotificationTypeMoneyRequest, NotifyTitleCreated,
+				fmt.Sprintf("%s 向您发起了 %s 的收款请求", currentUser.Username, req.Amount.StringFixed(2)), moneyRequest.ID)
+		},
+	); err != nil {
+		respondMoneyRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(moneyRequest))
+}
+
+// ListMoneyRequestsRequest 查询收款请求列表请求
+type ListMoneyRequestsRequest struct {
+	Page     int    `json:"page" form:"page" binding:"min=1"`
+	PageSize int    `json:"page_size" form:"page_size" binding:"min=1,max=100"`
+	Role     string `json:"role" form:"role" binding:"omitempty,oneof=incoming outgoing"`
+	Status   string `json:"status" form:"status" binding:"omitempty,oneof=pending paid declined cancelled expired"`
+}
+
+// ListMoneyRequestsResponse 查询收款请求列表响应
+type ListMoneyRequestsResponse struct {
+	Total         int64                `json:"total"`
+	Page          int                  `json:"page"`
+	PageSize      int                  `json:"page_size"`
+	MoneyRequests []model.MoneyRequest `json:"money_requests"`
+}
+
+// ListMoneyRequests 查询当前用户发起（outgoing）或需要支付（incoming）的收款请求
+// @Tags money_request
+// @Produce json
+// @Param request query ListMoneyRequestsRequest true "查询参数"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/money-requests [get]
+func ListMoneyRequests(c *gin.Context) {
+	var req ListMoneyRequestsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	baseQuery := moneyRequestQuery(db.DB(c.Request.Context()))
+	switch req.Role {
+	case "incoming":
+		baseQuery = baseQuery.Where("money_requests.payer_user_id = ? OR money_requests.paid_by_user_id = ?", user.ID, user.ID)
+	case "outgoing":
+		baseQuery = baseQuery.Where("money_requests.requester_user_id = ?", user.ID)
+	default:
+		baseQuery = baseQuery.Where("money_requests.requester_user_id = ? OR money_requests.payer_user_id = ? OR money_requests.paid_by_user_id = ?", user.ID, user.ID, user.ID)
+	}
+
+	// 过期状态不落库，按 expires_at 计算
+	now := time.Now()
+	switch model.MoneyRequestStatus(req.Status) {
+	case "":
+	case model.MoneyRequestStatusPending:
+		baseQuery = baseQuery.Where("money_requests.status = ? AND (money_requests.expires_at IS NULL OR money_requests.expires_at > ?)", model.MoneyRequestStatusPending, now)
+	case model.MoneyRequestStatusExpired:
+		baseQuery = baseQuery.Where("money_requests.status = ? AND money_requests.expires_at <= ?", model.MoneyRequestStatusPending, now)
+	default:
+		baseQuery = baseQuery.Where("money_requests.status = ?", model.MoneyRequestStatus(req.Status))
+	}
+
+	var total int64
+	if err := baseQuery.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	response := &ListMoneyRequestsResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := baseQuery.Order("money_requests.created_at DESC").Offset(offset).Limit(req.PageSize).Find(&response.MoneyRequests).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// GetMoneyRequest 通过 token 查询收款请求，指定付款用户的请求仅双方可见
+// @Tags money_request
+// @Produce json
+// @Param token path string true "收款请求 token"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/money-requests/{token} [get]
+func GetMoneyRequest(c *gin.Context) {
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	var moneyRequest model.MoneyRequest
+	if err := moneyRequestQuery(db.DB(c.Request.Context())).
+		Where("money_requests.token = ?", c.Param("token")).
+		Where("money_requests.payer_user_id IS NULL OR money_requests.requester_user_id = ? OR money_requests.payer_user_id = ?", user.ID, user.ID).
+		First(&moneyRequest).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(MoneyRequestNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(moneyRequest))
+}
+
+// PayMoneyRequestRequest 支付收款请求
+type PayMoneyRequestRequest struct {
+	PayKey string `json:"pay_key" binding:"required,max=6"`
+}
+
+// PayMoneyRequest 支付收款请求，生成关联该请求的 transfer 订单
+// @Tags money_request
+// @Accept json
+// @Produce json
+// @Param token path string true "收款请求 token"
+// @Param request body PayMoneyRequestRequest true "request body"
+// @Param Idempotency-Key header string false "幂等键，重复请求返回首次请求的响应"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/money-requests/{token}/pay [post]
+func PayMoneyRequest(c *gin.Context) {
+	var req PayMoneyRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	if subtle.ConstantTimeCompare([]byte(currentUser.PayKey), []byte(req.PayKey)) != 1 {
+		c.JSON(http.StatusBadRequest, util.Err(common.PayKeyIncorrect))
+		return
+	}
+
+	var moneyRequest *model.MoneyRequest
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			var err error
+			if moneyRequest, err = lockPendingMoneyRequest(tx, c.Param("token")); err != nil {
+				return err
+			}
+			if moneyRequest.RequesterUserID == currentUser.ID {
+				return errors.New(CannotPayOwnRequest)
+			}
+			if moneyRequest.PayerUserID != nil && *moneyRequest.PayerUserID != currentUser.ID {
+				return errors.New(NotMoneyRequestPayer)
+			}
+
+			var requester model.User
+			if err := tx.Where("id = ? AND is_active = ?", moneyRequest.RequesterUserID, true).First(&requester).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New(MoneyRequestNotFound)
+				}
+				return err
+			}
+
+			// 扣减付款人余额
+			result := tx.Model(&model.User{}).
+				Where("id = ? AND available_balance >= ?", currentUser.ID, moneyRequest.Amount).
+				UpdateColumns(map[string]interface{}{
+					"available_balance": gorm.Expr("available_balance - ?", moneyRequest.Amount),
+					"total_transfer":    gorm.Expr("total_transfer + ?", moneyRequest.Amount),
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return errors.New(common.InsufficientBalance)
+			}
+
+			// 增加收款人余额
+			if err := tx.Model(&model.User{}).
+				Where("id = ?", requester.ID).
+				UpdateColumns(map[string]interface{}{
+					"available_balance": gorm.Expr("available_balance + ?", moneyRequest.Amount),
+					"total_receive":     gorm.Expr("total_receive + ?", moneyRequest.Amount),
+				}).Error; err != nil {
+				return err
+			}
+
+			now := time.Now()
+			order := model.Order{
+				OrderName:      OrderName,
+				PayerUserID:    currentUser.ID,
+				PayeeUserID:    requester.ID,
+				Amount:         moneyRequest.Amount,
+				Status:         model.OrderStatusSuccess,
+				Type:           model.OrderTypeTransfer,
+				Remark:         moneyRequest.Note,
+				MoneyRequestID: moneyRequest.ID,
+				TradeTime:      now,
+				ExpiresAt:      now,
+			}
+			if err := tx.Create(&order).Error; err != nil {
+				return err
+			}
+
+			moneyRequest.Status = model.MoneyRequestStatusPaid
+			moneyRequest.OrderID = order.ID
+			moneyRequest.PaidByUserID = currentUser.ID
+			moneyRequest.ClosedAt = &now
+			if err := tx.Model(moneyRequest).Select("status", "order_id", "paid_by_user_id", "closed_at").Updates(moneyRequest).Error; err != nil {
+				return err
+			}
+
+			amount := moneyRequest.Amount.StringFixed(2)
+			if err := service.CreateNotification(tx, requester.ID, model.NotificationTypeMoneyRequest, NotifyTitlePaid,
+				fmt.Sprintf("%s 已支付您 %s 的收款请求", currentUser.Username, amount), moneyRequest.ID); err != nil {
+				return err
+			}
+			return service.CreateNotification(tx, currentUser.ID, model.NotificationTypeMoneyRequest, NotifyTitleSent,
+				fmt.Sprintf("您已向 %s 支付 %s", requester.Username, amount), moneyRequest.ID)
+		},
+	); err != nil {
+		respondMoneyRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(moneyRequest))
+}
+
+// DeclineMoneyRequest 付款人拒绝收款请求，仅适用于指定付款用户的请求
+// @Tags money_request
+// @Produce json
+// @Param token path string true "收款请求 token"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/money-requests/{token}/decline [post]
+func DeclineMoneyRequest(c *gin.Context) {
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	closeMoneyRequest(c, model.MoneyRequestStatusDeclined, func(moneyRequest *model.MoneyRequest) (uint64, string, string, error) {
+		if moneyRequest.PayerUserID == nil {
+			return 0, "", "", errors.New(MoneyRequestNotDeclinable)
+		}
+		if *moneyRequest.PayerUserID != currentUser.ID {
+			return 0, "", "", errors.New(NotMoneyRequestPayer)
+		}
+		return moneyRequest.RequesterUserID, NotifyTitleDeclined,
+			fmt.Sprintf("%s 拒绝了您 %s 的收款请求", currentUser.Username, moneyRequest.Amount.StringFixed(2)), nil
+	})
+}
+
+// CancelMoneyRequest 发起人取消收款请求
+// @Tags money_request
+// @Produce json
+// @Param token path string true "收款请求 token"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/money-requests/{token}/cancel [post]
+func CancelMoneyRequest(c *gin.Context) {
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	closeMoneyRequest(c, model.MoneyRequestStatusCancelled, func(moneyRequest *model.MoneyRequest) (uint64, string, string, error) {
+		if moneyRequest.RequesterUserID != currentUser.ID {
+			return 0, "", "", errors.New(MoneyRequestNotFound)
+		}
+		if moneyRequest.PayerUserID == nil {
+			return 0, "", "", nil
+		}
+		return *moneyRequest.PayerUserID, NotifyTitleCancelled,
+			fmt.Sprintf("%s 取消了 %s 的收款请求", currentUser.Username, moneyRequest.Amount.StringFixed(2)), nil
+	})
+}
@@ -0,0 +1,121 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package money_request
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/pay/internal/common"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/service"
+	"github.com/linux-do/pay/internal/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// moneyRequestQuery 收款请求查询，附带发起人和付款人用户名
+func moneyRequestQuery(tx *gorm.DB) *gorm.DB {
+	return tx.Model(&model.MoneyRequest{}).
+		Select("money_requests.*, requester_user.username as requester_username, payer_user.username as payer_username").
+		Joins("JOIN users as requester_user ON money_requests.requester_user_id = requester_user.id").
+		Joins("LEFT JOIN users as payer_user ON money_requests.payer_user_id = payer_user.id")
+}
+
+// lockPendingMoneyRequest 锁定待支付且未过期的收款请求
+func lockPendingMoneyRequest(tx *gorm.DB, token string) (*model.MoneyRequest, error) {
+	var moneyRequest model.MoneyRequest
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+		Where("token = ?", token).
+		First(&moneyRequest).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New(MoneyRequestNotFound)
+		}
+		return nil, err
+	}
+
+	switch moneyRequest.Status {
+	case model.MoneyRequestStatusPending:
+		return &moneyRequest, nil
+	case model.MoneyRequestStatusExpired:
+		return nil, errors.New(MoneyRequestExpired)
+	default:
+		return nil, errors.New(MoneyRequestNotPending)
+	}
+}
+
+// closeMoneyRequest 将待支付的收款请求关闭为指定状态
+// check 校验当前用户权限，返回需要通知的用户及通知内容，notifyUserID 为 0 表示无需通知
+func closeMoneyRequest(c *gin.Context, status model.MoneyRequestStatus, check func(*model.MoneyRequest) (notifyUserID uint64, title, content string, err error)) {
+	var moneyRequest *model.MoneyRequest
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			var err error
+			if moneyRequest, err = lockPendingMoneyRequest(tx, c.Param("token")); err != nil {
+				return err
+			}
+
+			notifyUserID, title, content, err := check(moneyRequest)
+			if err != nil {
+				return err
+			}
+
+			now := time.Now()
+			moneyRequest.Status = status
+			moneyRequest.ClosedAt = &now
+			if err := tx.Model(moneyRequest).Select("status", "closed_at").Updates(moneyRequest).Error; err != nil {
+				return err
+			}
+
+			if notifyUserID == 0 {
+				return nil
+			}
+			return service.CreateNotification(tx, notifyUserID, model.NotificationTypeMoneyRequest, title, content, moneyRequest.ID)
+		},
+	); err != nil {
+		respondMoneyRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(moneyRequest))
+}
+
+// respondMoneyRequestError 将收款请求错误映射为 HTTP 响应
+func respondMoneyRequestError(c *gin.Context, err error) {
+	switch err.Error() {
+	case MoneyRequestNotFound, PayerNotFound:
+		c.JSON(http.StatusNotFound, util.Err(err.Error()))
+	case NotMoneyRequestPayer:
+		c.JSON(http.StatusForbidden, util.Err(err.Error()))
+	case MoneyRequestNotPending, MoneyRequestExpired, MoneyRequestNotDeclinable, CannotRequestFromSelf,
+		CannotPayOwnRequest, PayerUsernameRequired, common.InsufficientBalance:
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+	}
+}
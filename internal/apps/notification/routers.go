@@ -0,0 +1,132 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package notification
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/pay/internal/apps/oauth"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/util"
+)
+
+// ListNotificationsRequest 查询通知列表请求
+type ListNotificationsRequest struct {
+	Page       int  `json:"page" form:"page" binding:"min=1"`
+	PageSize   int  `json:"page_size" form:"page_size" binding:"min=1,max=100"`
+	UnreadOnly bool `json:"unread_only" form:"unread_only"`
+}
+
+// ListNotificationsResponse 查询通知列表响应
+type ListNotificationsResponse struct {
+	Total         int64                `json:"total"`
+	Unread        int64                `json:"unread"`
+	Page          int                  `json:"page"`
+	PageSize      int                  `json:"page_size"`
+	Notifications []model.Notification `json:"notifications"`
+}
+
+// ListNotifications 查询当前用户的站内通知
+// @Tags notification
+// @Produce json
+// @Param request query ListNotificationsRequest true "查询参数"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/notifications [get]
+func ListNotifications(c *gin.Context) {
+	var req ListNotificationsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	response := &ListNotificationsResponse{
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
+	if err := db.DB(c.Request.Context()).Model(&model.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", user.ID).
+		Count(&response.Unread).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	baseQuery := db.DB(c.Request.Context()).Model(&model.Notification{}).Where("user_id = ?", user.ID)
+	if req.UnreadOnly {
+		baseQuery = baseQuery.Where("read_at IS NULL")
+	}
+
+	if err := baseQuery.Count(&response.Total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := baseQuery.Order("created_at DESC").Offset(offset).Limit(req.PageSize).Find(&response.Notifications).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// MarkNotificationsReadRequest 标记通知已读请求，不传 ids 表示全部已读
+type MarkNotificationsReadRequest struct {
+	IDs []uint64 `json:"ids" binding:"omitempty,max=100"`
+}
+
+// MarkNotificationsRead 标记当前用户的通知为已读
+// @Tags notification
+// @Accept json
+// @Produce json
+// @Param request body MarkNotificationsReadRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/notifications/read [post]
+func MarkNotificationsRead(c *gin.Context) {
+	var req MarkNotificationsReadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	query := db.DB(c.Request.Context()).Model(&model.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", user.ID)
+	if len(req.IDs) > 0 {
+		query = query.Where("id IN ?", req.IDs)
+	}
+	if err := query.Update("read_at", time.Now()).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
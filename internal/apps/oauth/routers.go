@@ -111,6 +111,8 @@ type BasicUserInfo struct {
 	TotalTransfer    decimal.Decimal  `json:"total_transfer"`
 	TotalCommunity   decimal.Decimal  `json:"total_community"`
 	AvailableBalance decimal.Decimal  `json:"available_balance"`
+	EscrowBalance    decimal.Decimal  `json:"escrow_balance"`
+	HeldBalance      decimal.Decimal  `json:"held_balance"`
 	PayScore         int64            `json:"pay_score"`
 	IsPayKey         bool             `json:"is_pay_key"`
 	IsAdmin          bool             `json:"is_admin"`
@@ -157,6 +159,8 @@ func UserInfo(c *gin.Context) {
 			TotalTransfer:    user.TotalTransfer,
 			TotalCommunity:   user.TotalCommunity,
 			AvailableBalance: user.AvailableBalance,
+			EscrowBalance:    user.EscrowBalance,
+			HeldBalance:      user.HeldBalance,
 			PayScore:         user.PayScore,
 			IsPayKey:         user.PayKey != "",
 			IsAdmin:          user.IsAdmin,
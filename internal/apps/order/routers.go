@@ -33,13 +33,14 @@ import (
 	"github.com/linux-do/pay/internal/db"
 	"github.com/linux-do/pay/internal/model"
 	"github.com/linux-do/pay/internal/util"
+	"github.com/shopspring/decimal"
 )
 
 type TransactionListRequest struct {
 	Page      int        `json:"page" form:"page" binding:"min=1"`
 	PageSize  int        `json:"page_size" form:"page_size" binding:"min=1,max=100"`
-	Type      string     `json:"type" form:"type" binding:"omitempty,oneof=receive payment transfer community"`
-	Status    string     `json:"status" form:"status" binding:"omitempty,oneof=success pending failed expired disputing refund refused"`
+	Type      string     `json:"type" form:"type" binding:"omitempty,oneof=receive payment transfer community payout escrow red_packet redeem"`
+	Status    string     `json:"status" form:"status" binding:"omitempty,oneof=success pending failed expired disputing refund refused cancelled held authorized"`
 	ClientID  string     `json:"client_id" form:"client_id" binding:"omitempty"`
 	StartTime *time.Time `json:"startTime" form:"startTime" binding:"omitempty"`
 	EndTime   *time.Time `json:"endTime" form:"endTime" binding:"omitempty,gtfield=StartTime"`
@@ -58,6 +59,8 @@ type TransactionListResponse struct {
 		DisputeID      *uint64 `json:"dispute_id"`
 		PayerUsername  string  `json:"payer_username"`
 		PayeeUsername  string  `json:"payee_username"`
+		// SplitAmount 当前用户作为分账收款人时的实收分账金额
+		SplitAmount *decimal.Decimal `json:"split_amount"`
 	} `json:"orders"`
 }
 
@@ -78,28 +81,32 @@ func ListTransactions(c *gin.Context) {
 	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
 
 	baseQuery := db.DB(c.Request.Context()).Model(&model.Order{}).
-		Select("orders.*, merchant_api_keys.app_name, merchant_api_keys.app_homepage_url, merchant_api_keys.app_description, merchant_api_keys.redirect_uri, disputes.id as dispute_id, payer_user.username as payer_username, payee_user.username as payee_username").
+		Select("orders.*, merchant_api_keys.app_name, merchant_api_keys.app_homepage_url, merchant_api_keys.app_description, merchant_api_keys.redirect_uri, disputes.id as dispute_id, payer_user.username as payer_username, payee_user.username as payee_username, my_split.net_amount as split_amount").
 		Joins("LEFT JOIN merchant_api_keys ON orders.client_id = merchant_api_keys.client_id").
 		Joins("LEFT JOIN disputes ON orders.id = disputes.order_id").
 		Joins("LEFT JOIN users as payer_user ON orders.payer_user_id = payer_user.id").
-		Joins("LEFT JOIN users as payee_user ON orders.payee_user_id = payee_user.id")
+		Joins("LEFT JOIN users as payee_user ON orders.payee_user_id = payee_user.id").
+		Joins("LEFT JOIN order_splits as my_split ON orders.id = my_split.order_id AND my_split.recipient_user_id = ? AND my_split.status <> ?", user.ID, model.OrderSplitStatusPending)
 
 	if req.Type != "" {
 		orderType := model.OrderType(req.Type)
 
 		switch orderType {
 		case model.OrderTypeReceive:
-			// receive 类型：查询当前用户作为收款方的 payment 订单
-			baseQuery = baseQuery.Where("orders.type = ? AND orders.payee_user_id = ?", model.OrderTypePayment, user.ID)
+			// receive 类型：查询当前用户作为收款方或分账收款人的 payment 订单
+			baseQuery = baseQuery.Where("orders.type = ? AND (orders.payee_user_id = ? OR my_split.id IS NOT NULL)", model.OrderTypePayment, user.ID)
 		case model.OrderTypeCommunity:
 			// community 类型：查询当前用户作为收款方的 community 订单
 			baseQuery = baseQuery.Where("orders.type = ? AND orders.payee_user_id = ?", orderType, user.ID)
 		case model.OrderTypePayment, model.OrderTypeTransfer:
 			// payment 和 transfer 类型：查询当前用户作为付款方的订单
 			baseQuery = baseQuery.Where("orders.type = ? AND orders.payer_user_id = ?", orderType, user.ID)
+		case model.OrderTypePayout, model.OrderTypeEscrow, model.OrderTypeRedPacket, model.OrderTypeRedeem:
+			// payout、escrow、red_packet 和 redeem 类型：付款方和收款方双方可见
+			baseQuery = baseQuery.Where("orders.type = ? AND (orders.payer_user_id = ? OR orders.payee_user_id = ?)", orderType, user.ID, user.ID)
 		}
 	} else {
-		baseQuery = baseQuery.Where("orders.payee_user_id = ? OR orders.payer_user_id = ?", user.ID, user.ID)
+		baseQuery = baseQuery.Where("orders.payee_user_id = ? OR orders.payer_user_id = ? OR my_split.id IS NOT NULL", user.ID, user.ID)
 	}
 
 	if req.Status != "" {
@@ -134,9 +141,9 @@ func ListTransactions(c *gin.Context) {
 		return
 	}
 
-	// 转换订单类型：从收款方视角看，payment 订单应该显示为 receive
+	// 转换订单类型：从收款方和分账收款人视角看，payment 订单应该显示为 receive
 	for i := range response.Orders {
-		if response.Orders[i].Type == model.OrderTypePayment && response.Orders[i].PayeeUserID == user.ID {
+		if response.Orders[i].Type == model.OrderTypePayment && (response.Orders[i].PayeeUserID == user.ID || response.Orders[i].SplitAmount != nil) {
 			response.Orders[i].Type = model.OrderTypeReceive
 		}
 	}
@@ -24,14 +24,47 @@
 
 package payment
 
+import "time"
+
 const (
 	APIKeyObjKey          = "payment_api_key_obj"
 	CreateOrderRequestKey = "payment_create_order_request"
 )
 
+const (
+	// OrderEventName SSE 推送订单状态变更的事件名
+	OrderEventName = "order"
+	// OrderEventHeartbeatInterval SSE 心跳间隔，避免连接被代理断开
+	OrderEventHeartbeatInterval = 15 * time.Second
+)
+
+const (
+	// AllowanceRemark 免密支付订单备注
+	AllowanceRemark = "[系统]: 免密支付"
+	// AuthorizationRemark 预授权订单支付时的备注
+	AuthorizationRemark = "[系统]: 预授权冻结付款人余额"
+)
+
+const (
+	// ChargeOrderNoLockClass 免密扣款按商户订单号加锁时使用的 advisory lock 分类键
+	ChargeOrderNoLockClass = 2001
+)
+
+const (
+	// OrderNameMaxLength 订单名称最大字符数
+	OrderNameMaxLength = 64
+)
+
+const (
+	// EPayParamMetadataKey 易支付 param 参数在订单 metadata 中的存储键
+	EPayParamMetadataKey = "param"
+)
+
 const (
 	// OrderMerchantIDCacheKeyFormat Redis key 格式，用于存储订单号对应的商户ID
 	OrderMerchantIDCacheKeyFormat = "payment:order:%s"
 	// OrderExpireKeyFormat Redis key 格式，用于订单过期监听，key中包含订单ID
 	OrderExpireKeyFormat = "payment:order:expire:%d"
+	// SignNonceCacheKeyFormat Redis key 格式，用于记录商户请求已使用的 nonce，key中包含ClientID和nonce
+	SignNonceCacheKeyFormat = "payment:nonce:%s:%s"
 )
@@ -35,4 +35,27 @@ const (
 	CannotTransferToSelf     = "不能转账给自己"
 	PayConfigNotFound        = "支付配置不存在"
 	SystemConfigValueInvalid = "系统配置 %s 的值无法转换为整数: %v"
+	SignatureInvalid         = "签名验证失败"
+	SignRequired             = "该 API Key 已开启强制防重放，请求必须携带 sign"
+	NonceParamsRequired      = "缺少 timestamp 或 nonce 参数"
+	TimestampInvalid         = "timestamp 参数格式错误"
+	TimestampExpired         = "请求已过期，请检查 timestamp"
+	NonceReused              = "nonce 已被使用"
+	ExpireMinutesOutOfRange  = "订单过期时间超出允许范围"
+	TimeoutExpressInvalid    = "timeout_express 参数格式错误"
+	PayerNotFound            = "付款用户不存在"
+	APIKeyScopeDenied        = "API Key 未开通 %s 权限"
+	SplitRuleInvalid         = "分账规则无效：金额与比例必须且只能指定一个，金额最多 2 位小数，比例须在 0 到 100 之间"
+	SplitRecipientInvalid    = "分账收款人不存在、重复或为商户本身"
+	SplitAmountExceeded      = "分账金额合计不能超过订单金额"
+	PreAuthSplitUnsupported  = "预授权订单不支持分账"
+	AuthorizationNotFound    = "预授权订单不存在或已处理"
+	CaptureAmountExceeded    = "确认扣款金额不能超过预授权金额"
+	HeldBalanceMismatch      = "付款人冻结余额不足，请联系管理员"
+	ItemUnitPriceInvalid     = "商品单价必须大于0且最多2位小数"
+	ItemsAmountMismatch      = "订单金额与商品明细合计不一致"
+	MerchantOrderNoConflict  = "商户订单号已被其他订单使用"
+	RefundItemsInvalid       = "items 参数格式错误，应为 商品行ID:数量，多个以逗号分隔"
+	RefundItemInvalid        = "退款商品行不存在或可退数量不足"
+	RefundAmountMismatch     = "退款金额与可退金额不一致"
 )
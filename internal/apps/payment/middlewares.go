@@ -26,6 +26,7 @@ package payment
 
 import (
 	"encoding/base64"
+	"fmt"
 	"net/http"
 	"strings"
 
@@ -39,11 +40,33 @@ import (
 
 // CreateOrderRequest 商户创建订单统一请求
 type CreateOrderRequest struct {
-	OrderName       string          `json:"order_name" binding:"required,max=64"`
-	MerchantOrderNo string          `json:"merchant_order_no"`
-	Amount          decimal.Decimal `json:"amount" binding:"required"`
-	Remark          string          `json:"remark" binding:"max=100"`
-	PaymentType     string          `json:"payment_type"`
+	OrderName       string           `json:"order_name" binding:"required_without=Items,max=64"`
+	MerchantOrderNo string           `json:"merchant_order_no"`
+	Amount          decimal.Decimal  `json:"amount" binding:"required"`
+	Remark          string           `json:"remark" binding:"max=100"`
+	PaymentType     string           `json:"payment_type"`
+	Metadata        util.StringMap   `json:"metadata" binding:"omitempty,max=20,dive,keys,max=64,endkeys,max=255" swaggertype:"object,string"`
+	ExpireMinutes   int              `json:"expire_minutes" binding:"omitempty,min=1"`
+	Splits          []OrderSplitRule `json:"splits" binding:"omitempty,max=10,dive"`
+	// PreAuth 预授权订单，支付时仅冻结付款人余额，由商户确认扣款或撤销
+	PreAuth bool `json:"pre_auth"`
+	// Items 商品明细，提供时订单金额由明细合计得出，amount 可省略，order_name 为空时取自首个商品
+	Items []OrderItemRequest `json:"items" binding:"omitempty,max=100,dive"`
+}
+
+// OrderItemRequest 订单商品明细
+type OrderItemRequest struct {
+	Name      string          `json:"name" binding:"required,max=64"`
+	SKU       string          `json:"sku" binding:"max=64"`
+	UnitPrice decimal.Decimal `json:"unit_price" binding:"required"`
+	Quantity  int             `json:"quantity" binding:"required,min=1,max=10000"`
+}
+
+// OrderSplitRule 订单分账规则，amount 与 percentage 二选一
+type OrderSplitRule struct {
+	RecipientUserID uint64          `json:"recipient_user_id" binding:"required"`
+	Amount          decimal.Decimal `json:"amount"`
+	Percentage      decimal.Decimal `json:"percentage"`
 }
 
 // EPayRequest 易支付请求
@@ -58,16 +81,25 @@ type EPayRequest struct {
 	Sign            string          `form:"sign" binding:"required"`
 	PayType         string          `form:"type" binding:"required"`
 	SignType        string          `form:"sign_type"`
+	Param           string          `form:"param" binding:"max=255"`
+	Timestamp       string          `form:"timestamp"`
+	Nonce           string          `form:"nonce" binding:"max=64"`
+	TimeoutExpress  string          `form:"timeout_express" binding:"max=10"`
 }
 
 // ToCreateOrderRequest 转换为通用创建订单请求
-func (r *EPayRequest) ToCreateOrderRequest() *CreateOrderRequest {
-	return &CreateOrderRequest{
+func (r *EPayRequest) ToCreateOrderRequest(expireMinutes int) *CreateOrderRequest {
+	req := &CreateOrderRequest{
 		OrderName:       r.OrderName,
 		MerchantOrderNo: r.MerchantOrderNo,
 		Amount:          r.Amount,
 		PaymentType:     r.PayType,
+		ExpireMinutes:   expireMinutes,
 	}
+	if r.Param != "" {
+		req.Metadata = util.StringMap{EPayParamMetadataKey: r.Param}
+	}
+	return req
 }
 
 // RequireMerchantAuth 验证商户 ClientID/ClientSecret（Basic Auth）
@@ -118,6 +150,19 @@ func RequireMerchantAuth() gin.HandlerFunc {
 	}
 }
 
+// RequireAPIKeyScope 校验已认证的商户 API Key 拥有指定权限，需在 RequireMerchantAuth 之后使用
+func RequireAPIKeyScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, APIKeyObjKey)
+		if !apiKey.HasScope(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, util.Err(fmt.Sprintf(APIKeyScopeDenied, scope)))
+			return
+		}
+
+		c.Next()
+	}
+}
+
 // RequireSignatureAuth 验证签名
 func RequireSignatureAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -128,7 +173,12 @@ func RequireSignatureAuth() gin.HandlerFunc {
 		switch PayType {
 		case common.PayTypeEPay:
 			if createOrderReq, err := VerifySignature(c, &apiKey); err != nil {
-				c.AbortWithStatusJSON(http.StatusUnauthorized, util.Err(err.Error()))
+				// 参数格式错误属于请求错误，其余视为鉴权失败
+				if err.Error() == TimeoutExpressInvalid {
+					c.AbortWithStatusJSON(http.StatusBadRequest, util.Err(err.Error()))
+				} else {
+					c.AbortWithStatusJSON(http.StatusUnauthorized, util.Err(err.Error()))
+				}
 				return
 			} else {
 				util.SetToContext(c, CreateOrderRequestKey, createOrderReq)
@@ -26,22 +26,18 @@ package payment
 
 import (
 	"crypto/subtle"
-	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
-	"net/url"
 	"strconv"
 	"time"
 
-	"github.com/hibiken/asynq"
 	"github.com/linux-do/pay/internal/apps/oauth"
 	"github.com/linux-do/pay/internal/common"
-	"github.com/linux-do/pay/internal/config"
+	"github.com/linux-do/pay/internal/listener"
 	"github.com/linux-do/pay/internal/service"
-	"github.com/linux-do/pay/internal/task"
-	"github.com/linux-do/pay/internal/task/schedule"
 
 	"github.com/gin-gonic/gin"
 	"github.com/linux-do/pay/internal/db"
@@ -55,7 +51,10 @@ import (
 // PayOrderRequest 用户支付订单请求
 type PayOrderRequest struct {
 	OrderNo string `json:"order_no" binding:"required"`
-	PayKey  string `json:"pay_key" binding:"required,max=6"`
+	// PayKey 为空时使用用户授予该商户应用的免密支付额度
+	PayKey string `json:"pay_key" binding:"max=6"`
+	// CouponCode 使用的商户优惠码，按减免后的金额支付
+	CouponCode string `json:"coupon_code" binding:"max=32"`
 }
 
 // GetOrderRequest 查询订单请求
@@ -71,14 +70,15 @@ type MerchantInfo struct {
 
 // GetOrderResponse 查询订单响应
 type GetOrderResponse struct {
-	Order    *model.Order    `json:"order"`
-	FeeRate  decimal.Decimal `json:"fee_rate"`
-	Merchant MerchantInfo    `json:"merchant"`
+	Order    *model.Order      `json:"order"`
+	Items    []model.OrderItem `json:"items"`
+	FeeRate  decimal.Decimal   `json:"fee_rate"`
+	Merchant MerchantInfo      `json:"merchant"`
 }
 
-// TransferRequest 转账请求
+// TransferRequest 转账请求，recipient_id 为空时仅按用户名查找收款人
 type TransferRequest struct {
-	RecipientID       uint64          `json:"recipient_id" binding:"required"`
+	RecipientID       uint64          `json:"recipient_id"`
 	RecipientUsername string          `json:"recipient_username" binding:"required"`
 	Amount            decimal.Decimal `json:"amount" binding:"required"`
 	PayKey            string          `json:"pay_key" binding:"required,max=6"`
@@ -95,12 +95,20 @@ type QueryOrderRequest struct {
 }
 
 // RefundOrderRequest 商户退款请求
+// 鉴权方式：传入 sign 或 API Key 开启强制防重放时使用签名模式，sign 为除 sign、sign_type 外全部非空参数的 MD5 签名，且必须携带 timestamp 与 nonce；
+// 否则沿用旧版方式，以 key 传入 client_secret。旧版方式将逐步淘汰，建议尽快改用签名模式并开启强制防重放
+// items 按商品行部分退款，格式为 商品行ID:数量，多个以逗号分隔，退款金额为该行按比例分摊优惠后的实付金额；不传时退还订单剩余全部金额
 type RefundOrderRequest struct {
 	ClientID        string          `form:"pid" json:"pid" binding:"required"`
-	ClientSecret    string          `form:"key" json:"key" binding:"required"`
+	ClientSecret    string          `form:"key" json:"key"`
 	MerchantOrderNo string          `form:"out_trade_no" json:"out_trade_no"`
 	TradeNo         uint64          `form:"trade_no" json:"trade_no" binding:"required"`
 	Amount          decimal.Decimal `form:"money" json:"money" binding:"required"`
+	Items           string          `form:"items" json:"items" binding:"max=1024"`
+	Timestamp       string          `form:"timestamp" json:"timestamp"`
+	Nonce           string          `form:"nonce" json:"nonce" binding:"max=64"`
+	Sign            string          `form:"sign" json:"sign"`
+	SignType        string          `form:"sign_type" json:"sign_type"`
 }
 
 // CreateMerchantOrder 商户创建订单接口
@@ -114,80 +122,294 @@ func CreateMerchantOrder(c *gin.Context) {
 	req, _ := util.GetFromContext[*CreateOrderRequest](c, CreateOrderRequestKey)
 	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, APIKeyObjKey)
 
-	// 获取商户用户信息
+	_, payURL, err := createMerchantOrder(c.Request.Context(), apiKey, req)
+	if err != nil {
+		if isOrderRequestError(err) {
+			c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	c.Redirect(http.StatusFound, payURL)
+}
+
+// CreateMerchantOrderAPIResponse 易支付 API 模式创建订单响应
+type CreateMerchantOrderAPIResponse struct {
+	Code    int    `json:"code" example:"1"`
+	Msg     string `json:"msg" example:"创建订单成功"`
+	TradeNo string `json:"trade_no" example:"123456"`
+	PayURL  string `json:"payurl"`
+	QRCode  string `json:"qrcode"`
+}
+
+// CreateMerchantOrderAPI 商户创建订单接口（API 模式，返回收银台地址而不跳转）
+// @Tags payment
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param request body CreateOrderRequest true "request body"
+// @Success 200 {object} CreateMerchantOrderAPIResponse
+// @Router /mapi.php [post]
+func CreateMerchantOrderAPI(c *gin.Context) {
+	req, _ := util.GetFromContext[*CreateOrderRequest](c, CreateOrderRequestKey)
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, APIKeyObjKey)
+
+	order, payURL, err := createMerchantOrder(c.Request.Context(), apiKey, req)
+	if err != nil {
+		if isOrderRequestError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"code": -1, "msg": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"code": -1, "msg": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, CreateMerchantOrderAPIResponse{
+		Code:    1,
+		Msg:     "创建订单成功",
+		TradeNo: strconv.FormatUint(order.ID, 10),
+		PayURL:  payURL,
+		QRCode:  payURL,
+	})
+}
+
+// CreateNativeOrderResponse 商户 API 创建订单响应
+type CreateNativeOrderResponse struct {
+	TradeNo   string    `json:"trade_no" example:"123456"`
+	PayURL    string    `json:"pay_url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// CreateNativeOrder 商户通过 API 创建订单接口（Basic Auth）
+// @Tags payment
+// @Accept json
+// @Produce json
+// @Param request body CreateOrderRequest true "request body"
+// @Success 200 {object} CreateNativeOrderResponse
+// @Router /api/v1/merchant/orders [post]
+func CreateNativeOrder(c *gin.Context) {
+	var req CreateOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	if err := applyOrderItems(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	if req.Amount.LessThanOrEqual(decimal.Zero) {
+		c.JSON(http.StatusBadRequest, util.Err(common.AmountMustBeGreaterThanZero))
+		return
+	}
+
+	if req.Amount.Exponent() < -2 {
+		c.JSON(http.StatusBadRequest, util.Err(common.AmountDecimalPlacesExceeded))
+		return
+	}
+
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, APIKeyObjKey)
+
+	order, payURL, err := createMerchantOrder(c.Request.Context(), apiKey, &req)
+	if err != nil {
+		if isOrderRequestError(err) {
+			c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(CreateNativeOrderResponse{
+		TradeNo:   strconv.FormatUint(order.ID, 10),
+		PayURL:    payURL,
+		ExpiresAt: order.ExpiresAt,
+	}))
+}
+
+// ChargeUserRequest 商户免密扣款请求
+type ChargeUserRequest struct {
+	UserID          uint64          `json:"user_id" binding:"required"`
+	OrderName       string          `json:"order_name" binding:"required,max=64"`
+	MerchantOrderNo string          `json:"merchant_order_no" binding:"max=64"`
+	Amount          decimal.Decimal `json:"amount" binding:"required"`
+	Remark          string          `json:"remark" binding:"max=100"`
+	Metadata        util.StringMap  `json:"metadata" binding:"omitempty,max=20,dive,keys,max=64,endkeys,max=255" swaggertype:"object,string"`
+}
+
+// ChargeUserResponse 商户免密扣款响应
+type ChargeUserResponse struct {
+	TradeNo   string          `json:"trade_no" example:"123456"`
+	Amount    decimal.Decimal `json:"amount"`
+	TradeTime time.Time       `json:"trade_time"`
+}
+
+// ChargeUser 商户在用户授予的免密额度内直接扣款（Basic Auth）
+// @Tags payment
+// @Accept json
+// @Produce json
+// @Param request body ChargeUserRequest true "request body"
+// @Param Idempotency-Key header string false "幂等键，重复请求返回首次请求的响应"
+// @Success 200 {object} ChargeUserResponse
+// @Router /api/v1/merchant/charges [post]
+func ChargeUser(c *gin.Context) {
+	var req ChargeUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	if req.Amount.LessThanOrEqual(decimal.Zero) {
+		c.JSON(http.StatusBadRequest, util.Err(common.AmountMustBeGreaterThanZero))
+		return
+	}
+	if req.Amount.Exponent() < -2 {
+		c.JSON(http.StatusBadRequest, util.Err(common.AmountDecimalPlacesExceeded))
+		return
+	}
+
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, APIKeyObjKey)
+
+	var payerUser model.User
+	if err := db.DB(c.Request.Context()).Where("id = ? AND is_active = ?", req.UserID, true).First(&payerUser).Error; err != nil {
+		c.JSON(http.StatusNotFound, util.Err(PayerNotFound))
+		return
+	}
+
 	var merchantUser model.User
 	if err := db.DB(c.Request.Context()).Where("id = ? AND is_active = ?", apiKey.UserID, true).First(&merchantUser).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, util.Err(MerchantInfoNotFound))
+		c.JSON(http.StatusBadRequest, util.Err(MerchantInfoNotFound))
 		return
 	}
 
-	// 获取商家订单过期时间（分钟）
-	expireMinutes, errGet := model.GetIntByKey(c.Request.Context(), model.ConfigKeyMerchantOrderExpireMinutes)
-	if errGet != nil {
-		c.JSON(http.StatusInternalServerError, util.Err(errGet.Error()))
+	if payerUser.ID == merchantUser.ID {
+		c.JSON(http.StatusBadRequest, util.Err(common.CannotPaySelf))
 		return
 	}
 
-	var payURL string
+	var payerPayConfig, merchantPayConfig model.UserPayConfig
+	if err := payerPayConfig.GetByPayScore(db.DB(c.Request.Context()), payerUser.PayScore); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+	if err := merchantPayConfig.GetByPayScore(db.DB(c.Request.Context()), merchantUser.PayScore); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
 
+	var order model.Order
+	var duplicated bool
 	if err := db.DB(c.Request.Context()).Transaction(
 		func(tx *gorm.DB) error {
-			// 创建订单
-			order := model.Order{
+			// 商户订单号重复时直接返回已有订单，不重复扣款
+			if req.MerchantOrderNo != "" {
+				existing, err := findChargeOrder(tx, apiKey.ClientID, &req)
+				if err != nil {
+					return err
+				}
+				if existing != nil {
+					order = *existing
+					duplicated = true
+					return nil
+				}
+			}
+
+			// 校验免密支付额度
+			allowance, err := service.ConsumeAllowance(tx, payerUser.ID, apiKey.ClientID, req.Amount)
+			if err != nil {
+				return err
+			}
+
+			// 检查每日限额
+			if err := service.CheckDailyLimit(tx, payerUser.ID, req.Amount, payerPayConfig.DailyLimit); err != nil {
+				return err
+			}
+
+			// 检查商户应用的金额范围和每日收款限额
+			if err := service.CheckMerchantLimits(tx, apiKey, req.Amount); err != nil {
+				return err
+			}
+
+			// 计算手续费
+			_, merchantAmount, feePercent := service.CalculateFee(req.Amount, merchantPayConfig.FeeRate)
+			remark := fmt.Sprintf("%s [系统]: 收取商家%d%%手续费", AllowanceRemark, feePercent)
+			if req.Remark != "" {
+				remark = req.Remark + " " + remark
+			}
+
+			now := time.Now()
+			order = model.Order{
 				OrderName:       req.OrderName,
-				ClientID:        apiKey.ClientID,
 				MerchantOrderNo: req.MerchantOrderNo,
+				ClientID:        apiKey.ClientID,
+				PayerUserID:     payerUser.ID,
 				PayeeUserID:     merchantUser.ID,
 				Amount:          req.Amount,
-				Status:          model.OrderStatusPending,
+				Status:          model.OrderStatusSuccess,
 				Type:            model.OrderTypePayment,
-				Remark:          req.Remark,
-				PaymentType:     req.PaymentType,
-				ExpiresAt:       time.Now().Add(time.Duration(expireMinutes) * time.Minute),
+				Remark:          remark,
+				Metadata:        req.Metadata,
+				AllowanceID:     allowance.ID,
+				TradeTime:       now,
+				ExpiresAt:       now,
 			}
 			if err := tx.Create(&order).Error; err != nil {
 				return err
 			}
 
-			encryptString, err := util.Encrypt(merchantUser.SignKey, strconv.FormatUint(order.ID, 10))
-			if err != nil {
+			// 扣减用户余额
+			if err := service.DeductUserBalance(tx, payerUser.ID, req.Amount); err != nil {
 				return err
 			}
 
-			merchantIDStr := strconv.FormatUint(merchantUser.ID, 10)
-			if errSet := db.Redis.Set(c.Request.Context(), fmt.Sprintf(OrderMerchantIDCacheKeyFormat, encryptString), merchantIDStr, time.Duration(expireMinutes)*time.Minute).Err(); errSet != nil {
-				return fmt.Errorf("failed to set redis key: %w", errSet)
-			}
-
-			expireKey := fmt.Sprintf(OrderExpireKeyFormat, order.ID)
-			if errSet := db.Redis.Set(c.Request.Context(), expireKey, order.ID, time.Duration(expireMinutes)*time.Minute).Err(); errSet != nil {
-				return fmt.Errorf("failed to set order expire key: %w", errSet)
+			// 增加商户余额和积分
+			merchantScoreIncrease := req.Amount.Mul(merchantPayConfig.ScoreRate).Round(0).IntPart()
+			if err := service.AddMerchantBalance(tx, merchantUser.ID, merchantAmount, merchantScoreIncrease); err != nil {
+				return err
 			}
 
-			payURL = fmt.Sprintf("%s?order_no=%s", config.Config.App.FrontendPayURL, url.QueryEscape(encryptString))
-			return nil
+			// 下发商户回调任务
+			return EnqueueMerchantNotify(&order)
 		},
 	); err != nil {
-		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		errMsg := err.Error()
+		if errMsg == common.InsufficientBalance || errMsg == common.DailyLimitExceeded ||
+			isOrderRequestError(err) || service.IsAllowanceError(err) {
+			c.JSON(http.StatusBadRequest, util.Err(errMsg))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(errMsg))
+		}
 		return
 	}
 
-	c.Redirect(http.StatusFound, payURL)
+	if !duplicated {
+		service.PublishOrderEvent(c.Request.Context(), &order)
+	}
+
+	c.JSON(http.StatusOK, util.OK(ChargeUserResponse{
+		TradeNo:   strconv.FormatUint(order.ID, 10),
+		Amount:    order.Amount,
+		TradeTime: order.TradeTime,
+	}))
 }
 
 // QueryMerchantOrderResponse 查询订单响应
 type QueryMerchantOrderResponse struct {
-	Code       int    `json:"code" example:"1"`
-	Msg        string `json:"msg" example:"查询订单号成功！"`
-	TradeNo    string `json:"trade_no" example:"123456"`
-	OutTradeNo string `json:"out_trade_no" example:"M202312080001"`
-	Type       string `json:"type" example:"epay"`
-	Pid        string `json:"pid" example:"1001"`
-	AddTime    string `json:"addtime" example:"2023-12-08 12:00:00"`
-	EndTime    string `json:"endtime" example:"2023-12-08 12:05:00"`
-	Name       string `json:"name" example:"商品名称"`
-	Money      string `json:"money" example:"10.00"`
-	Status     int    `json:"status" example:"1"`
+	Code       int            `json:"code" example:"1"`
+	Msg        string         `json:"msg" example:"查询订单号成功！"`
+	TradeNo    string         `json:"trade_no" example:"123456"`
+	OutTradeNo string         `json:"out_trade_no" example:"M202312080001"`
+	Type       string         `json:"type" example:"epay"`
+	Pid        string         `json:"pid" example:"1001"`
+	AddTime    string         `json:"addtime" example:"2023-12-08 12:00:00"`
+	EndTime    string         `json:"endtime" example:"2023-12-08 12:05:00"`
+	Name       string         `json:"name" example:"商品名称"`
+	Money      string         `json:"money" example:"10.00"`
+	Status     int            `json:"status" example:"1"`
+	Param      string         `json:"param" example:""`
+	Metadata   util.StringMap `json:"metadata" swaggertype:"object,string"`
 }
 
 // QueryMerchantOrder 商户主动查询订单状态接口
@@ -237,6 +459,9 @@ func QueryMerchantOrder(c *gin.Context) {
 		"name":         order.OrderName,
 		"money":        order.Amount.Truncate(2).StringFixed(2),
 		"status":       statusInt,
+		"param":        order.Metadata[EPayParamMetadataKey],
+		"metadata":     order.Metadata,
+		"link_id":      order.MerchantPaymentLinkID,
 	})
 }
 
@@ -247,6 +472,8 @@ type RefundMerchantOrderResponse struct {
 }
 
 // RefundMerchantOrder 商户退款接口
+// @Description 鉴权迁移说明：传入 sign 时使用签名模式，sign 为除 sign、sign_type 外全部非空参数（含 items、timestamp、nonce）的 MD5 签名，且必须携带 timestamp 与 nonce；
+// @Description API Key 开启强制防重放（require_nonce）后只接受签名模式。未开启时仍可沿用旧版方式以 key 传入 client_secret，旧版方式将逐步淘汰，建议尽快迁移到签名模式后开启强制防重放。
 // @Tags payment
 // @Accept json
 // @Produce json
@@ -270,16 +497,57 @@ func RefundMerchantOrder(c *gin.Context) {
 		return
 	}
 
+	refundItems, errItems := parseRefundItems(req.Items)
+	if errItems != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": -1, "msg": errItems.Error()})
+		return
+	}
+
 	var apiKey model.MerchantAPIKey
-	if err := db.DB(c.Request.Context()).Where("client_id = ? AND client_secret = ?", req.ClientID, req.ClientSecret).First(&apiKey).Error; err != nil {
+	if err := apiKey.GetByClientID(db.DB(c.Request.Context()), req.ClientID); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"code": -1, "msg": MerchantInfoNotFound})
 		return
 	}
 
+	if apiKey.RequireNonce || req.Sign != "" {
+		// 签名模式：签名覆盖 timestamp 与 nonce，先验签再占用 nonce，截获的请求无法改写防重放参数
+		if req.Sign == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"code": -1, "msg": SignRequired})
+			return
+		}
+		if req.Timestamp == "" || req.Nonce == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"code": -1, "msg": NonceParamsRequired})
+			return
+		}
+
+		params := map[string]string{
+			"pid":          req.ClientID,
+			"out_trade_no": req.MerchantOrderNo,
+			"trade_no":     strconv.FormatUint(req.TradeNo, 10),
+			"money":        req.Amount.Truncate(2).StringFixed(2),
+			"items":        req.Items,
+			"timestamp":    req.Timestamp,
+			"nonce":        req.Nonce,
+		}
+		if !verifySign(params, apiKey.ClientSecret, req.Sign) {
+			c.JSON(http.StatusUnauthorized, gin.H{"code": -1, "msg": SignatureInvalid})
+			return
+		}
+	} else if req.ClientSecret == "" || subtle.ConstantTimeCompare([]byte(req.ClientSecret), []byte(apiKey.ClientSecret)) != 1 {
+		// 旧版方式：以 key 直接传入 client_secret
+		c.JSON(http.StatusBadRequest, gin.H{"code": -1, "msg": MerchantInfoNotFound})
+		return
+	}
+
+	if err := VerifyNonce(c.Request.Context(), &apiKey, req.Timestamp, req.Nonce); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"code": -1, "msg": err.Error()})
+		return
+	}
+
+	var order model.Order
 	if err := db.DB(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
-		var order model.Order
 		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
-			Where("id = ? AND client_id = ? AND status = ? AND amount = ?", req.TradeNo, req.ClientID, model.OrderStatusSuccess, req.Amount).
+			Where("id = ? AND client_id = ? AND status = ? AND type = ?", req.TradeNo, req.ClientID, model.OrderStatusSuccess, model.OrderTypePayment).
 			First(&order).Error; err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return errors.New(OrderNotFound)
@@ -287,6 +555,15 @@ func RefundMerchantOrder(c *gin.Context) {
 			return err
 		}
 
+		// 未指定商品行时退还订单剩余全部金额，指定时按商品行累计退款
+		refundAmount, err := refundOrderItems(tx, &order, refundItems)
+		if err != nil {
+			return err
+		}
+		if !refundAmount.Equal(req.Amount) {
+			return errors.New(RefundAmountMismatch)
+		}
+
 		var payerUser model.User
 		if err := payerUser.GetByID(tx, order.PayerUserID); err != nil {
 			return err
@@ -302,12 +579,19 @@ func RefundMerchantOrder(c *gin.Context) {
 			return err
 		}
 
-		merchantScoreDecrease := order.Amount.Mul(merchantPayConfig.ScoreRate).Round(0).IntPart()
+		// 按比例扣回分账收款人的分账份额，商户退还剩余部分
+		splitAmount, err := service.ReverseOrderSplits(tx, &order, refundAmount)
+		if err != nil {
+			return err
+		}
+
+		merchantScoreDecrease := refundAmount.Mul(merchantPayConfig.ScoreRate).Round(0).IntPart()
+		merchantRefund := refundAmount.Sub(splitAmount)
 		if err := tx.Model(&model.User{}).
 			Where("id = ?", merchantUser.ID).
 			UpdateColumns(map[string]interface{}{
-				"available_balance": gorm.Expr("available_balance - ?", order.Amount),
-				"total_receive":     gorm.Expr("total_receive - ?", order.Amount),
+				"available_balance": gorm.Expr("available_balance - ?", merchantRefund),
+				"total_receive":     gorm.Expr("total_receive - ?", merchantRefund),
 				"pay_score":         gorm.Expr("pay_score - ?", merchantScoreDecrease),
 			}).Error; err != nil {
 			return err
@@ -316,25 +600,30 @@ func RefundMerchantOrder(c *gin.Context) {
 		if err := tx.Model(&model.User{}).
 			Where("id = ?", payerUser.ID).
 			UpdateColumns(map[string]interface{}{
-				"available_balance": gorm.Expr("available_balance + ?", order.Amount),
-				"total_payment":     gorm.Expr("total_payment - ?", order.Amount),
-				"pay_score":         gorm.Expr("pay_score - ?", order.Amount.Round(0).IntPart()),
+				"available_balance": gorm.Expr("available_balance + ?", refundAmount),
+				"total_payment":     gorm.Expr("total_payment - ?", refundAmount),
+				"pay_score":         gorm.Expr("pay_score - ?", refundAmount.Round(0).IntPart()),
 			}).Error; err != nil {
 			return err
 		}
 
-		if err := tx.Model(&model.Order{}).
-			Where("id = ?", order.ID).
-			UpdateColumn("status", model.OrderStatusRefund).Error; err != nil {
-			return err
+		// 全部退完后订单才进入退款状态，部分退款的订单保持成功状态
+		order.RefundedAmount = order.RefundedAmount.Add(refundAmount)
+		updates := map[string]interface{}{"refunded_amount": order.RefundedAmount}
+		if order.RefundableAmount().IsZero() {
+			order.Status = model.OrderStatusRefund
+			updates["status"] = order.Status
 		}
-
-		return nil
+		return tx.Model(&model.Order{}).
+			Where("id = ?", order.ID).
+			UpdateColumns(updates).Error
 	}); err != nil {
 		c.JSON(http.StatusOK, gin.H{"code": -1, "msg": err.Error()})
 		return
 	}
 
+	service.PublishOrderEvent(c.Request.Context(), &order)
+
 	c.JSON(http.StatusOK, gin.H{
 		"code": 1,
 		"msg":  "退款成功",
@@ -383,8 +672,18 @@ func GetPaymentPageDetails(c *gin.Context) {
 		return
 	}
 
+	items := make([]model.OrderItem, 0)
+	if err := db.DB(c.Request.Context()).
+		Where("order_id = ?", order.ID).
+		Order("line_no ASC").
+		Find(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
 	c.JSON(http.StatusOK, util.OK(GetOrderResponse{
 		Order:   &order,
+		Items:   items,
 		FeeRate: orderCtx.MerchantPayConfig.FeeRate,
 		Merchant: MerchantInfo{
 			AppName:     merchant.AppName,
@@ -398,6 +697,7 @@ func GetPaymentPageDetails(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param request body PayOrderRequest true "支付订单请求"
+// @Param Idempotency-Key header string false "幂等键，重复请求返回首次请求的响应"
 // @Success 200 {object} util.ResponseAny
 // @Router /api/v1/merchant/payment [post]
 func PayMerchantOrder(c *gin.Context) {
@@ -411,14 +711,14 @@ func PayMerchantOrder(c *gin.Context) {
 		return
 	}
 
-	if subtle.ConstantTimeCompare([]byte(orderCtx.CurrentUser.PayKey), []byte(req.PayKey)) != 1 {
+	if req.PayKey != "" && subtle.ConstantTimeCompare([]byte(orderCtx.CurrentUser.PayKey), []byte(req.PayKey)) != 1 {
 		c.JSON(http.StatusBadRequest, util.Err(common.PayKeyIncorrect))
 		return
 	}
 
+	var order model.Order
 	if err := db.DB(c.Request.Context()).Transaction(
 		func(tx *gorm.DB) error {
-			var order model.Order
 			if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
 				Where("id = ? AND status = ?", orderCtx.OrderID, model.OrderStatusPending).
 				First(&order).Error; err != nil {
@@ -433,14 +733,56 @@ func PayMerchantOrder(c *gin.Context) {
 				return errors.New(OrderExpired)
 			}
 
+			var apiKey model.MerchantAPIKey
+			if err := apiKey.GetByClientID(tx, order.ClientID); err != nil {
+				return err
+			}
+
+			// 使用优惠券时核销并改按减免后的金额支付
+			if req.CouponCode != "" {
+				if err := applyOrderCoupon(tx, &order, apiKey.ID, orderCtx.CurrentUser.ID, req.CouponCode); err != nil {
+					return err
+				}
+			}
+
+			// 未提供支付密钥时校验免密支付额度
+			if req.PayKey == "" {
+				allowance, err := service.ConsumeAllowance(tx, orderCtx.CurrentUser.ID, order.ClientID, order.Amount)
+				if err != nil {
+					return err
+				}
+				order.AllowanceID = allowance.ID
+			}
+
 			// 检查每日限额
 			if err := service.CheckDailyLimit(tx, orderCtx.CurrentUser.ID, order.Amount, orderCtx.PayerPayConfig.DailyLimit); err != nil {
 				return err
 			}
 
+			// 检查商户应用的金额范围和每日收款限额
+			if err := service.CheckMerchantLimits(tx, &apiKey, order.Amount); err != nil {
+				return err
+			}
+
+			// 预授权订单仅冻结付款人余额，待商户确认扣款时再结算
+			if order.PreAuth {
+				voidHours, err := model.GetIntByKey(c.Request.Context(), model.ConfigKeyOrderAuthorizationVoidHours)
+				if err != nil {
+					return err
+				}
+				if err := authorizeOrder(tx, &order, orderCtx.CurrentUser.ID, time.Duration(voidHours)*time.Hour); err != nil {
+					return err
+				}
+				deleteOrderExpireKey(c.Request.Context(), order.ID)
+				return EnqueueMerchantNotify(&order)
+			}
+
 			// 计算手续费
 			_, merchantAmount, feePercent := service.CalculateFee(order.Amount, orderCtx.MerchantPayConfig.FeeRate)
 			feeRemark := fmt.Sprintf("[系统]: 收取商家%d%%手续费", feePercent)
+			if order.AllowanceID != 0 {
+				feeRemark = AllowanceRemark + " " + feeRemark
+			}
 
 			// 更新订单状态和备注
 			if order.Remark != "" {
@@ -460,32 +802,22 @@ func PayMerchantOrder(c *gin.Context) {
 				return err
 			}
 
+			// 结算分账，分账收款人按份额承担手续费
+			splitAmount, err := service.SettleOrderSplits(tx, order.ID, orderCtx.MerchantPayConfig.FeeRate)
+			if err != nil {
+				return err
+			}
+
 			// 增加商户余额和积分
 			merchantScoreIncrease := order.Amount.Mul(orderCtx.MerchantPayConfig.ScoreRate).Round(0).IntPart()
-			if err := service.AddMerchantBalance(tx, orderCtx.MerchantUser.ID, merchantAmount, merchantScoreIncrease); err != nil {
+			if err := service.AddMerchantBalance(tx, orderCtx.MerchantUser.ID, merchantAmount.Sub(splitAmount), merchantScoreIncrease); err != nil {
 				return err
 			}
 
-			expireKey := fmt.Sprintf(OrderExpireKeyFormat, order.ID)
-			if err := db.Redis.Del(c.Request.Context(), expireKey).Err(); err != nil {
-				log.Printf("[Payment] 删除订单过期key失败: order_id=%d, error=%v", order.ID, err)
-			}
+			deleteOrderExpireKey(c.Request.Context(), order.ID)
 
 			// 下发商户回调任务
-			notifyPayload, _ := json.Marshal(map[string]interface{}{
-				"order_id":  order.ID,
-				"client_id": order.ClientID,
-			})
-			if _, errTask := schedule.AsynqClient.Enqueue(
-				asynq.NewTask(task.MerchantPaymentNotifyTask, notifyPayload),
-				asynq.Queue(task.QueueWebhook),
-				asynq.MaxRetry(5),
-				asynq.Timeout(30*time.Second),
-			); errTask != nil {
-				return fmt.Errorf("下发商户回调任务失败: %w", errTask)
-			}
-
-			return nil
+			return EnqueueMerchantNotify(&order)
 		},
 	); err != nil {
 		errMsg := err.Error()
@@ -497,20 +829,240 @@ func PayMerchantOrder(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, util.Err(OrderExpired))
 		} else if errMsg == common.DailyLimitExceeded {
 			c.JSON(http.StatusBadRequest, util.Err(common.DailyLimitExceeded))
+		} else if isOrderRequestError(err) || service.IsAllowanceError(err) || service.IsCouponError(err) {
+			c.JSON(http.StatusBadRequest, util.Err(errMsg))
 		} else {
 			c.JSON(http.StatusInternalServerError, util.Err(errMsg))
 		}
 		return
 	}
 
+	service.PublishOrderEvent(c.Request.Context(), &order)
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
+
+// PreviewOrderCouponRequest 收银台试算优惠券请求
+type PreviewOrderCouponRequest struct {
+	OrderNo    string `form:"order_no" binding:"required"`
+	CouponCode string `form:"coupon_code" binding:"required,max=32"`
+}
+
+// PreviewOrderCoupon 收银台试算优惠券减免金额，不核销优惠券
+// @Tags payment
+// @Produce json
+// @Param request query PreviewOrderCouponRequest true "试算参数"
+// @Success 200 {object} service.CouponDiscount
+// @Router /api/v1/merchant/payment/coupon [get]
+func PreviewOrderCoupon(c *gin.Context) {
+	var req PreviewOrderCouponRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	orderCtx, errCtx := ParseOrderNo(c, req.OrderNo)
+	if HandleParseOrderNoError(c, errCtx) {
+		return
+	}
+
+	var order model.Order
+	if err := db.DB(c.Request.Context()).
+		Where("id = ? AND status = ?", orderCtx.OrderID, model.OrderStatusPending).
+		First(&order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(OrderNotFound))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	var apiKey model.MerchantAPIKey
+	if err := apiKey.GetByClientID(db.DB(c.Request.Context()), order.ClientID); err != nil {
+		c.JSON(http.StatusNotFound, util.Err(MerchantInfoNotFound))
+		return
+	}
+
+	discount, err := resolveOrderCoupon(db.DB(c.Request.Context()), &order, apiKey.ID, orderCtx.CurrentUser.ID, req.CouponCode)
+	if err != nil {
+		if service.IsCouponError(err) {
+			c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(discount))
+}
+
+// OrderQRCodeRequest 订单收银台二维码请求
+type OrderQRCodeRequest struct {
+	OrderNo string `form:"order_no" binding:"required"`
+	util.QRCodeRequest
+}
+
+// GetOrderQRCode 获取待支付订单收银台地址的二维码
+// @Tags payment
+// @Produce png
+// @Produce image/svg+xml
+// @Param request query OrderQRCodeRequest true "二维码参数"
+// @Success 200 {file} binary
+// @Router /api/v1/merchant/payment/qrcode [get]
+func GetOrderQRCode(c *gin.Context) {
+	var req OrderQRCodeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	// 与收银台一致校验订单号，且仅为待支付订单生成二维码
+	orderID, _, errDecode := decodeOrderNo(c.Request.Context(), req.OrderNo)
+	if HandleParseOrderNoError(c, errDecode) {
+		return
+	}
+
+	var order model.Order
+	if err := db.DB(c.Request.Context()).
+		Where("id = ? AND status = ?", orderID, model.OrderStatusPending).
+		First(&order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(OrderNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	util.RenderQRCode(c, cashierURL(req.OrderNo), &req.QRCodeRequest)
+}
+
+// CancelOrderRequest 用户取消订单请求
+type CancelOrderRequest struct {
+	OrderNo string `json:"order_no" binding:"required"`
+}
+
+// CancelMerchantOrder 用户在收银台取消待支付订单接口
+// 持有收银台订单号的登录用户视为买家，可取消尚未绑定付款人或付款人为本人的待支付订单（商户本人除外），取消不会记录付款人
+// @Tags payment
+// @Accept json
+// @Produce json
+// @Param request body CancelOrderRequest true "取消订单请求"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/merchant/payment/cancel [post]
+func CancelMerchantOrder(c *gin.Context) {
+	var req CancelOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+	orderCtx, errCtx := ParseOrderNo(c, req.OrderNo)
+	if HandleParseOrderNoError(c, errCtx) {
+		return
+	}
+
+	var order model.Order
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+				Where("id = ? AND status = ? AND payer_user_id IN ?",
+					orderCtx.OrderID, model.OrderStatusPending, []uint64{0, orderCtx.CurrentUser.ID}).
+				First(&order).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New(OrderNotFound)
+				}
+				return err
+			}
+
+			order.Status = model.OrderStatusCancelled
+			if err := tx.Model(&order).Update("status", order.Status).Error; err != nil {
+				return err
+			}
+			log.Printf("[Payment] 用户取消订单: order_id=%d, user_id=%d", order.ID, orderCtx.CurrentUser.ID)
+
+			// 清理收银台缓存和过期监听 key
+			if err := db.Redis.Del(
+				c.Request.Context(),
+				fmt.Sprintf(OrderMerchantIDCacheKeyFormat, req.OrderNo),
+				fmt.Sprintf(OrderExpireKeyFormat, order.ID),
+			).Err(); err != nil {
+				log.Printf("[Payment] 删除订单缓存key失败: order_id=%d, error=%v", order.ID, err)
+			}
+
+			// 通知商户订单已关闭
+			return EnqueueMerchantNotify(&order)
+		},
+	); err != nil {
+		if err.Error() == OrderNotFound {
+			c.JSON(http.StatusNotFound, util.Err(OrderNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	service.PublishOrderEvent(c.Request.Context(), &order)
+
 	c.JSON(http.StatusOK, util.OKNil())
 }
 
+// StreamOrderStatus 收银台订单状态推送接口（SSE）
+// @Tags payment
+// @Produce text/event-stream
+// @Param order_no query string true "订单号"
+// @Success 200 {object} service.OrderEvent
+// @Router /api/v1/merchant/payment/events [get]
+func StreamOrderStatus(c *gin.Context) {
+	var req GetOrderRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	orderCtx, errCtx := ParseOrderNo(c, req.OrderNo)
+	if HandleParseOrderNoError(c, errCtx) {
+		return
+	}
+
+	// 先订阅再查询当前状态，保证两者之间的变更不会丢失
+	events, unsubscribe := listener.SubscribeOrderEvents(orderCtx.OrderID, "")
+	defer unsubscribe()
+
+	var order model.Order
+	if err := db.DB(c.Request.Context()).Where("id = ?", orderCtx.OrderID).First(&order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(OrderNotFound))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	snapshot := service.NewOrderEvent(&order)
+	streamOrderEvents(c, events, &snapshot, true)
+}
+
+// StreamMerchantOrderEvents 商户订单事件推送接口（SSE，Basic Auth），推送该应用下所有订单的状态变更
+// @Tags payment
+// @Produce text/event-stream
+// @Success 200 {object} service.OrderEvent
+// @Router /api/v1/merchant/orders/events [get]
+func StreamMerchantOrderEvents(c *gin.Context) {
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, APIKeyObjKey)
+
+	events, unsubscribe := listener.SubscribeOrderEvents(0, apiKey.ClientID)
+	defer unsubscribe()
+
+	streamOrderEvents(c, events, nil, false)
+}
+
 // Transfer 用户转账接口
 // @Tags payment
 // @Accept json
 // @Produce json
 // @Param request body TransferRequest true "转账请求"
+// @Param Idempotency-Key header string false "幂等键，重复请求返回首次请求的响应"
 // @Success 200 {object} util.ResponseAny
 // @Router /api/v1/payment/transfer [post]
 func Transfer(c *gin.Context) {
@@ -537,7 +1089,7 @@ func Transfer(c *gin.Context) {
 		return
 	}
 
-	if currentUser.ID == req.RecipientID && currentUser.Username == req.RecipientUsername {
+	if currentUser.Username == req.RecipientUsername {
 		c.JSON(http.StatusBadRequest, util.Err(CannotTransferToSelf))
 		return
 	}
@@ -546,7 +1098,7 @@ func Transfer(c *gin.Context) {
 		func(tx *gorm.DB) error {
 			// 验证收款人是否存在且用户名匹配
 			var recipient model.User
-			if err := tx.Where("id = ? AND username = ?", req.RecipientID, req.RecipientUsername).First(&recipient).Error; err != nil {
+			if err := RecipientQuery(tx, req.RecipientID, req.RecipientUsername).First(&recipient).Error; err != nil {
 				if errors.Is(err, gorm.ErrRecordNotFound) {
 					return errors.New(RecipientNotFound)
 				}
@@ -610,3 +1162,139 @@ func Transfer(c *gin.Context) {
 
 	c.JSON(http.StatusOK, util.OKNil())
 }
+
+// CaptureOrderRequest 预授权订单确认扣款请求
+type CaptureOrderRequest struct {
+	// Amount 确认扣款金额，不超过预授权金额，为空时按预授权金额全额扣款
+	Amount decimal.Decimal `json:"amount"`
+}
+
+// OrderAuthorizationResponse 预授权订单处理结果
+type OrderAuthorizationResponse struct {
+	TradeNo        string                         `json:"trade_no" example:"123456"`
+	Status         model.OrderAuthorizationStatus `json:"status"`
+	HoldAmount     decimal.Decimal                `json:"hold_amount"`
+	CapturedAmount decimal.Decimal                `json:"captured_amount"`
+}
+
+// CaptureMerchantOrder 商户确认预授权订单扣款，未扣款部分解冻退回付款人（Basic Auth）
+// @Tags payment
+// @Accept json
+// @Produce json
+// @Param tradeNo path string true "平台订单号"
+// @Param request body CaptureOrderRequest false "request body"
+// @Param Idempotency-Key header string false "幂等键，重复请求返回首次请求的响应"
+// @Success 200 {object} OrderAuthorizationResponse
+// @Router /api/v1/merchant/orders/{tradeNo}/capture [post]
+func CaptureMerchantOrder(c *gin.Context) {
+	var req CaptureOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	if req.Amount.IsNegative() {
+		c.JSON(http.StatusBadRequest, util.Err(common.AmountMustBeGreaterThanZero))
+		return
+	}
+	if req.Amount.Exponent() < -2 {
+		c.JSON(http.StatusBadRequest, util.Err(common.AmountDecimalPlacesExceeded))
+		return
+	}
+
+	tradeNo, err := strconv.ParseUint(c.Param("tradeNo"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, util.Err(AuthorizationNotFound))
+		return
+	}
+
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, APIKeyObjKey)
+
+	var merchantUser model.User
+	if err := db.DB(c.Request.Context()).Where("id = ? AND is_active = ?", apiKey.UserID, true).First(&merchantUser).Error; err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(MerchantInfoNotFound))
+		return
+	}
+
+	var merchantPayConfig model.UserPayConfig
+	if err := merchantPayConfig.GetByPayScore(db.DB(c.Request.Context()), merchantUser.PayScore); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	var order *model.Order
+	var authorization *model.OrderAuthorization
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			var errLock error
+			order, authorization, errLock = lockAuthorizedOrder(tx, tradeNo, apiKey.ClientID)
+			if errLock != nil {
+				return errLock
+			}
+
+			amount := req.Amount
+			if amount.IsZero() {
+				amount = authorization.HoldAmount
+			}
+			if amount.GreaterThan(authorization.HoldAmount) {
+				return errors.New(CaptureAmountExceeded)
+			}
+
+			if err := captureAuthorization(tx, order, authorization, amount, merchantUser.ID, &merchantPayConfig); err != nil {
+				return err
+			}
+
+			// 下发商户回调任务
+			return EnqueueMerchantNotify(order)
+		},
+	); err != nil {
+		respondAuthorizationError(c, err)
+		return
+	}
+
+	service.PublishOrderEvent(c.Request.Context(), order)
+
+	c.JSON(http.StatusOK, util.OK(newOrderAuthorizationResponse(authorization)))
+}
+
+// VoidMerchantOrder 商户撤销预授权订单，冻结金额全部退回付款人（Basic Auth）
+// @Tags payment
+// @Produce json
+// @Param tradeNo path string true "平台订单号"
+// @Success 200 {object} OrderAuthorizationResponse
+// @Router /api/v1/merchant/orders/{tradeNo}/void [post]
+func VoidMerchantOrder(c *gin.Context) {
+	tradeNo, err := strconv.ParseUint(c.Param("tradeNo"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, util.Err(AuthorizationNotFound))
+		return
+	}
+
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, APIKeyObjKey)
+
+	var order *model.Order
+	var authorization *model.OrderAuthorization
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			var errLock error
+			order, authorization, errLock = lockAuthorizedOrder(tx, tradeNo, apiKey.ClientID)
+			if errLock != nil {
+				return errLock
+			}
+
+			if err := voidAuthorization(tx, order, authorization); err != nil {
+				return err
+			}
+
+			// 下发商户回调任务
+			return EnqueueMerchantNotify(order)
+		},
+	); err != nil {
+		respondAuthorizationError(c, err)
+		return
+	}
+
+	service.PublishOrderEvent(c.Request.Context(), order)
+
+	c.JSON(http.StatusOK, util.OK(newOrderAuthorizationResponse(authorization)))
+}
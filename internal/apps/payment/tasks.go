@@ -34,31 +34,73 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hibiken/asynq"
 	"github.com/linux-do/pay/internal/common"
+	"github.com/linux-do/pay/internal/config"
 	"github.com/linux-do/pay/internal/db"
 	"github.com/linux-do/pay/internal/logger"
 	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/service"
+	"github.com/linux-do/pay/internal/task"
+	"github.com/linux-do/pay/internal/task/schedule"
 	"github.com/linux-do/pay/internal/util"
 	"gorm.io/gorm"
 )
 
+// notifyTradeStatus 订单状态与回调 trade_status 的对应关系
+var notifyTradeStatus = map[model.OrderStatus]string{
+	model.OrderStatusSuccess:    "TRADE_SUCCESS",
+	model.OrderStatusCancelled:  "TRADE_CLOSED",
+	model.OrderStatusAuthorized: "TRADE_AUTHORIZED",
+}
+
+// EnqueueMerchantNotify 下发商户订单回调任务，回调内容以订单当前状态为准
+func EnqueueMerchantNotify(order *model.Order) error {
+	notifyPayload, _ := json.Marshal(map[string]interface{}{
+		"order_id":  order.ID,
+		"client_id": order.ClientID,
+		"status":    order.Status,
+	})
+	if _, errTask := schedule.AsynqClient.Enqueue(
+		asynq.NewTask(task.MerchantPaymentNotifyTask, notifyPayload),
+		asynq.Queue(task.QueueWebhook),
+		asynq.MaxRetry(5),
+		asynq.Timeout(30*time.Second),
+	); errTask != nil {
+		return fmt.Errorf("下发商户回调任务失败: %w", errTask)
+	}
+	return nil
+}
+
 // HandleMerchantPaymentNotify 处理商户支付回调任务
 func HandleMerchantPaymentNotify(ctx context.Context, t *asynq.Task) error {
 	// 解析任务参数
 	var payload struct {
-		OrderID  uint64 `json:"order_id"`
-		ClientID string `json:"client_id"`
+		OrderID  uint64            `json:"order_id"`
+		ClientID string            `json:"client_id"`
+		Status   model.OrderStatus `json:"status"`
 	}
 	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
 		logger.ErrorF(ctx, "解析商户回调任务参数失败: %v", err)
 		return fmt.Errorf("解析任务参数失败: %w", err)
 	}
 
+	// 兼容未携带状态的历史任务
+	if payload.Status == "" {
+		payload.Status = model.OrderStatusSuccess
+	}
+
+	tradeStatus, ok := notifyTradeStatus[payload.Status]
+	if !ok {
+		logger.ErrorF(ctx, "订单[ID:%d]状态[%s]不支持回调，跳过", payload.OrderID, payload.Status)
+		return nil
+	}
+
 	// 查询订单信息
 	var order model.Order
-	if err := db.DB(ctx).Where("id = ? AND status = ?", payload.OrderID, model.OrderStatusSuccess).First(&order).Error; err != nil {
+	if err := db.DB(ctx).Where("id = ? AND status = ?", payload.OrderID, payload.Status).First(&order).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			logger.ErrorF(ctx, "订单[ID:%d]不存在，跳过回调", payload.OrderID)
 			return nil
@@ -81,8 +123,20 @@ func HandleMerchantPaymentNotify(ctx context.Context, t *asynq.Task) error {
 		"type":         common.PayTypeEPay,
 		"name":         order.OrderName,
 		"money":        order.Amount.Truncate(2).StringFixed(2),
-		"trade_status": "TRADE_SUCCESS",
+		"trade_status": tradeStatus,
 		"sign_type":    "MD5",
+		"param":        order.Metadata[EPayParamMetadataKey],
+	}
+	if len(order.Metadata) > 0 {
+		metadataJSON, _ := json.Marshal(order.Metadata)
+		callbackParams["metadata"] = string(metadataJSON)
+	}
+	if order.MerchantPaymentLinkID != 0 {
+		callbackParams["link_id"] = strconv.FormatUint(order.MerchantPaymentLinkID, 10)
+	}
+	if order.CouponID != 0 {
+		callbackParams["original_money"] = order.OriginalAmount.Truncate(2).StringFixed(2)
+		callbackParams["discount_money"] = order.DiscountAmount.Truncate(2).StringFixed(2)
 	}
 
 	callbackParams["sign"] = GenerateSignature(callbackParams, apiKey.ClientSecret)
@@ -106,6 +160,73 @@ func HandleMerchantPaymentNotify(ctx context.Context, t *asynq.Task) error {
 	return nil
 }
 
+// EnqueueMerchantEvent 下发商户事件回调任务，params 为事件参数，回调时附加 pid、event 和签名
+func EnqueueMerchantEvent(clientID string, event string, params map[string]string) error {
+	notifyPayload, _ := json.Marshal(map[string]interface{}{
+		"client_id": clientID,
+		"event":     event,
+		"params":    params,
+	})
+	if _, errTask := schedule.AsynqClient.Enqueue(
+		asynq.NewTask(task.MerchantEventNotifyTask, notifyPayload),
+		asynq.Queue(task.QueueWebhook),
+		asynq.MaxRetry(5),
+		asynq.Timeout(30*time.Second),
+	); errTask != nil {
+		return fmt.Errorf("下发商户事件回调任务失败: %w", errTask)
+	}
+	return nil
+}
+
+// HandleMerchantEventNotify 处理商户事件回调任务
+func HandleMerchantEventNotify(ctx context.Context, t *asynq.Task) error {
+	var payload struct {
+		ClientID string            `json:"client_id"`
+		Event    string            `json:"event"`
+		Params   map[string]string `json:"params"`
+	}
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		logger.ErrorF(ctx, "解析商户事件回调任务参数失败: %v", err)
+		return fmt.Errorf("解析任务参数失败: %w", err)
+	}
+
+	var apiKey model.MerchantAPIKey
+	if err := apiKey.GetByClientID(db.DB(ctx), payload.ClientID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.ErrorF(ctx, "商户[ClientID:%s]不存在，跳过事件回调", payload.ClientID)
+			return nil
+		}
+		return fmt.Errorf("查询商户信息失败: %w", err)
+	}
+
+	callbackParams := make(map[string]string, len(payload.Params)+4)
+	for k, v := range payload.Params {
+		callbackParams[k] = v
+	}
+	callbackParams["pid"] = payload.ClientID
+	callbackParams["event"] = payload.Event
+	callbackParams["sign_type"] = "MD5"
+	callbackParams["sign"] = GenerateSignature(callbackParams, apiKey.ClientSecret)
+
+	if err := sendCallbackRequest(ctx, apiKey.NotifyURL, callbackParams); err != nil {
+		retried, _ := asynq.GetRetryCount(ctx)
+		maxRetry := 5
+
+		logger.ErrorF(ctx, "商户事件回调失败: 事件[%s] ClientID[%s] 重试次数[%d/%d] 错误: %v",
+			payload.Event, payload.ClientID, retried+1, maxRetry, err)
+
+		if retried >= maxRetry-1 {
+			logger.ErrorF(ctx, "商户事件回调达到最大重试次数，回调最终失败: 事件[%s] ClientID[%s]", payload.Event, payload.ClientID)
+			return nil
+		}
+
+		return fmt.Errorf("商户事件回调失败: %w", err)
+	}
+
+	logger.InfoF(ctx, "商户事件回调成功: 事件[%s] ClientID[%s]", payload.Event, payload.ClientID)
+	return nil
+}
+
 // sendCallbackRequest 发送HTTP回调请求
 func sendCallbackRequest(ctx context.Context, callbackURL string, params map[string]string) error {
 	vals := url.Values{}
@@ -147,3 +268,99 @@ func sendCallbackRequest(ctx context.Context, callbackURL string, params map[str
 	logger.InfoF(ctx, "商户回调请求成功: URL[%s] 响应[%s]", callbackURL, string(respBody))
 	return nil
 }
+
+// HandleAuthorizationAutoVoidDue 查询所有超时未确认扣款的预授权订单并逐个下发撤销任务
+func HandleAuthorizationAutoVoidDue(ctx context.Context, t *asynq.Task) error {
+	pageSize := 200
+	lastID := uint64(0)
+	currentDelay := 0 * time.Second
+	now := time.Now()
+
+	for {
+		var authorizations []model.OrderAuthorization
+		if err := db.DB(ctx).
+			Where("id > ? AND status = ? AND void_at <= ?", lastID, model.OrderAuthorizationStatusAuthorized, now).
+			Order("id ASC").
+			Limit(pageSize).
+			Find(&authorizations).Error; err != nil {
+			logger.ErrorF(ctx, "查询超时预授权订单失败: %v", err)
+			return err
+		}
+
+		// 没有更多预授权订单，退出循环
+		if len(authorizations) == 0 {
+			break
+		}
+
+		for _, authorization := range authorizations {
+			currentDelay += time.Duration(config.Config.Schedule.AuthorizationAutoVoidDispatchIntervalSeconds) * time.Second
+
+			payload, _ := json.Marshal(map[string]interface{}{
+				"order_id": authorization.OrderID,
+			})
+
+			if _, errTask := schedule.AsynqClient.Enqueue(
+				asynq.NewTask(task.AuthorizationAutoVoidSingleTask, payload),
+				asynq.ProcessIn(currentDelay),
+				asynq.MaxRetry(3),
+			); errTask != nil {
+				logger.ErrorF(ctx, "下发预授权订单[ID:%d]自动撤销任务失败: %v", authorization.OrderID, errTask)
+				return errTask
+			} else {
+				logger.InfoF(ctx, "下发预授权订单[ID:%d]自动撤销任务成功", authorization.OrderID)
+			}
+		}
+
+		lastID = authorizations[len(authorizations)-1].ID
+	}
+	return nil
+}
+
+// HandleAuthorizationAutoVoidSingle 撤销超时未确认扣款的预授权订单，冻结金额退回付款人
+func HandleAuthorizationAutoVoidSingle(ctx context.Context, t *asynq.Task) error {
+	var payload struct {
+		OrderID uint64 `json:"order_id"`
+	}
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("解析任务参数失败: %w", err)
+	}
+
+	var order *model.Order
+	if err := db.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		var authorization *model.OrderAuthorization
+		var errLock error
+		order, authorization, errLock = lockAuthorizedOrder(tx, payload.OrderID, "")
+		if errLock != nil {
+			return errLock
+		}
+
+		if authorization.VoidAt.After(time.Now()) {
+			order = nil
+			return nil
+		}
+
+		if err := voidAuthorization(tx, order, authorization); err != nil {
+			return err
+		}
+
+		// 下发商户回调任务
+		return EnqueueMerchantNotify(order)
+	}); err != nil {
+		if err.Error() == AuthorizationNotFound {
+			logger.InfoF(ctx, "预授权订单[ID:%d]已被处理，跳过", payload.OrderID)
+			return nil
+		}
+		logger.ErrorF(ctx, "预授权订单[ID:%d]自动撤销失败: %v", payload.OrderID, err)
+		return err
+	}
+
+	if order == nil {
+		logger.InfoF(ctx, "预授权订单[ID:%d]未到自动撤销时间，跳过", payload.OrderID)
+		return nil
+	}
+
+	service.PublishOrderEvent(ctx, order)
+
+	logger.InfoF(ctx, "预授权订单[ID:%d]自动撤销完成", payload.OrderID)
+	return nil
+}
@@ -25,25 +25,33 @@
 package payment
 
 import (
+	"context"
 	"crypto/md5"
 	"crypto/subtle"
 	"errors"
 	"fmt"
+	"io"
+	"log"
 	"net/http"
+	"net/url"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/linux-do/pay/internal/apps/oauth"
 	"github.com/linux-do/pay/internal/common"
+	"github.com/linux-do/pay/internal/config"
 	"github.com/linux-do/pay/internal/db"
 	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/service"
 	"github.com/linux-do/pay/internal/util"
 	"github.com/redis/go-redis/v9"
 	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // HandleParseOrderNoError 处理 ParseOrderNo 返回的错误，返回对应的 HTTP 响应
@@ -82,25 +90,8 @@ type OrderContext struct {
 
 // ParseOrderNo 解析订单号，获取订单上下文信息
 func ParseOrderNo(c *gin.Context, orderNo string) (*OrderContext, error) {
-	merchantIDStr, errGet := db.Redis.Get(c.Request.Context(), fmt.Sprintf(OrderMerchantIDCacheKeyFormat, orderNo)).Result()
-	if errGet != nil {
-		if errors.Is(errGet, redis.Nil) {
-			return nil, errors.New(OrderNotFound)
-		}
-		return nil, errGet
-	}
-
-	merchantID, errParse := strconv.ParseUint(merchantIDStr, 10, 64)
-	if errParse != nil {
-		return nil, errors.New(OrderNoFormatError)
-	}
-
-	// 获取商户用户信息
-	var merchantUser model.User
-	if err := db.DB(c.Request.Context()).Where("id = ? AND is_active = ?", merchantID, true).First(&merchantUser).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New(MerchantInfoNotFound)
-		}
+	orderID, merchantUser, err := decodeOrderNo(c.Request.Context(), orderNo)
+	if err != nil {
 		return nil, err
 	}
 
@@ -111,19 +102,9 @@ func ParseOrderNo(c *gin.Context, orderNo string) (*OrderContext, error) {
 		return nil, errors.New(CannotPayOwnOrder)
 	}
 
-	orderNoStr, errDecrypt := util.Decrypt(merchantUser.SignKey, orderNo)
-	if errDecrypt != nil {
-		return nil, errors.New(OrderNoFormatError)
-	}
-
-	orderID, errParse := strconv.ParseUint(orderNoStr, 10, 64)
-	if errParse != nil {
-		return nil, errors.New(OrderNoFormatError)
-	}
-
 	ctx := &OrderContext{
 		OrderID:      orderID,
-		MerchantUser: &merchantUser,
+		MerchantUser: merchantUser,
 		CurrentUser:  currentUser,
 	}
 
@@ -150,6 +131,462 @@ func ParseOrderNo(c *gin.Context, orderNo string) (*OrderContext, error) {
 	return ctx, nil
 }
 
+// decodeOrderNo 通过收银台缓存找到商户并解密订单号，返回订单ID和商户用户
+func decodeOrderNo(ctx context.Context, orderNo string) (uint64, *model.User, error) {
+	merchantIDStr, errGet := db.Redis.Get(ctx, fmt.Sprintf(OrderMerchantIDCacheKeyFormat, orderNo)).Result()
+	if errGet != nil {
+		if errors.Is(errGet, redis.Nil) {
+			return 0, nil, errors.New(OrderNotFound)
+		}
+		return 0, nil, errGet
+	}
+
+	merchantID, errParse := strconv.ParseUint(merchantIDStr, 10, 64)
+	if errParse != nil {
+		return 0, nil, errors.New(OrderNoFormatError)
+	}
+
+	// 获取商户用户信息
+	var merchantUser model.User
+	if err := db.DB(ctx).Where("id = ? AND is_active = ?", merchantID, true).First(&merchantUser).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil, errors.New(MerchantInfoNotFound)
+		}
+		return 0, nil, err
+	}
+
+	orderNoStr, errDecrypt := util.Decrypt(merchantUser.SignKey, orderNo)
+	if errDecrypt != nil {
+		return 0, nil, errors.New(OrderNoFormatError)
+	}
+
+	orderID, errParse := strconv.ParseUint(orderNoStr, 10, 64)
+	if errParse != nil {
+		return 0, nil, errors.New(OrderNoFormatError)
+	}
+
+	return orderID, &merchantUser, nil
+}
+
+// createMerchantOrder 创建待支付的商户订单，并写入收银台所需的 Redis 缓存，返回订单与收银台地址
+func createMerchantOrder(ctx context.Context, apiKey *model.MerchantAPIKey, req *CreateOrderRequest) (*model.Order, string, error) {
+	// 获取商户用户信息
+	var merchantUser model.User
+	if err := db.DB(ctx).Where("id = ? AND is_active = ?", apiKey.UserID, true).First(&merchantUser).Error; err != nil {
+		return nil, "", errors.New(MerchantInfoNotFound)
+	}
+
+	expireMinutes, errExpire := resolveOrderExpireMinutes(ctx, req.ExpireMinutes)
+	if errExpire != nil {
+		return nil, "", errExpire
+	}
+
+	// 下单时预检商户应用的金额范围和每日收款限额，支付事务内加锁复核
+	if err := service.PrecheckMerchantLimits(db.DB(ctx), apiKey, req.Amount); err != nil {
+		return nil, "", err
+	}
+
+	if req.PreAuth && len(req.Splits) > 0 {
+		return nil, "", errors.New(PreAuthSplitUnsupported)
+	}
+
+	splits, errSplit := resolveOrderSplits(db.DB(ctx), merchantUser.ID, req.Amount, req.Splits)
+	if errSplit != nil {
+		return nil, "", errSplit
+	}
+
+	var payURL string
+	order := model.Order{
+		OrderName:       req.OrderName,
+		ClientID:        apiKey.ClientID,
+		MerchantOrderNo: req.MerchantOrderNo,
+		PayeeUserID:     merchantUser.ID,
+		Amount:          req.Amount,
+		Status:          model.OrderStatusPending,
+		Type:            model.OrderTypePayment,
+		Remark:          req.Remark,
+		PaymentType:     req.PaymentType,
+		Metadata:        req.Metadata,
+		PreAuth:         req.PreAuth,
+		ExpiresAt:       time.Now().Add(time.Duration(expireMinutes) * time.Minute),
+	}
+
+	if err := db.DB(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			// 创建订单
+			if err := tx.Create(&order).Error; err != nil {
+				return err
+			}
+
+			if len(req.Items) > 0 {
+				items := make([]model.OrderItem, 0, len(req.Items))
+				for i, item := range req.Items {
+					items = append(items, model.OrderItem{
+						OrderID:   order.ID,
+						LineNo:    i + 1,
+						Name:      item.Name,
+						SKU:       item.SKU,
+						UnitPrice: item.UnitPrice,
+						Quantity:  item.Quantity,
+						Amount:    item.UnitPrice.Mul(decimal.NewFromInt(int64(item.Quantity))),
+					})
+				}
+				if err := tx.Create(&items).Error; err != nil {
+					return err
+				}
+			}
+
+			if len(splits) > 0 {
+				for i := range splits {
+					splits[i].OrderID = order.ID
+				}
+				if err := tx.Create(&splits).Error; err != nil {
+					return err
+				}
+			}
+
+			encryptString, err := util.Encrypt(merchantUser.SignKey, strconv.FormatUint(order.ID, 10))
+			if err != nil {
+				return err
+			}
+
+			merchantIDStr := strconv.FormatUint(merchantUser.ID, 10)
+			if errSet := db.Redis.Set(ctx, fmt.Sprintf(OrderMerchantIDCacheKeyFormat, encryptString), merchantIDStr, time.Duration(expireMinutes)*time.Minute).Err(); errSet != nil {
+				return fmt.Errorf("failed to set redis key: %w", errSet)
+			}
+
+			expireKey := fmt.Sprintf(OrderExpireKeyFormat, order.ID)
+			if errSet := db.Redis.Set(ctx, expireKey, order.ID, time.Duration(expireMinutes)*time.Minute).Err(); errSet != nil {
+				return fmt.Errorf("failed to set order expire key: %w", errSet)
+			}
+
+			payURL = cashierURL(encryptString)
+			return nil
+		},
+	); err != nil {
+		return nil, "", err
+	}
+
+	return &order, payURL, nil
+}
+
+// applyOrderItems 校验商品明细并据此补全订单金额与名称，商户同时提供金额时须与明细合计一致
+func applyOrderItems(req *CreateOrderRequest) error {
+	if len(req.Items) == 0 {
+		return nil
+	}
+
+	total := decimal.Zero
+	for _, item := range req.Items {
+		if item.UnitPrice.LessThanOrEqual(decimal.Zero) || item.UnitPrice.Exponent() < -2 {
+			return errors.New(ItemUnitPriceInvalid)
+		}
+		total = total.Add(item.UnitPrice.Mul(decimal.NewFromInt(int64(item.Quantity))))
+	}
+
+	if !req.Amount.IsZero() && !req.Amount.Equal(total) {
+		return errors.New(ItemsAmountMismatch)
+	}
+	req.Amount = total
+
+	if req.OrderName == "" {
+		req.OrderName = itemsOrderName(req.Items)
+	}
+	return nil
+}
+
+// itemsOrderName 由商品明细生成订单名称，多件商品时追加件数并截断到订单名称长度上限
+func itemsOrderName(items []OrderItemRequest) string {
+	if len(items) == 1 {
+		return items[0].Name
+	}
+
+	suffix := []rune(fmt.Sprintf(" 等%d件商品", len(items)))
+	name := []rune(items[0].Name)
+	if len(name)+len(suffix) > OrderNameMaxLength {
+		name = name[:OrderNameMaxLength-len(suffix)]
+	}
+	return string(name) + string(suffix)
+}
+
+// checkOrderCouponSupported 带分账规则的订单金额已按原价分配，不支持使用优惠券
+func checkOrderCouponSupported(tx *gorm.DB, orderID uint64) error {
+	var splitCount int64
+	if err := tx.Model(&model.OrderSplit{}).Where("order_id = ?", orderID).Count(&splitCount).Error; err != nil {
+		return err
+	}
+	if splitCount > 0 {
+		return errors.New(common.CouponNotApplicable)
+	}
+	return nil
+}
+
+// resolveOrderCoupon 试算优惠券对收银台订单的减免
+func resolveOrderCoupon(tx *gorm.DB, order *model.Order, apiKeyID uint64, userID uint64, code string) (*service.CouponDiscount, error) {
+	if err := checkOrderCouponSupported(tx, order.ID); err != nil {
+		return nil, err
+	}
+	return service.ResolveCoupon(tx, apiKeyID, 0, userID, code, order.Amount, false)
+}
+
+// applyOrderCoupon 在支付事务内核销优惠券，订单金额改为减免后的应付金额并写入核销记录
+func applyOrderCoupon(tx *gorm.DB, order *model.Order, apiKeyID uint64, userID uint64, code string) error {
+	if err := checkOrderCouponSupported(tx, order.ID); err != nil {
+		return err
+	}
+
+	discount, err := service.ApplyCoupon(tx, apiKeyID, 0, userID, code, order.Amount)
+	if err != nil {
+		return err
+	}
+	discount.ApplyToOrder(order)
+
+	return service.RecordCouponRedemption(tx, discount, order.ID, userID)
+}
+
+// resolveOrderSplits 校验分账规则并计算每个收款人的分账份额，按比例分账时向下取整到分
+func resolveOrderSplits(tx *gorm.DB, merchantUserID uint64, amount decimal.Decimal, rules []OrderSplitRule) ([]model.OrderSplit, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	hundred := decimal.NewFromInt(100)
+	recipientIDs := make([]uint64, 0, len(rules))
+	seen := make(map[uint64]struct{}, len(rules))
+	splits := make([]model.OrderSplit, 0, len(rules))
+	total := decimal.Zero
+	for _, rule := range rules {
+		hasAmount := !rule.Amount.IsZero()
+		hasPercentage := !rule.Percentage.IsZero()
+		if hasAmount == hasPercentage {
+			return nil, errors.New(SplitRuleInvalid)
+		}
+
+		share := rule.Amount
+		if hasPercentage {
+			if rule.Percentage.LessThanOrEqual(decimal.Zero) || rule.Percentage.GreaterThan(hundred) || rule.Percentage.Exponent() < -2 {
+				return nil, errors.New(SplitRuleInvalid)
+			}
+			share = amount.Mul(rule.Percentage).Div(hundred).RoundFloor(2)
+		} else if rule.Amount.LessThan(decimal.Zero) || rule.Amount.Exponent() < -2 {
+			return nil, errors.New(SplitRuleInvalid)
+		}
+		if share.LessThanOrEqual(decimal.Zero) {
+			return nil, errors.New(SplitRuleInvalid)
+		}
+
+		if _, ok := seen[rule.RecipientUserID]; ok || rule.RecipientUserID == merchantUserID {
+			return nil, errors.New(SplitRecipientInvalid)
+		}
+		seen[rule.RecipientUserID] = struct{}{}
+		recipientIDs = append(recipientIDs, rule.RecipientUserID)
+
+		total = total.Add(share)
+		splits = append(splits, model.OrderSplit{
+			RecipientUserID: rule.RecipientUserID,
+			Amount:          share,
+			Status:          model.OrderSplitStatusPending,
+		})
+	}
+
+	if total.GreaterThan(amount) {
+		return nil, errors.New(SplitAmountExceeded)
+	}
+
+	var activeCount int64
+	if err := tx.Model(&model.User{}).
+		Where("id IN ? AND is_active = ?", recipientIDs, true).
+		Count(&activeCount).Error; err != nil {
+		return nil, err
+	}
+	if activeCount != int64(len(recipientIDs)) {
+		return nil, errors.New(SplitRecipientInvalid)
+	}
+
+	return splits, nil
+}
+
+// cashierURL 根据加密订单号生成收银台地址
+func cashierURL(orderNo string) string {
+	return fmt.Sprintf("%s?order_no=%s", config.Config.App.FrontendPayURL, url.QueryEscape(orderNo))
+}
+
+// resolveOrderExpireMinutes 计算订单过期时间（分钟）
+// 商户未指定时使用系统默认值，指定时必须在系统允许的范围内
+func resolveOrderExpireMinutes(ctx context.Context, requested int) (int, error) {
+	if requested <= 0 {
+		return model.GetIntByKey(ctx, model.ConfigKeyMerchantOrderExpireMinutes)
+	}
+
+	minMinutes, errMin := model.GetIntByKey(ctx, model.ConfigKeyMerchantOrderMinExpireMinutes)
+	if errMin != nil {
+		return 0, errMin
+	}
+	maxMinutes, errMax := model.GetIntByKey(ctx, model.ConfigKeyMerchantOrderMaxExpireMinutes)
+	if errMax != nil {
+		return 0, errMax
+	}
+
+	if requested < minMinutes || requested > maxMinutes {
+		return 0, errors.New(ExpireMinutesOutOfRange)
+	}
+	return requested, nil
+}
+
+// parseTimeoutExpress 解析易支付 timeout_express 参数，支持 m（分钟）、h（小时）、d（天）单位，如 90m、2h、1d
+func parseTimeoutExpress(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	unit := value[len(value)-1]
+	number, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || number <= 0 {
+		return 0, errors.New(TimeoutExpressInvalid)
+	}
+
+	switch unit {
+	case 'm':
+		return number, nil
+	case 'h':
+		return number * 60, nil
+	case 'd':
+		return number * 24 * 60, nil
+	default:
+		return 0, errors.New(TimeoutExpressInvalid)
+	}
+}
+
+// isOrderRequestError 判断订单错误是否由请求参数或商户限额引起
+func isOrderRequestError(err error) bool {
+	switch err.Error() {
+	case ExpireMinutesOutOfRange, common.OrderAmountBelowMinimum, common.OrderAmountAboveMaximum, common.MerchantDailyReceiveLimit,
+		SplitRuleInvalid, SplitRecipientInvalid, SplitAmountExceeded, PreAuthSplitUnsupported,
+		ItemUnitPriceInvalid, ItemsAmountMismatch, MerchantOrderNoConflict:
+		return true
+	}
+	return false
+}
+
+// findChargeOrder 锁定商户订单号并查询已存在的免密扣款订单，未找到时返回 nil
+// 相同付款人和金额的重复请求返回原订单，商户订单号被其他订单占用时返回错误
+func findChargeOrder(tx *gorm.DB, clientID string, req *ChargeUserRequest) (*model.Order, error) {
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(?, hashtext(?))",
+		ChargeOrderNoLockClass, clientID+":"+req.MerchantOrderNo).Error; err != nil {
+		return nil, err
+	}
+
+	var order model.Order
+	if err := tx.Where("client_id = ? AND merchant_order_no = ? AND type = ?",
+		clientID, req.MerchantOrderNo, model.OrderTypePayment).
+		First(&order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if order.PayerUserID != req.UserID || !order.Amount.Equal(req.Amount) || order.AllowanceID == 0 {
+		return nil, errors.New(MerchantOrderNoConflict)
+	}
+	return &order, nil
+}
+
+// parseRefundItems 解析按商品行退款参数，格式为 商品行ID:数量，多个以逗号分隔；未传时返回 nil 表示整单退款
+func parseRefundItems(value string) (map[uint64]int, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	items := make(map[uint64]int)
+
+	for _, part := range strings.Split(value, ",") {
+		idStr, quantityStr, ok := strings.Cut(strings.TrimSpace(part), ":")
+		if !ok {
+			return nil, errors.New(RefundItemsInvalid)
+		}
+		itemID, errID := strconv.ParseUint(idStr, 10, 64)
+		quantity, errQuantity := strconv.Atoi(quantityStr)
+		if errID != nil || errQuantity != nil || itemID == 0 || quantity <= 0 {
+			return nil, errors.New(RefundItemsInvalid)
+		}
+		if _, exists := items[itemID]; exists {
+			return nil, errors.New(RefundItemsInvalid)
+		}
+		items[itemID] = quantity
+	}
+	return items, nil
+}
+
+// refundOrderItems 按商品行累计退款数量和金额，返回本次退款金额；items 为 nil 时退还全部商品行的剩余数量
+// 每行可退金额为按商品行金额比例分摊的实付金额，优惠券减免和部分确认扣款均随之分摊到各行；
+// 某行退完时退还该行剩余实付金额，全部商品行退完时退还订单剩余全部金额，避免分摊舍入残差
+func refundOrderItems(tx *gorm.DB, order *model.Order, items map[uint64]int) (decimal.Decimal, error) {
+	var orderItems []model.OrderItem
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("order_id = ?", order.ID).
+		Order("line_no ASC").
+		Find(&orderItems).Error; err != nil {
+		return decimal.Zero, err
+	}
+
+	itemsTotal := decimal.Zero
+	for _, item := range orderItems {
+		itemsTotal = itemsTotal.Add(item.Amount)
+	}
+	if items != nil && itemsTotal.IsZero() {
+		return decimal.Zero, errors.New(RefundItemInvalid)
+	}
+
+	total := decimal.Zero
+	matched := 0
+	allRefunded := true
+	for i := range orderItems {
+		item := &orderItems[i]
+		remainingQuantity := item.Quantity - item.RefundedQuantity
+
+		quantity, ok := items[item.ID]
+		if items == nil {
+			quantity, ok = remainingQuantity, remainingQuantity > 0
+		}
+		if ok {
+			matched++
+			if quantity > remainingQuantity {
+				return decimal.Zero, errors.New(RefundItemInvalid)
+			}
+
+			linePaid := item.Amount.Mul(order.Amount).Div(itemsTotal).Round(2)
+			amount := linePaid.Mul(decimal.NewFromInt(int64(quantity))).Div(decimal.NewFromInt(int64(item.Quantity))).Round(2)
+			if quantity == remainingQuantity {
+				amount = decimal.Max(linePaid.Sub(item.RefundedAmount), decimal.Zero)
+			}
+
+			if err := tx.Model(item).UpdateColumns(map[string]interface{}{
+				"refunded_quantity": gorm.Expr("refunded_quantity + ?", quantity),
+				"refunded_amount":   gorm.Expr("refunded_amount + ?", amount),
+			}).Error; err != nil {
+				return decimal.Zero, err
+			}
+			item.RefundedQuantity += quantity
+			total = total.Add(amount)
+		}
+		if item.RefundedQuantity < item.Quantity {
+			allRefunded = false
+		}
+	}
+	if items != nil && matched != len(items) {
+		return decimal.Zero, errors.New(RefundItemInvalid)
+	}
+
+	if allRefunded {
+		total = order.RefundableAmount()
+	}
+	if total.GreaterThan(order.RefundableAmount()) {
+		return decimal.Zero, errors.New(RefundAmountMismatch)
+	}
+	return total, nil
+}
+
 // GenerateSignature 生成MD5签名
 func GenerateSignature(params map[string]string, secret string) string {
 	// 按key排序
@@ -185,6 +622,12 @@ func GenerateSignature(params map[string]string, secret string) string {
 	return fmt.Sprintf("%x", hash)
 }
 
+// verifySign 生成期望的签名并以常量时间比较（防止时序攻击）
+func verifySign(params map[string]string, secret string, sign string) bool {
+	expectedSign := GenerateSignature(params, secret)
+	return subtle.ConstantTimeCompare([]byte(strings.ToLower(expectedSign)), []byte(strings.ToLower(sign))) == 1
+}
+
 // VerifySignature 验证MD5签名
 func VerifySignature(c *gin.Context, apiKey *model.MerchantAPIKey) (*CreateOrderRequest, error) {
 	var req EPayRequest
@@ -208,23 +651,290 @@ func VerifySignature(c *gin.Context, apiKey *model.MerchantAPIKey) (*CreateOrder
 
 	// 构建签名参数
 	params := map[string]string{
-		"pid":          req.ClientID,
-		"type":         req.PayType,
-		"out_trade_no": req.MerchantOrderNo,
-		"notify_url":   req.NotifyURL,
-		"return_url":   req.ReturnURL,
-		"name":         req.OrderName,
-		"money":        req.Amount.Truncate(2).StringFixed(2),
-		"device":       req.Device,
+		"pid":             req.ClientID,
+		"type":            req.PayType,
+		"out_trade_no":    req.MerchantOrderNo,
+		"notify_url":      req.NotifyURL,
+		"return_url":      req.ReturnURL,
+		"name":            req.OrderName,
+		"money":           req.Amount.Truncate(2).StringFixed(2),
+		"device":          req.Device,
+		"param":           req.Param,
+		"timestamp":       req.Timestamp,
+		"nonce":           req.Nonce,
+		"timeout_express": req.TimeoutExpress,
+	}
+
+	if !verifySign(params, apiKey.ClientSecret, req.Sign) {
+		return nil, errors.New(SignatureInvalid)
+	}
+
+	expireMinutes, errParse := parseTimeoutExpress(req.TimeoutExpress)
+	if errParse != nil {
+		return nil, errParse
+	}
+
+	// 签名通过后再校验防重放参数，避免无效请求占用 nonce
+	if err := VerifyNonce(c.Request.Context(), apiKey, req.Timestamp, req.Nonce); err != nil {
+		return nil, err
+	}
+
+	return req.ToCreateOrderRequest(expireMinutes), nil
+}
+
+// VerifyNonce 校验请求的 timestamp 与 nonce，防止商户请求被重放
+// 未开启强制校验的商户可以不传这两个参数，但传了任意一个就必须通过校验
+func VerifyNonce(ctx context.Context, apiKey *model.MerchantAPIKey, timestamp, nonce string) error {
+	if timestamp == "" && nonce == "" {
+		if apiKey.RequireNonce {
+			return errors.New(NonceParamsRequired)
+		}
+		return nil
+	}
+	if timestamp == "" || nonce == "" {
+		return errors.New(NonceParamsRequired)
+	}
+
+	ts, errParse := strconv.ParseInt(timestamp, 10, 64)
+	if errParse != nil {
+		return errors.New(TimestampInvalid)
+	}
+
+	skewSeconds, errGet := model.GetIntByKey(ctx, model.ConfigKeyMerchantSignTimestampSkewSeconds)
+	if errGet != nil {
+		return errGet
 	}
 
-	// 生成期望的签名
-	expectedSign := GenerateSignature(params, apiKey.ClientSecret)
+	skew := time.Duration(skewSeconds) * time.Second
+	diff := time.Since(time.Unix(ts, 0))
+	if diff > skew || diff < -skew {
+		return errors.New(TimestampExpired)
+	}
+
+	// nonce 保留两倍偏差窗口，覆盖时间戳允许的全部范围
+	ok, errSet := db.Redis.SetNX(ctx, fmt.Sprintf(SignNonceCacheKeyFormat, apiKey.ClientID, nonce), ts, 2*skew).Result()
+	if errSet != nil {
+		return errSet
+	}
+	if !ok {
+		return errors.New(NonceReused)
+	}
+
+	return nil
+}
+
+// streamOrderEvents 以 SSE 推送订单事件，直到客户端断开；stopOnFinal 为 true 时收到终态事件后结束
+func streamOrderEvents(c *gin.Context, events <-chan service.OrderEvent, snapshot *service.OrderEvent, stopOnFinal bool) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	// 先推送订单当前状态，避免订阅前发生的变更被遗漏
+	if snapshot != nil {
+		c.SSEvent(OrderEventName, snapshot)
+		c.Writer.Flush()
+		if stopOnFinal && isFinalOrderStatus(snapshot.Status) {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(OrderEventHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event := <-events:
+			c.SSEvent(OrderEventName, event)
+			return !(stopOnFinal && isFinalOrderStatus(event.Status))
+		case <-heartbeat.C:
+			c.SSEvent("ping", time.Now().Unix())
+			return true
+		}
+	})
+}
+
+// isFinalOrderStatus 判断收银台订单是否已结束，支付成功或预授权成功后收银台无需继续等待
+func isFinalOrderStatus(status model.OrderStatus) bool {
+	switch status {
+	case model.OrderStatusSuccess, model.OrderStatusAuthorized,
+		model.OrderStatusExpired, model.OrderStatusCancelled, model.OrderStatusRefund:
+		return true
+	}
+	return false
+}
+
+// RecipientQuery 按用户名查找转账收款人，recipientID 不为空时同时校验 ID
+func RecipientQuery(tx *gorm.DB, recipientID uint64, username string) *gorm.DB {
+	query := tx.Where("username = ?", username)
+	if recipientID != 0 {
+		query = query.Where("id = ?", recipientID)
+	}
+	return query
+}
+
+// deleteOrderExpireKey 订单支付完成后删除过期监听 key
+func deleteOrderExpireKey(ctx context.Context, orderID uint64) {
+	if err := db.Redis.Del(ctx, fmt.Sprintf(OrderExpireKeyFormat, orderID)).Err(); err != nil {
+		log.Printf("[Payment] 删除订单过期key失败: order_id=%d, error=%v", orderID, err)
+	}
+}
+
+// authorizeOrder 将预授权订单金额从付款人可用余额转入冻结余额，并创建到期自动撤销的预授权记录
+func authorizeOrder(tx *gorm.DB, order *model.Order, payerUserID uint64, voidAfter time.Duration) error {
+	result := tx.Model(&model.User{}).
+		Where("id = ? AND available_balance >= ?", payerUserID, order.Amount).
+		UpdateColumns(map[string]interface{}{
+			"available_balance": gorm.Expr("available_balance - ?", order.Amount),
+			"held_balance":      gorm.Expr("held_balance + ?", order.Amount),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New(common.InsufficientBalance)
+	}
+
+	remark := AuthorizationRemark
+	if order.AllowanceID != 0 {
+		remark = AllowanceRemark + " " + remark
+	}
+	if order.Remark != "" {
+		order.Remark = order.Remark + " " + remark
+	} else {
+		order.Remark = remark
+	}
+
+	now := time.Now()
+	order.Status = model.OrderStatusAuthorized
+	order.PayerUserID = payerUserID
+	order.TradeTime = now
+	if err := tx.Save(order).Error; err != nil {
+		return err
+	}
+
+	return tx.Create(&model.OrderAuthorization{
+		OrderID:     order.ID,
+		ClientID:    order.ClientID,
+		PayerUserID: payerUserID,
+		HoldAmount:  order.Amount,
+		Status:      model.OrderAuthorizationStatusAuthorized,
+		VoidAt:      now.Add(voidAfter),
+	}).Error
+}
+
+// lockAuthorizedOrder 锁定待确认扣款的预授权订单及其预授权记录，clientID 为空时不校验所属应用
+func lockAuthorizedOrder(tx *gorm.DB, orderID uint64, clientID string) (*model.Order, *model.OrderAuthorization, error) {
+	query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND status = ? AND type = ?", orderID, model.OrderStatusAuthorized, model.OrderTypePayment)
+	if clientID != "" {
+		query = query.Where("client_id = ?", clientID)
+	}
+
+	var order model.Order
+	if err := query.First(&order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errors.New(AuthorizationNotFound)
+		}
+		return nil, nil, err
+	}
+
+	var authorization model.OrderAuthorization
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("order_id = ? AND status = ?", order.ID, model.OrderAuthorizationStatusAuthorized).
+		First(&authorization).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errors.New(AuthorizationNotFound)
+		}
+		return nil, nil, err
+	}
+
+	return &order, &authorization, nil
+}
+
+// releaseHeldBalance 解冻付款人的预授权冻结金额，captured 部分计入支付，其余退回可用余额
+func releaseHeldBalance(tx *gorm.DB, authorization *model.OrderAuthorization, captured decimal.Decimal) error {
+	result := tx.Model(&model.User{}).
+		Where("id = ? AND held_balance >= ?", authorization.PayerUserID, authorization.HoldAmount).
+		UpdateColumns(map[string]interface{}{
+			"held_balance":      gorm.Expr("held_balance - ?", authorization.HoldAmount),
+			"available_balance": gorm.Expr("available_balance + ?", authorization.HoldAmount.Sub(captured)),
+			"total_payment":     gorm.Expr("total_payment + ?", captured),
+			"pay_score":         gorm.Expr("pay_score + ?", captured.Round(0).IntPart()),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New(HeldBalanceMismatch)
+	}
+	return nil
+}
 
-	// 常量时间比较签名（防止时序攻击）
-	if subtle.ConstantTimeCompare([]byte(strings.ToLower(expectedSign)), []byte(strings.ToLower(req.Sign))) != 1 {
-		return nil, errors.New("签名验证失败")
+// captureAuthorization 按确认金额完成预授权扣款，商户按当前费率结算，未扣款部分解冻退回付款人
+func captureAuthorization(tx *gorm.DB, order *model.Order, authorization *model.OrderAuthorization, amount decimal.Decimal, merchantUserID uint64, merchantPayConfig *model.UserPayConfig) error {
+	if err := releaseHeldBalance(tx, authorization, amount); err != nil {
+		return err
 	}
 
-	return req.ToCreateOrderRequest(), nil
+	_, merchantAmount, feePercent := service.CalculateFee(amount, merchantPayConfig.FeeRate)
+	merchantScoreIncrease := amount.Mul(merchantPayConfig.ScoreRate).Round(0).IntPart()
+	if err := service.AddMerchantBalance(tx, merchantUserID, merchantAmount, merchantScoreIncrease); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	order.Remark = fmt.Sprintf("%s [系统]: 确认扣款%s，收取商家%d%%手续费", order.Remark, amount.StringFixed(2), feePercent)
+	order.Amount = amount
+	order.Status = model.OrderStatusSuccess
+	order.TradeTime = now
+	if err := tx.Model(order).Select("amount", "status", "remark", "trade_time").Updates(order).Error; err != nil {
+		return err
+	}
+
+	authorization.Status = model.OrderAuthorizationStatusCaptured
+	authorization.CapturedAmount = amount
+	authorization.CapturedAt = &now
+	return tx.Model(authorization).Select("status", "captured_amount", "captured_at").Updates(authorization).Error
+}
+
+// voidAuthorization 撤销预授权，冻结金额全部退回付款人可用余额并关闭订单
+func voidAuthorization(tx *gorm.DB, order *model.Order, authorization *model.OrderAuthorization) error {
+	if err := releaseHeldBalance(tx, authorization, decimal.Zero); err != nil {
+		return err
+	}
+
+	order.Status = model.OrderStatusCancelled
+	if err := tx.Model(order).Select("status").Updates(order).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	authorization.Status = model.OrderAuthorizationStatusVoided
+	authorization.VoidedAt = &now
+	return tx.Model(authorization).Select("status", "voided_at").Updates(authorization).Error
+}
+
+// newOrderAuthorizationResponse 构建预授权订单处理结果
+func newOrderAuthorizationResponse(authorization *model.OrderAuthorization) OrderAuthorizationResponse {
+	return OrderAuthorizationResponse{
+		TradeNo:        strconv.FormatUint(authorization.OrderID, 10),
+		Status:         authorization.Status,
+		HoldAmount:     authorization.HoldAmount,
+		CapturedAmount: authorization.CapturedAmount,
+	}
+}
+
+// respondAuthorizationError 将预授权扣款或撤销的错误映射为 HTTP 响应
+func respondAuthorizationError(c *gin.Context, err error) {
+	switch err.Error() {
+	case AuthorizationNotFound:
+		c.JSON(http.StatusNotFound, util.Err(err.Error()))
+	case CaptureAmountExceeded:
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+	}
 }
@@ -0,0 +1,201 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package payout
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/pay/internal/apps/payment"
+	"github.com/linux-do/pay/internal/common"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/util"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// PayoutItemRequest 付款明细
+type PayoutItemRequest struct {
+	UserID      uint64          `json:"user_id" binding:"required"`
+	Amount      decimal.Decimal `json:"amount" binding:"required"`
+	OutDetailNo string          `json:"out_detail_no" binding:"max=64"`
+	Remark      string          `json:"remark" binding:"max=100"`
+}
+
+// CreatePayoutRequest 创建付款请求，单笔付款传入一个明细即可
+type CreatePayoutRequest struct {
+	OutBatchNo string              `json:"out_batch_no" binding:"required,max=64"`
+	Remark     string              `json:"remark" binding:"max=100"`
+	Items      []PayoutItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+// CreatePayout 商户从余额向用户付款（Basic Auth，需要 payout:write 权限）
+// 付款异步处理，同一 out_batch_no 重复提交返回已有批次
+// @Tags payout
+// @Accept json
+// @Produce json
+// @Param request body CreatePayoutRequest true "request body"
+// @Param Idempotency-Key header string false "幂等键，重复请求返回首次请求的响应"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/merchant/payouts [post]
+func CreatePayout(c *gin.Context) {
+	var req CreatePayoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	maxBatchSize, errGet := model.GetIntByKey(c.Request.Context(), model.ConfigKeyPayoutMaxBatchSize)
+	if errGet != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(errGet.Error()))
+		return
+	}
+	if len(req.Items) > maxBatchSize {
+		c.JSON(http.StatusBadRequest, util.Err(fmt.Sprintf(PayoutItemsExceeded, maxBatchSize)))
+		return
+	}
+
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, payment.APIKeyObjKey)
+
+	totalAmount, errValidate := validatePayoutItems(apiKey, req.Items)
+	if errValidate != nil {
+		c.JSON(http.StatusBadRequest, util.Err(errValidate.Error()))
+		return
+	}
+
+	// 幂等：同一 out_batch_no 只创建一次批次
+	if existing, err := getPayoutBatch(db.DB(c.Request.Context()), apiKey.ClientID, req.OutBatchNo); err == nil {
+		replayPayoutBatch(c, existing, len(req.Items), totalAmount)
+		return
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	var merchantUser model.User
+	if err := db.DB(c.Request.Context()).Where("id = ? AND is_active = ?", apiKey.UserID, true).First(&merchantUser).Error; err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(PayoutMerchantNotFound))
+		return
+	}
+
+	batch := model.PayoutBatch{
+		ClientID:       apiKey.ClientID,
+		OutBatchNo:     req.OutBatchNo,
+		MerchantUserID: merchantUser.ID,
+		TotalCount:     len(req.Items),
+		TotalAmount:    totalAmount,
+		SuccessAmount:  decimal.Zero,
+		Status:         model.PayoutBatchStatusProcessing,
+		Remark:         req.Remark,
+		Items:          make([]model.PayoutItem, 0, len(req.Items)),
+	}
+	for _, item := range req.Items {
+		batch.Items = append(batch.Items, model.PayoutItem{
+			OutDetailNo:     item.OutDetailNo,
+			RecipientUserID: item.UserID,
+			Amount:          item.Amount,
+			Remark:          item.Remark,
+			Status:          model.PayoutItemStatusPending,
+		})
+	}
+
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			if err := tx.Create(&batch).Error; err != nil {
+				return err
+			}
+			return EnqueuePayoutBatch(batch.ID)
+		},
+	); err != nil {
+		// 并发提交同一 out_batch_no 时唯一索引冲突，返回已创建的批次
+		if existing, errGet := getPayoutBatch(db.DB(c.Request.Context()), apiKey.ClientID, req.OutBatchNo); errGet == nil {
+			replayPayoutBatch(c, existing, len(req.Items), totalAmount)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(batch))
+}
+
+// GetPayout 商户查询付款批次及各明细状态（Basic Auth，需要 payout:write 权限）
+// @Tags payout
+// @Produce json
+// @Param outBatchNo path string true "商户批次号"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/merchant/payouts/{outBatchNo} [get]
+func GetPayout(c *gin.Context) {
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, payment.APIKeyObjKey)
+
+	batch, err := getPayoutBatch(db.DB(c.Request.Context()), apiKey.ClientID, c.Param("outBatchNo"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(PayoutBatchNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(batch))
+}
+
+// validatePayoutItems 校验付款明细并返回付款总额
+func validatePayoutItems(apiKey *model.MerchantAPIKey, items []PayoutItemRequest) (decimal.Decimal, error) {
+	totalAmount := decimal.Zero
+	detailNos := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		if item.Amount.LessThanOrEqual(decimal.Zero) {
+			return decimal.Zero, errors.New(common.AmountMustBeGreaterThanZero)
+		}
+		if item.Amount.Exponent() < -2 {
+			return decimal.Zero, errors.New(common.AmountDecimalPlacesExceeded)
+		}
+		if item.UserID == apiKey.UserID {
+			return decimal.Zero, errors.New(CannotPayoutToSelf)
+		}
+		if item.OutDetailNo != "" {
+			if _, ok := detailNos[item.OutDetailNo]; ok {
+				return decimal.Zero, errors.New(DuplicateOutDetailNo)
+			}
+			detailNos[item.OutDetailNo] = struct{}{}
+		}
+		totalAmount = totalAmount.Add(item.Amount)
+	}
+	return totalAmount, nil
+}
+
+// replayPayoutBatch 返回已存在的批次，请求内容与原批次不一致时返回冲突
+func replayPayoutBatch(c *gin.Context, batch *model.PayoutBatch, totalCount int, totalAmount decimal.Decimal) {
+	if batch.TotalCount != totalCount || !batch.TotalAmount.Equal(totalAmount) {
+		c.JSON(http.StatusConflict, util.Err(OutBatchNoConflict))
+		return
+	}
+	c.JSON(http.StatusOK, util.OK(batch))
+}
@@ -0,0 +1,166 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package payout
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/linux-do/pay/internal/apps/payment"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/logger"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/task"
+	"github.com/linux-do/pay/internal/task/schedule"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EnqueuePayoutBatch 下发付款批次处理任务
+func EnqueuePayoutBatch(batchID uint64) error {
+	payload, _ := json.Marshal(map[string]interface{}{
+		"batch_id": batchID,
+	})
+	if _, errTask := schedule.AsynqClient.Enqueue(
+		asynq.NewTask(task.PayoutBatchTask, payload),
+		asynq.MaxRetry(5),
+		asynq.Timeout(5*time.Minute),
+	); errTask != nil {
+		return fmt.Errorf("下发付款批次任务失败: %w", errTask)
+	}
+	return nil
+}
+
+// HandlePayoutBatch 逐笔处理付款批次中的待付款明细，全部处理完成后汇总批次并回调商户
+func HandlePayoutBatch(ctx context.Context, t *asynq.Task) error {
+	var payload struct {
+		BatchID uint64 `json:"batch_id"`
+	}
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("解析任务参数失败: %w", err)
+	}
+
+	// 任务可能先于创建批次的事务提交执行，查询不到时返回错误等待重试
+	var batch model.PayoutBatch
+	if err := db.DB(ctx).Where("id = ?", payload.BatchID).First(&batch).Error; err != nil {
+		return fmt.Errorf("查询付款批次[ID:%d]失败: %w", payload.BatchID, err)
+	}
+	if batch.Status != model.PayoutBatchStatusProcessing {
+		return nil
+	}
+
+	var itemIDs []uint64
+	if err := db.DB(ctx).Model(&model.PayoutItem{}).
+		Where("batch_id = ? AND status = ?", batch.ID, model.PayoutItemStatusPending).
+		Order("id ASC").
+		Pluck("id", &itemIDs).Error; err != nil {
+		return err
+	}
+
+	for _, itemID := range itemIDs {
+		if err := db.DB(ctx).Transaction(func(tx *gorm.DB) error {
+			return processPayoutItem(tx, &batch, itemID)
+		}); err != nil {
+			logger.ErrorF(ctx, "处理付款明细[ID:%d]失败: %v", itemID, err)
+			return err
+		}
+	}
+
+	finished := false
+	if err := db.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND status = ?", batch.ID, model.PayoutBatchStatusProcessing).
+			First(&batch).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		var stats []struct {
+			Status model.PayoutItemStatus
+			Count  int
+			Amount decimal.Decimal
+		}
+		if err := tx.Model(&model.PayoutItem{}).
+			Select("status, COUNT(*) AS count, COALESCE(SUM(amount), 0) AS amount").
+			Where("batch_id = ?", batch.ID).
+			Group("status").
+			Scan(&stats).Error; err != nil {
+			return err
+		}
+
+		batch.SuccessCount, batch.SuccessAmount, batch.FailedCount = 0, decimal.Zero, 0
+		for _, stat := range stats {
+			switch stat.Status {
+			case model.PayoutItemStatusSuccess:
+				batch.SuccessCount = stat.Count
+				batch.SuccessAmount = stat.Amount
+			case model.PayoutItemStatusFailed:
+				batch.FailedCount = stat.Count
+			}
+		}
+		if batch.SuccessCount+batch.FailedCount < batch.TotalCount {
+			return fmt.Errorf("付款批次[ID:%d]仍有未处理的明细", batch.ID)
+		}
+
+		now := time.Now()
+		batch.Status = model.PayoutBatchStatusFinished
+		batch.FinishedAt = &now
+		finished = true
+		return tx.Model(&batch).
+			Select("status", "success_count", "success_amount", "failed_count", "finished_at").
+			Updates(&batch).Error
+	}); err != nil {
+		logger.ErrorF(ctx, "汇总付款批次[ID:%d]失败: %v", batch.ID, err)
+		return err
+	}
+
+	if !finished {
+		return nil
+	}
+
+	logger.InfoF(ctx, "付款批次[ID:%d]处理完成: 成功[%d] 失败[%d]", batch.ID, batch.SuccessCount, batch.FailedCount)
+
+	if err := payment.EnqueueMerchantEvent(batch.ClientID, EventBatchFinished, map[string]string{
+		"batch_id":       strconv.FormatUint(batch.ID, 10),
+		"out_batch_no":   batch.OutBatchNo,
+		"status":         string(batch.Status),
+		"total_count":    strconv.Itoa(batch.TotalCount),
+		"total_amount":   batch.TotalAmount.StringFixed(2),
+		"success_count":  strconv.Itoa(batch.SuccessCount),
+		"success_amount": batch.SuccessAmount.StringFixed(2),
+		"failed_count":   strconv.Itoa(batch.FailedCount),
+	}); err != nil {
+		logger.ErrorF(ctx, "%v", err)
+	}
+	return nil
+}
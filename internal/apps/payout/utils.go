@@ -0,0 +1,153 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package payout
+
+import (
+	"errors"
+	"time"
+
+	"github.com/linux-do/pay/internal/common"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// getPayoutBatch 按商户批次号查询批次及明细
+func getPayoutBatch(tx *gorm.DB, clientID, outBatchNo string) (*model.PayoutBatch, error) {
+	var batch model.PayoutBatch
+	if err := tx.
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Where("client_id = ? AND out_batch_no = ?", clientID, outBatchNo).
+		First(&batch).Error; err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// isPayoutFailure 判断是否为付款明细的业务失败，业务失败记录到明细上，不重试任务
+func isPayoutFailure(err error) bool {
+	switch err.Error() {
+	case common.InsufficientBalance, PayoutRecipientNotFound:
+		return true
+	}
+	return false
+}
+
+// payoutItem 执行单笔付款：创建 payout 订单，从商户余额转入收款用户
+func payoutItem(tx *gorm.DB, batch *model.PayoutBatch, item *model.PayoutItem) (*model.Order, error) {
+	var recipient model.User
+	if err := tx.Where("id = ? AND is_active = ?", item.RecipientUserID, true).First(&recipient).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New(PayoutRecipientNotFound)
+		}
+		return nil, err
+	}
+
+	remark := item.Remark
+	if remark == "" {
+		remark = batch.Remark
+	}
+	merchantOrderNo := item.OutDetailNo
+	if merchantOrderNo == "" {
+		merchantOrderNo = batch.OutBatchNo
+	}
+
+	now := time.Now()
+	order := model.Order{
+		OrderName:       OrderName,
+		MerchantOrderNo: merchantOrderNo,
+		ClientID:        batch.ClientID,
+		PayerUserID:     batch.MerchantUserID,
+		PayeeUserID:     recipient.ID,
+		Amount:          item.Amount,
+		Status:          model.OrderStatusSuccess,
+		Type:            model.OrderTypePayout,
+		Remark:          remark,
+		Metadata:        util.StringMap{"out_batch_no": batch.OutBatchNo},
+		TradeTime:       now,
+		ExpiresAt:       now,
+	}
+	if err := tx.Create(&order).Error; err != nil {
+		return nil, err
+	}
+
+	// 扣减商户余额
+	result := tx.Model(&model.User{}).
+		Where("id = ? AND available_balance >= ?", batch.MerchantUserID, item.Amount).
+		UpdateColumns(map[string]interface{}{
+			"available_balance": gorm.Expr("available_balance - ?", item.Amount),
+			"total_transfer":    gorm.Expr("total_transfer + ?", item.Amount),
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New(common.InsufficientBalance)
+	}
+
+	// 增加收款用户余额
+	if err := tx.Model(&model.User{}).
+		Where("id = ?", recipient.ID).
+		UpdateColumns(map[string]interface{}{
+			"available_balance": gorm.Expr("available_balance + ?", item.Amount),
+			"total_receive":     gorm.Expr("total_receive + ?", item.Amount),
+		}).Error; err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+// processPayoutItem 处理单个待付款明细，业务失败时将明细标记为失败
+func processPayoutItem(tx *gorm.DB, batch *model.PayoutBatch, itemID uint64) error {
+	var item model.PayoutItem
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND status = ?", itemID, model.PayoutItemStatusPending).
+		First(&item).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	var order *model.Order
+	if errPay := tx.Transaction(func(payTx *gorm.DB) error {
+		var err error
+		order, err = payoutItem(payTx, batch, &item)
+		return err
+	}); errPay != nil {
+		if !isPayoutFailure(errPay) {
+			return errPay
+		}
+		item.Status = model.PayoutItemStatusFailed
+		item.FailReason = errPay.Error()
+	} else {
+		item.Status = model.PayoutItemStatusSuccess
+		item.OrderID = order.ID
+	}
+
+	return tx.Model(&item).Select("status", "fail_reason", "order_id").Updates(&item).Error
+}
@@ -0,0 +1,266 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package receive_code
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/pay/internal/apps/oauth"
+	"github.com/linux-do/pay/internal/common"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/util"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// ReceiveCodeResponse 个人收款码
+type ReceiveCodeResponse struct {
+	Token     string    `json:"token"`
+	URL       string    `json:"url"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// GetMyReceiveCode 获取当前用户的个人收款码，首次获取时生成
+// @Tags receive_code
+// @Produce json
+// @Success 200 {object} ReceiveCodeResponse
+// @Router /api/v1/receive-code [get]
+func GetMyReceiveCode(c *gin.Context) {
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	receiveCode, err := getOrCreateReceiveCode(db.DB(c.Request.Context()), user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(ReceiveCodeResponse{
+		Token:     receiveCode.Token,
+		URL:       receiveCodeURL(receiveCode.Token),
+		UpdatedAt: receiveCode.UpdatedAt,
+	}))
+}
+
+// GetMyReceiveCodeQRCode 获取当前用户的个人收款码二维码
+// @Tags receive_code
+// @Produce png
+// @Produce image/svg+xml
+// @Param request query util.QRCodeRequest false "二维码参数"
+// @Success 200 {file} binary
+// @Router /api/v1/receive-code/qrcode [get]
+func GetMyReceiveCodeQRCode(c *gin.Context) {
+	var req util.QRCodeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	receiveCode, err := getOrCreateReceiveCode(db.DB(c.Request.Context()), user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	util.RenderQRCode(c, receiveCodeURL(receiveCode.Token), &req)
+}
+
+// RegenerateReceiveCode 重新生成个人收款码，旧收款码立即失效
+// @Tags receive_code
+// @Produce json
+// @Success 200 {object} ReceiveCodeResponse
+// @Router /api/v1/receive-code/regenerate [post]
+func RegenerateReceiveCode(c *gin.Context) {
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	var receiveCode *model.UserReceiveCode
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			var err error
+			if receiveCode, err = getOrCreateReceiveCode(tx, user.ID); err != nil {
+				return err
+			}
+
+			receiveCode.Token = util.GenerateUniqueIDSimple()
+			return tx.Model(receiveCode).Select("token").Updates(receiveCode).Error
+		},
+	); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(ReceiveCodeResponse{
+		Token:     receiveCode.Token,
+		URL:       receiveCodeURL(receiveCode.Token),
+		UpdatedAt: receiveCode.UpdatedAt,
+	}))
+}
+
+// ReceiveCodeOwner 收款码所属用户的公开信息
+type ReceiveCodeOwner struct {
+	ID        uint64 `json:"id"`
+	Username  string `json:"username"`
+	Nickname  string `json:"nickname"`
+	AvatarUrl string `json:"avatar_url"`
+}
+
+// GetReceiveCodeOwner 通过收款码 token 查询收款用户的公开信息
+// @Tags receive_code
+// @Produce json
+// @Param token path string true "收款码 token"
+// @Success 200 {object} ReceiveCodeOwner
+// @Router /api/v1/receive-codes/{token} [get]
+func GetReceiveCodeOwner(c *gin.Context) {
+	var owner ReceiveCodeOwner
+	if err := db.DB(c.Request.Context()).
+		Model(&model.UserReceiveCode{}).
+		Select("users.id, users.username, users.nickname, users.avatar_url").
+		Joins("JOIN users ON users.id = user_receive_codes.user_id").
+		Where("user_receive_codes.token = ? AND users.is_active = ?", c.Param("token"), true).
+		Take(&owner).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(ReceiveCodeNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(owner))
+}
+
+// PayReceiveCodeRequest 向收款码付款请求
+type PayReceiveCodeRequest struct {
+	Amount decimal.Decimal `json:"amount" binding:"required"`
+	PayKey string          `json:"pay_key" binding:"required,max=6"`
+	Remark string          `json:"remark" binding:"max=100"`
+}
+
+// PayReceiveCode 向个人收款码付款，由付款人填写金额和备注，生成 transfer 订单
+// @Tags receive_code
+// @Accept json
+// @Produce json
+// @Param token path string true "收款码 token"
+// @Param request body PayReceiveCodeRequest true "request body"
+// @Param Idempotency-Key header string false "幂等键，重复请求返回首次请求的响应"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/receive-codes/{token}/pay [post]
+func PayReceiveCode(c *gin.Context) {
+	var req PayReceiveCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	if req.Amount.LessThanOrEqual(decimal.Zero) {
+		c.JSON(http.StatusBadRequest, util.Err(common.AmountMustBeGreaterThanZero))
+		return
+	}
+	if req.Amount.Exponent() < -2 {
+		c.JSON(http.StatusBadRequest, util.Err(common.AmountDecimalPlacesExceeded))
+		return
+	}
+
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	if subtle.ConstantTimeCompare([]byte(currentUser.PayKey), []byte(req.PayKey)) != 1 {
+		c.JSON(http.StatusBadRequest, util.Err(common.PayKeyIncorrect))
+		return
+	}
+
+	var order model.Order
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			var owner model.User
+			if err := tx.Joins("JOIN user_receive_codes ON user_receive_codes.user_id = users.id").
+				Where("user_receive_codes.token = ? AND users.is_active = ?", c.Param("token"), true).
+				First(&owner).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New(ReceiveCodeNotFound)
+				}
+				return err
+			}
+			if owner.ID == currentUser.ID {
+				return errors.New(CannotPayOwnReceiveCode)
+			}
+
+			// 扣减付款人余额
+			result := tx.Model(&model.User{}).
+				Where("id = ? AND available_balance >= ?", currentUser.ID, req.Amount).
+				UpdateColumns(map[string]interface{}{
+					"available_balance": gorm.Expr("available_balance - ?", req.Amount),
+					"total_transfer":    gorm.Expr("total_transfer + ?", req.Amount),
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return errors.New(common.InsufficientBalance)
+			}
+
+			// 增加收款人余额
+			if err := tx.Model(&model.User{}).
+				Where("id = ?", owner.ID).
+				UpdateColumns(map[string]interface{}{
+					"available_balance": gorm.Expr("available_balance + ?", req.Amount),
+					"total_receive":     gorm.Expr("total_receive + ?", req.Amount),
+				}).Error; err != nil {
+				return err
+			}
+
+			now := time.Now()
+			order = model.Order{
+				OrderName:   OrderName,
+				PayerUserID: currentUser.ID,
+				PayeeUserID: owner.ID,
+				Amount:      req.Amount,
+				Status:      model.OrderStatusSuccess,
+				Type:        model.OrderTypeTransfer,
+				Remark:      req.Remark,
+				TradeTime:   now,
+				ExpiresAt:   now,
+			}
+			return tx.Create(&order).Error
+		},
+	); err != nil {
+		switch err.Error() {
+		case ReceiveCodeNotFound:
+			c.JSON(http.StatusNotFound, util.Err(err.Error()))
+		case CannotPayOwnReceiveCode, common.InsufficientBalance:
+			c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(order))
+}
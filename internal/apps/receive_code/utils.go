@@ -0,0 +1,65 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package receive_code
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/linux-do/pay/internal/config"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// receiveCodeURL 收款码对应的付款页面地址
+func receiveCodeURL(token string) string {
+	return fmt.Sprintf("%s/receive?token=%s", strings.TrimRight(config.Config.App.FrontendPayURL, "/"), url.QueryEscape(token))
+}
+
+// getOrCreateReceiveCode 查询用户收款码，不存在时生成
+func getOrCreateReceiveCode(tx *gorm.DB, userID uint64) (*model.UserReceiveCode, error) {
+	var receiveCode model.UserReceiveCode
+	err := tx.Where("user_id = ?", userID).First(&receiveCode).Error
+	if err == nil {
+		return &receiveCode, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	// 并发请求同时生成时以先写入的为准
+	receiveCode = model.UserReceiveCode{UserID: userID, Token: util.GenerateUniqueIDSimple()}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&receiveCode).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("user_id = ?", userID).First(&receiveCode).Error; err != nil {
+		return nil, err
+	}
+	return &receiveCode, nil
+}
@@ -0,0 +1,369 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package red_packet
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/pay/internal/apps/oauth"
+	"github.com/linux-do/pay/internal/common"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/util"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateRedPacketRequest 发红包请求
+type CreateRedPacketRequest struct {
+	TotalAmount   decimal.Decimal          `json:"total_amount" binding:"required"`
+	Count         int                      `json:"count" binding:"required,min=1"`
+	SplitMode     model.RedPacketSplitMode `json:"split_mode" binding:"required,oneof=equal random"`
+	MinTrustLevel model.TrustLevel         `json:"min_trust_level" binding:"max=4"`
+	Greeting      string                   `json:"greeting" binding:"max=64"`
+	PayKey        string                   `json:"pay_key" binding:"required,max=6"`
+}
+
+// CreateRedPacket 发红包，总金额立即从余额扣除
+// @Tags red_packet
+// @Accept json
+// @Produce json
+// @Param request body CreateRedPacketRequest true "request body"
+// @Param Idempotency-Key header string false "幂等键，重复请求返回首次请求的响应"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/red-packets [post]
+func CreateRedPacket(c *gin.Context) {
+	var req CreateRedPacketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	if req.TotalAmount.LessThanOrEqual(decimal.Zero) {
+		c.JSON(http.StatusBadRequest, util.Err(common.AmountMustBeGreaterThanZero))
+		return
+	}
+	if req.TotalAmount.Exponent() < -2 {
+		c.JSON(http.StatusBadRequest, util.Err(common.AmountDecimalPlacesExceeded))
+		return
+	}
+
+	maxCount, errGet := model.GetIntByKey(c.Request.Context(), model.ConfigKeyRedPacketMaxCount)
+	if errGet != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(errGet.Error()))
+		return
+	}
+	if req.Count > maxCount {
+		c.JSON(http.StatusBadRequest, util.Err(fmt.Sprintf(CountOutOfRange, maxCount)))
+		return
+	}
+	if req.TotalAmount.Shift(2).IntPart() < int64(req.Count) {
+		c.JSON(http.StatusBadRequest, util.Err(AmountBelowCountMinimum))
+		return
+	}
+
+	expireHours, errGet := model.GetIntByKey(c.Request.Context(), model.ConfigKeyRedPacketExpireHours)
+	if errGet != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(errGet.Error()))
+		return
+	}
+
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	if subtle.ConstantTimeCompare([]byte(currentUser.PayKey), []byte(req.PayKey)) != 1 {
+		c.JSON(http.StatusBadRequest, util.Err(common.PayKeyIncorrect))
+		return
+	}
+
+	packet := model.RedPacket{
+		Token:           util.GenerateUniqueIDSimple(),
+		SenderUserID:    currentUser.ID,
+		TotalAmount:     req.TotalAmount,
+		TotalCount:      req.Count,
+		RemainingAmount: req.TotalAmount,
+		RemainingCount:  req.Count,
+		SplitMode:       req.SplitMode,
+		MinTrustLevel:   req.MinTrustLevel,
+		Greeting:        req.Greeting,
+		Status:          model.RedPacketStatusActive,
+		RefundedAmount:  decimal.Zero,
+		ExpiresAt:       time.Now().Add(time.Duration(expireHours) * time.Hour),
+	}
+
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			// 扣减发送者余额
+			result := tx.Model(&model.User{}).
+				Where("id = ? AND available_balance >= ?", currentUser.ID, req.TotalAmount).
+				UpdateColumns(map[string]interface{}{
+					"available_balance": gorm.Expr("available_balance - ?", req.TotalAmount),
+					"total_transfer":    gorm.Expr("total_transfer + ?", req.TotalAmount),
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return errors.New(common.InsufficientBalance)
+			}
+
+			return tx.Create(&packet).Error
+		},
+	); err != nil {
+		if err.Error() == common.InsufficientBalance {
+			c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	packet.SenderUsername = currentUser.Username
+	c.JSON(http.StatusOK, util.OK(packet))
+}
+
+// ListRedPacketsRequest 查询红包列表请求
+type ListRedPacketsRequest struct {
+	Page     int    `json:"page" form:"page" binding:"min=1"`
+	PageSize int    `json:"page_size" form:"page_size" binding:"min=1,max=100"`
+	Status   string `json:"status" form:"status" binding:"omitempty,oneof=active finished refunded"`
+}
+
+// ListRedPacketsResponse 查询红包列表响应
+type ListRedPacketsResponse struct {
+	Total      int64             `json:"total"`
+	Page       int               `json:"page"`
+	PageSize   int               `json:"page_size"`
+	RedPackets []model.RedPacket `json:"red_packets"`
+}
+
+// ListRedPackets 查询当前用户发出的红包
+// @Tags red_packet
+// @Produce json
+// @Param request query ListRedPacketsRequest true "查询参数"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/red-packets [get]
+func ListRedPackets(c *gin.Context) {
+	var req ListRedPacketsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	baseQuery := db.DB(c.Request.Context()).Model(&model.RedPacket{}).Where("sender_user_id = ?", user.ID)
+	if req.Status != "" {
+		baseQuery = baseQuery.Where("status = ?", model.RedPacketStatus(req.Status))
+	}
+
+	var total int64
+	if err := baseQuery.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	response := &ListRedPacketsResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := baseQuery.Order("created_at DESC").Offset(offset).Limit(req.PageSize).Find(&response.RedPackets).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// RedPacketDetailResponse 红包详情响应
+type RedPacketDetailResponse struct {
+	model.RedPacket
+	Claims  []model.RedPacketClaim `json:"claims"`
+	MyClaim *model.RedPacketClaim  `json:"my_claim"`
+}
+
+// GetRedPacket 通过 token 查询红包详情及领取记录
+// @Tags red_packet
+// @Produce json
+// @Param token path string true "红包 token"
+// @Success 200 {object} RedPacketDetailResponse
+// @Router /api/v1/red-packets/{token} [get]
+func GetRedPacket(c *gin.Context) {
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	var response RedPacketDetailResponse
+	if err := db.DB(c.Request.Context()).Model(&model.RedPacket{}).
+		Select("red_packets.*, users.username as sender_username").
+		Joins("JOIN users ON red_packets.sender_user_id = users.id").
+		Where("red_packets.token = ?", c.Param("token")).
+		First(&response.RedPacket).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(RedPacketNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	if err := db.DB(c.Request.Context()).Model(&model.RedPacketClaim{}).
+		Select("red_packet_claims.*, users.username").
+		Joins("JOIN users ON red_packet_claims.user_id = users.id").
+		Where("red_packet_claims.red_packet_id = ?", response.ID).
+		Order("red_packet_claims.id ASC").
+		Find(&response.Claims).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	for i := range response.Claims {
+		if response.Claims[i].UserID == user.ID {
+			response.MyClaim = &response.Claims[i]
+			break
+		}
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// ClaimRedPacket 领取红包，锁定红包行保证并发安全，领取成功生成 red_packet 订单
+// @Tags red_packet
+// @Produce json
+// @Param token path string true "红包 token"
+// @Param Idempotency-Key header string false "幂等键，重复请求返回首次请求的响应"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/red-packets/{token}/claim [post]
+func ClaimRedPacket(c *gin.Context) {
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	var claim model.RedPacketClaim
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			var packet model.RedPacket
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("token = ?", c.Param("token")).
+				First(&packet).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New(RedPacketNotFound)
+				}
+				return err
+			}
+
+			if packet.SenderUserID == currentUser.ID {
+				return errors.New(CannotClaimOwnPacket)
+			}
+			if currentUser.TrustLevel < packet.MinTrustLevel {
+				return errors.New(TrustLevelTooLow)
+			}
+
+			var claimed int64
+			if err := tx.Model(&model.RedPacketClaim{}).
+				Where("red_packet_id = ? AND user_id = ?", packet.ID, currentUser.ID).
+				Count(&claimed).Error; err != nil {
+				return err
+			}
+			if claimed > 0 {
+				return errors.New(RedPacketAlreadyClaimed)
+			}
+
+			switch {
+			case packet.Status == model.RedPacketStatusFinished || packet.RemainingCount == 0:
+				return errors.New(RedPacketFinished)
+			case packet.Status != model.RedPacketStatusActive || !packet.ExpiresAt.After(time.Now()):
+				return errors.New(RedPacketExpired)
+			}
+
+			amount := nextClaimAmount(&packet)
+
+			now := time.Now()
+			order := model.Order{
+				OrderName:   packet.Greeting,
+				PayerUserID: packet.SenderUserID,
+				PayeeUserID: currentUser.ID,
+				Amount:      amount,
+				Status:      model.OrderStatusSuccess,
+				Type:        model.OrderTypeRedPacket,
+				RedPacketID: packet.ID,
+				TradeTime:   now,
+				ExpiresAt:   now,
+			}
+			if order.OrderName == "" {
+				order.OrderName = OrderName
+			}
+			if err := tx.Create(&order).Error; err != nil {
+				return err
+			}
+
+			claim = model.RedPacketClaim{
+				RedPacketID: packet.ID,
+				UserID:      currentUser.ID,
+				Amount:      amount,
+				OrderID:     order.ID,
+				Username:    currentUser.Username,
+			}
+			if err := tx.Create(&claim).Error; err != nil {
+				return err
+			}
+
+			packet.RemainingAmount = packet.RemainingAmount.Sub(amount)
+			packet.RemainingCount--
+			if packet.RemainingCount == 0 {
+				packet.Status = model.RedPacketStatusFinished
+			}
+			if err := tx.Model(&packet).Select("remaining_amount", "remaining_count", "status").Updates(&packet).Error; err != nil {
+				return err
+			}
+
+			// 增加领取者余额
+			return tx.Model(&model.User{}).
+				Where("id = ?", currentUser.ID).
+				UpdateColumns(map[string]interface{}{
+					"available_balance": gorm.Expr("available_balance + ?", amount),
+					"total_receive":     gorm.Expr("total_receive + ?", amount),
+				}).Error
+		},
+	); err != nil {
+		switch err.Error() {
+		case RedPacketNotFound:
+			c.JSON(http.StatusNotFound, util.Err(err.Error()))
+		case RedPacketExpired, RedPacketFinished, RedPacketAlreadyClaimed, CannotClaimOwnPacket:
+			c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		case TrustLevelTooLow:
+			c.JSON(http.StatusForbidden, util.Err(err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(claim))
+}
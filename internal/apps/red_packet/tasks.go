@@ -0,0 +1,138 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package red_packet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/linux-do/pay/internal/config"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/logger"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/task"
+	"github.com/linux-do/pay/internal/task/schedule"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// HandleRedPacketRefundDue 查询所有已过期且未领完的红包并逐个下发退款任务
+func HandleRedPacketRefundDue(ctx context.Context, t *asynq.Task) error {
+	pageSize := 200
+	lastID := uint64(0)
+	currentDelay := 0 * time.Second
+	now := time.Now()
+
+	for {
+		var packets []model.RedPacket
+		if err := db.DB(ctx).
+			Where("id > ? AND status = ? AND expires_at <= ?", lastID, model.RedPacketStatusActive, now).
+			Order("id ASC").
+			Limit(pageSize).
+			Find(&packets).Error; err != nil {
+			logger.ErrorF(ctx, "查询过期红包失败: %v", err)
+			return err
+		}
+
+		// 没有更多红包，退出循环
+		if len(packets) == 0 {
+			break
+		}
+
+		for _, packet := range packets {
+			currentDelay += time.Duration(config.Config.Schedule.RedPacketRefundDispatchIntervalSeconds) * time.Second
+
+			payload, _ := json.Marshal(map[string]interface{}{
+				"red_packet_id": packet.ID,
+			})
+
+			if _, errTask := schedule.AsynqClient.Enqueue(
+				asynq.NewTask(task.RedPacketRefundSingleTask, payload),
+				asynq.ProcessIn(currentDelay),
+				asynq.MaxRetry(3),
+			); errTask != nil {
+				logger.ErrorF(ctx, "下发红包[ID:%d]退款任务失败: %v", packet.ID, errTask)
+				return errTask
+			} else {
+				logger.InfoF(ctx, "下发红包[ID:%d]退款任务成功", packet.ID)
+			}
+		}
+
+		lastID = packets[len(packets)-1].ID
+	}
+	return nil
+}
+
+// HandleRedPacketRefundSingle 将过期红包的剩余金额退回发送者
+func HandleRedPacketRefundSingle(ctx context.Context, t *asynq.Task) error {
+	var payload struct {
+		RedPacketID uint64 `json:"red_packet_id"`
+	}
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("解析任务参数失败: %w", err)
+	}
+
+	if err := db.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		var packet model.RedPacket
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND status = ? AND expires_at <= ?", payload.RedPacketID, model.RedPacketStatusActive, time.Now()).
+			First(&packet).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				logger.InfoF(ctx, "红包[ID:%d]已被处理或未过期，跳过", payload.RedPacketID)
+				return nil
+			}
+			return err
+		}
+
+		refundAmount := packet.RemainingAmount
+		packet.Status = model.RedPacketStatusRefunded
+		packet.RefundedAmount = refundAmount
+		if err := tx.Model(&packet).Select("status", "refunded_amount").Updates(&packet).Error; err != nil {
+			return err
+		}
+
+		if refundAmount.IsZero() {
+			return nil
+		}
+
+		// 剩余金额退回发送者
+		return tx.Model(&model.User{}).
+			Where("id = ?", packet.SenderUserID).
+			UpdateColumns(map[string]interface{}{
+				"available_balance": gorm.Expr("available_balance + ?", refundAmount),
+				"total_transfer":    gorm.Expr("total_transfer - ?", refundAmount),
+			}).Error
+	}); err != nil {
+		logger.ErrorF(ctx, "红包[ID:%d]退款失败: %v", payload.RedPacketID, err)
+		return err
+	}
+
+	logger.InfoF(ctx, "红包[ID:%d]过期退款处理完成", payload.RedPacketID)
+	return nil
+}
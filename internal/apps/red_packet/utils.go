@@ -0,0 +1,60 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package red_packet
+
+import (
+	"math/rand/v2"
+
+	"github.com/linux-do/pay/internal/model"
+	"github.com/shopspring/decimal"
+)
+
+// nextClaimAmount 计算下一个领取者的金额，按分计算保证每人至少 0.01
+// 平均模式下余数由最后一人领取；拼手气模式使用二倍均值法，单次最多领取剩余均值的两倍
+func nextClaimAmount(packet *model.RedPacket) decimal.Decimal {
+	remainingCents := packet.RemainingAmount.Shift(2).IntPart()
+	remainingCount := int64(packet.RemainingCount)
+	if remainingCount <= 1 {
+		return packet.RemainingAmount
+	}
+
+	var cents int64
+	switch packet.SplitMode {
+	case model.RedPacketSplitModeRandom:
+		maxCents := remainingCents / remainingCount * 2
+		// 为后续领取者每人预留 0.01
+		if limit := remainingCents - (remainingCount - 1); maxCents > limit {
+			maxCents = limit
+		}
+		cents = 1
+		if maxCents > 1 {
+			cents = rand.Int64N(maxCents) + 1
+		}
+	default:
+		cents = packet.TotalAmount.Shift(2).IntPart() / int64(packet.TotalCount)
+	}
+
+	return decimal.New(cents, -2)
+}
@@ -0,0 +1,43 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package redeem
+
+import "time"
+
+const (
+	// RedeemFailureKeyFormat Redis key 格式，记录用户在时间窗口内输入错误兑换码的次数
+	RedeemFailureKeyFormat = "redeem:failures:%d"
+	// RedeemFailureWindow 错误次数统计窗口
+	RedeemFailureWindow = time.Hour
+)
+
+const (
+	// codeAlphabet 兑换码字符集，去除易混淆的 0/O、1/I
+	codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	// codeLength 兑换码长度
+	codeLength = 16
+	// codeInsertBatchSize 批量写入兑换码的每批数量
+	codeInsertBatchSize = 200
+)
@@ -0,0 +1,38 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package redeem
+
+const (
+	RedeemBatchNotFound      = "兑换码批次不存在"
+	RedeemBatchNotActive     = "兑换码批次已停用"
+	RedeemCodeInvalid        = "兑换码无效"
+	RedeemCodeExpired        = "兑换码已过期"
+	RedeemCodeExhausted      = "兑换码已被兑换完"
+	RedeemCodeAlreadyUsed    = "您已兑换过该兑换码"
+	RedeemPerUserExceeded    = "已达到该批次每人兑换次数上限"
+	CannotRedeemOwnCode      = "不能兑换自己发放的兑换码"
+	RedeemTooManyAttempts    = "兑换码错误次数过多，请稍后再试"
+	RedeemExpiresAtNotFuture = "过期时间必须晚于当前时间"
+)
@@ -0,0 +1,533 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package redeem
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/pay/internal/apps/oauth"
+	"github.com/linux-do/pay/internal/common"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/util"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateRedeemBatchRequest 创建兑换码批次请求
+type CreateRedeemBatchRequest struct {
+	Name         string          `json:"name" binding:"required,max=64"`
+	Amount       decimal.Decimal `json:"amount" binding:"required"`
+	CodeCount    int             `json:"code_count" binding:"required,min=1,max=1000"`
+	MaxUses      int             `json:"max_uses" binding:"omitempty,min=1,max=10000"`
+	PerUserLimit int             `json:"per_user_limit" binding:"omitempty,min=1"`
+	ExpiresAt    *time.Time      `json:"expires_at"`
+}
+
+// CreateRedeemBatch 创建兑换码批次，商户批次从商户余额预扣全部额度
+// @Tags redeem
+// @Accept json
+// @Produce json
+// @Param request body CreateRedeemBatchRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/redeem-batches [post]
+// @Router /api/v1/merchant/api-keys/{id}/redeem-batches [post]
+func CreateRedeemBatch(c *gin.Context) {
+	var req CreateRedeemBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	if req.Amount.LessThanOrEqual(decimal.Zero) {
+		c.JSON(http.StatusBadRequest, util.Err(common.AmountMustBeGreaterThanZero))
+		return
+	}
+	if req.Amount.Exponent() < -2 {
+		c.JSON(http.StatusBadRequest, util.Err(common.AmountDecimalPlacesExceeded))
+		return
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, util.Err(RedeemExpiresAtNotFuture))
+		return
+	}
+	if req.MaxUses == 0 {
+		req.MaxUses = 1
+	}
+	if req.PerUserLimit == 0 {
+		req.PerUserLimit = 1
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+	apiKey := merchantAPIKey(c)
+
+	batch := model.RedeemCodeBatch{
+		CreatorUserID:  user.ID,
+		Name:           req.Name,
+		Amount:         req.Amount,
+		CodeCount:      req.CodeCount,
+		MaxUses:        req.MaxUses,
+		PerUserLimit:   req.PerUserLimit,
+		TotalBudget:    req.Amount.Mul(decimal.NewFromInt(int64(req.CodeCount * req.MaxUses))),
+		RedeemedAmount: decimal.Zero,
+		RefundedAmount: decimal.Zero,
+		Status:         model.RedeemCodeBatchStatusActive,
+		ExpiresAt:      req.ExpiresAt,
+	}
+	if apiKey != nil {
+		batch.ClientID = apiKey.ClientID
+		batch.CreatorUserID = apiKey.UserID
+	}
+
+	codes := make([]model.RedeemCode, 0, req.CodeCount)
+	for range req.CodeCount {
+		code, err := generateRedeemCode()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+			return
+		}
+		codes = append(codes, model.RedeemCode{Code: code, MaxUses: req.MaxUses})
+	}
+
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			// 商户批次从商户余额预扣全部额度
+			if batch.ClientID != "" {
+				result := tx.Model(&model.User{}).
+					Where("id = ? AND available_balance >= ?", batch.CreatorUserID, batch.TotalBudget).
+					UpdateColumns(map[string]interface{}{
+						"available_balance": gorm.Expr("available_balance - ?", batch.TotalBudget),
+						"total_transfer":    gorm.Expr("total_transfer + ?", batch.TotalBudget),
+					})
+				if result.Error != nil {
+					return result.Error
+				}
+				if result.RowsAffected == 0 {
+					return errors.New(common.InsufficientBalance)
+				}
+			}
+
+			if err := tx.Create(&batch).Error; err != nil {
+				return err
+			}
+			for i := range codes {
+				codes[i].BatchID = batch.ID
+			}
+			return tx.CreateInBatches(&codes, codeInsertBatchSize).Error
+		},
+	); err != nil {
+		if err.Error() == common.InsufficientBalance {
+			c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(batch))
+}
+
+// ListRedeemBatchesRequest 查询兑换码批次列表请求
+type ListRedeemBatchesRequest struct {
+	Page     int    `json:"page" form:"page" binding:"min=1"`
+	PageSize int    `json:"page_size" form:"page_size" binding:"min=1,max=100"`
+	Status   string `json:"status" form:"status" binding:"omitempty,oneof=active disabled"`
+}
+
+// ListRedeemBatchesResponse 查询兑换码批次列表响应
+type ListRedeemBatchesResponse struct {
+	Total    int64                   `json:"total"`
+	Page     int                     `json:"page"`
+	PageSize int                     `json:"page_size"`
+	Batches  []model.RedeemCodeBatch `json:"batches"`
+}
+
+// ListRedeemBatches 查询兑换码批次列表
+// @Tags redeem
+// @Produce json
+// @Param request query ListRedeemBatchesRequest true "查询参数"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/redeem-batches [get]
+// @Router /api/v1/merchant/api-keys/{id}/redeem-batches [get]
+func ListRedeemBatches(c *gin.Context) {
+	var req ListRedeemBatchesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	baseQuery := batchScope(c)(db.DB(c.Request.Context()).Model(&model.RedeemCodeBatch{}))
+	if req.Status != "" {
+		baseQuery = baseQuery.Where("status = ?", model.RedeemCodeBatchStatus(req.Status))
+	}
+
+	var total int64
+	if err := baseQuery.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	response := &ListRedeemBatchesResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := baseQuery.Order("created_at DESC").Offset(offset).Limit(req.PageSize).Find(&response.Batches).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// RedeemBatchStatsResponse 兑换码批次使用统计
+type RedeemBatchStatsResponse struct {
+	Batch           model.RedeemCodeBatch `json:"batch"`
+	UsedCodes       int64                 `json:"used_codes"`
+	ExhaustedCodes  int64                 `json:"exhausted_codes"`
+	UniqueUsers     int64                 `json:"unique_users"`
+	RemainingBudget decimal.Decimal       `json:"remaining_budget"`
+}
+
+// GetRedeemBatchStats 查询兑换码批次使用统计
+// @Tags redeem
+// @Produce json
+// @Param batchId path uint64 true "批次 ID"
+// @Success 200 {object} RedeemBatchStatsResponse
+// @Router /api/v1/admin/redeem-batches/{batchId}/stats [get]
+// @Router /api/v1/merchant/api-keys/{id}/redeem-batches/{batchId}/stats [get]
+func GetRedeemBatchStats(c *gin.Context) {
+	var response RedeemBatchStatsResponse
+	if !findRedeemBatch(c, &response.Batch) {
+		return
+	}
+
+	if err := db.DB(c.Request.Context()).Model(&model.RedeemCode{}).
+		Select("COUNT(*) FILTER (WHERE used_count > 0) AS used_codes, COUNT(*) FILTER (WHERE used_count >= max_uses) AS exhausted_codes").
+		Where("batch_id = ?", response.Batch.ID).
+		Scan(&response).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	if err := db.DB(c.Request.Context()).Model(&model.RedeemCodeUse{}).
+		Where("batch_id = ?", response.Batch.ID).
+		Distinct("user_id").
+		Count(&response.UniqueUsers).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	response.RemainingBudget = response.Batch.TotalBudget.Sub(response.Batch.RedeemedAmount).Sub(response.Batch.RefundedAmount)
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// ExportRedeemBatch 导出兑换码批次为 CSV
+// @Tags redeem
+// @Produce text/csv
+// @Param batchId path uint64 true "批次 ID"
+// @Success 200 {string} string "CSV 文件"
+// @Router /api/v1/admin/redeem-batches/{batchId}/export [get]
+// @Router /api/v1/merchant/api-keys/{id}/redeem-batches/{batchId}/export [get]
+func ExportRedeemBatch(c *gin.Context) {
+	var batch model.RedeemCodeBatch
+	if !findRedeemBatch(c, &batch) {
+		return
+	}
+
+	var codes []model.RedeemCode
+	if err := db.DB(c.Request.Context()).
+		Where("batch_id = ?", batch.ID).
+		Order("id ASC").
+		Find(&codes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	expired := batch.ExpiresAt != nil && !batch.ExpiresAt.After(time.Now())
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=redeem_batch_%d.csv", batch.ID))
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	_ = writer.Write([]string{"code", "amount", "max_uses", "used_count", "status", "expires_at"})
+	for _, code := range codes {
+		status := "available"
+		switch {
+		case batch.Status == model.RedeemCodeBatchStatusDisabled:
+			status = "disabled"
+		case code.UsedCount >= code.MaxUses:
+			status = "exhausted"
+		case expired:
+			status = "expired"
+		}
+		expiresAt := ""
+		if batch.ExpiresAt != nil {
+			expiresAt = batch.ExpiresAt.Format(time.RFC3339)
+		}
+		_ = writer.Write([]string{
+			code.Code,
+			batch.Amount.StringFixed(2),
+			strconv.Itoa(code.MaxUses),
+			strconv.Itoa(code.UsedCount),
+			status,
+			expiresAt,
+		})
+	}
+	writer.Flush()
+}
+
+// DisableRedeemBatch 停用兑换码批次，商户批次未兑换的额度退回商户余额
+// @Tags redeem
+// @Produce json
+// @Param batchId path uint64 true "批次 ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/redeem-batches/{batchId}/disable [post]
+// @Router /api/v1/merchant/api-keys/{id}/redeem-batches/{batchId}/disable [post]
+func DisableRedeemBatch(c *gin.Context) {
+	var batch model.RedeemCodeBatch
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			if err := batchScope(c)(tx.Clauses(clause.Locking{Strength: "UPDATE"})).
+				Where("id = ?", c.Param("batchId")).
+				First(&batch).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New(RedeemBatchNotFound)
+				}
+				return err
+			}
+			if batch.Status != model.RedeemCodeBatchStatusActive {
+				return errors.New(RedeemBatchNotActive)
+			}
+
+			batch.Status = model.RedeemCodeBatchStatusDisabled
+			if batch.ClientID != "" {
+				batch.RefundedAmount = batch.TotalBudget.Sub(batch.RedeemedAmount)
+			}
+			if err := tx.Model(&batch).Select("status", "refunded_amount").Updates(&batch).Error; err != nil {
+				return err
+			}
+
+			if batch.RefundedAmount.IsZero() {
+				return nil
+			}
+			return tx.Model(&model.User{}).
+				Where("id = ?", batch.CreatorUserID).
+				UpdateColumns(map[string]interface{}{
+					"available_balance": gorm.Expr("available_balance + ?", batch.RefundedAmount),
+					"total_transfer":    gorm.Expr("total_transfer - ?", batch.RefundedAmount),
+				}).Error
+		},
+	); err != nil {
+		switch err.Error() {
+		case RedeemBatchNotFound:
+			c.JSON(http.StatusNotFound, util.Err(err.Error()))
+		case RedeemBatchNotActive:
+			c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(batch))
+}
+
+// RedeemRequest 兑换请求
+type RedeemRequest struct {
+	Code string `json:"code" binding:"required,max=64"`
+}
+
+// Redeem 用户兑换兑换码，金额计入可用余额，输入错误次数过多时限制兑换
+// @Tags redeem
+// @Accept json
+// @Produce json
+// @Param request body RedeemRequest true "request body"
+// @Param Idempotency-Key header string false "幂等键，重复请求返回首次请求的响应"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/redeem [post]
+func Redeem(c *gin.Context) {
+	var req RedeemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	maxFailures, errGet := model.GetIntByKey(c.Request.Context(), model.ConfigKeyRedeemMaxFailuresPerHour)
+	if errGet != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(errGet.Error()))
+		return
+	}
+	failures, errFailures := redeemFailures(c.Request.Context(), currentUser.ID)
+	if errFailures != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(errFailures.Error()))
+		return
+	}
+	if failures >= maxFailures {
+		c.JSON(http.StatusTooManyRequests, util.Err(RedeemTooManyAttempts))
+		return
+	}
+
+	var use model.RedeemCodeUse
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			var code model.RedeemCode
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("code = ?", normalizeRedeemCode(req.Code)).
+				First(&code).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New(RedeemCodeInvalid)
+				}
+				return err
+			}
+
+			var batch model.RedeemCodeBatch
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("id = ?", code.BatchID).
+				First(&batch).Error; err != nil {
+				return err
+			}
+
+			switch {
+			case batch.Status != model.RedeemCodeBatchStatusActive:
+				return errors.New(RedeemBatchNotActive)
+			case batch.ExpiresAt != nil && !batch.ExpiresAt.After(time.Now()):
+				return errors.New(RedeemCodeExpired)
+			case code.UsedCount >= code.MaxUses:
+				return errors.New(RedeemCodeExhausted)
+			case batch.ClientID != "" && batch.CreatorUserID == currentUser.ID:
+				return errors.New(CannotRedeemOwnCode)
+			}
+
+			var codeUsed, batchUsed int64
+			if err := tx.Model(&model.RedeemCodeUse{}).
+				Where("code_id = ? AND user_id = ?", code.ID, currentUser.ID).
+				Count(&codeUsed).Error; err != nil {
+				return err
+			}
+			if codeUsed > 0 {
+				return errors.New(RedeemCodeAlreadyUsed)
+			}
+			if err := tx.Model(&model.RedeemCodeUse{}).
+				Where("batch_id = ? AND user_id = ?", batch.ID, currentUser.ID).
+				Count(&batchUsed).Error; err != nil {
+				return err
+			}
+			if batchUsed >= int64(batch.PerUserLimit) {
+				return errors.New(RedeemPerUserExceeded)
+			}
+
+			now := time.Now()
+			order := model.Order{
+				OrderName:   batch.Name,
+				ClientID:    batch.ClientID,
+				PayeeUserID: currentUser.ID,
+				Amount:      batch.Amount,
+				Status:      model.OrderStatusSuccess,
+				Type:        model.OrderTypeRedeem,
+				TradeTime:   now,
+				ExpiresAt:   now,
+			}
+			// 平台批次由平台出资，付款方为空
+			if batch.ClientID != "" {
+				order.PayerUserID = batch.CreatorUserID
+			}
+			if err := tx.Create(&order).Error; err != nil {
+				return err
+			}
+
+			use = model.RedeemCodeUse{
+				BatchID: batch.ID,
+				CodeID:  code.ID,
+				UserID:  currentUser.ID,
+				Amount:  batch.Amount,
+				OrderID: order.ID,
+			}
+			if err := tx.Create(&use).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Model(&code).UpdateColumn("used_count", gorm.Expr("used_count + 1")).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&batch).UpdateColumns(map[string]interface{}{
+				"redeemed_count":  gorm.Expr("redeemed_count + 1"),
+				"redeemed_amount": gorm.Expr("redeemed_amount + ?", batch.Amount),
+			}).Error; err != nil {
+				return err
+			}
+
+			// 增加兑换用户余额
+			return tx.Model(&model.User{}).
+				Where("id = ?", currentUser.ID).
+				UpdateColumns(map[string]interface{}{
+					"available_balance": gorm.Expr("available_balance + ?", batch.Amount),
+					"total_receive":     gorm.Expr("total_receive + ?", batch.Amount),
+				}).Error
+		},
+	); err != nil {
+		switch err.Error() {
+		case RedeemCodeInvalid:
+			_ = recordRedeemFailure(c.Request.Context(), currentUser.ID)
+			c.JSON(http.StatusNotFound, util.Err(err.Error()))
+		case RedeemBatchNotActive, RedeemCodeExpired, RedeemCodeExhausted, RedeemCodeAlreadyUsed,
+			RedeemPerUserExceeded, CannotRedeemOwnCode:
+			c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(use))
+}
+
+// findRedeemBatch 在当前范围内查询兑换码批次，未找到时写入错误响应并返回 false
+func findRedeemBatch(c *gin.Context, batch *model.RedeemCodeBatch) bool {
+	if err := batchScope(c)(db.DB(c.Request.Context())).
+		Where("id = ?", c.Param("batchId")).
+		First(batch).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(RedeemBatchNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,100 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package redeem
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/pay/internal/apps/merchant"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/util"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// generateRedeemCode 生成随机兑换码
+func generateRedeemCode() (string, error) {
+	buf := make([]byte, codeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	for i, b := range buf {
+		buf[i] = codeAlphabet[int(b)%len(codeAlphabet)]
+	}
+	return string(buf), nil
+}
+
+// normalizeRedeemCode 规范化用户输入的兑换码，忽略大小写、空格和连字符
+func normalizeRedeemCode(code string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(code)))
+}
+
+// merchantAPIKey 获取商户路由中的 API Key，管理员路由返回 nil
+func merchantAPIKey(c *gin.Context) *model.MerchantAPIKey {
+	apiKey, ok := util.GetFromContext[*model.MerchantAPIKey](c, merchant.APIKeyObjKey)
+	if !ok {
+		return nil
+	}
+	return apiKey
+}
+
+// batchScope 限定批次查询范围：商户路由只能访问本应用的批次，管理员路由只能访问平台批次
+func batchScope(c *gin.Context) func(*gorm.DB) *gorm.DB {
+	clientID := ""
+	if apiKey := merchantAPIKey(c); apiKey != nil {
+		clientID = apiKey.ClientID
+	}
+	return func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("client_id = ?", clientID)
+	}
+}
+
+// redeemFailures 查询用户当前窗口内的兑换错误次数
+func redeemFailures(ctx context.Context, userID uint64) (int, error) {
+	count, err := db.Redis.Get(ctx, fmt.Sprintf(RedeemFailureKeyFormat, userID)).Int()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, err
+	}
+	return count, nil
+}
+
+// recordRedeemFailure 记录一次兑换错误，窗口从第一次错误开始计算
+func recordRedeemFailure(ctx context.Context, userID uint64) error {
+	key := fmt.Sprintf(RedeemFailureKeyFormat, userID)
+	count, err := db.Redis.Incr(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+	if count == 1 {
+		return db.Redis.Expire(ctx, key, RedeemFailureWindow).Err()
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package scheduled_transfer
+
+import "time"
+
+const (
+	// OrderName 定时转账生成的转账订单名称
+	OrderName = "定时转账"
+	// NotifyTitleFailed 定时转账执行失败的站内通知标题
+	NotifyTitleFailed = "定时转账执行失败"
+	// minCronInterval 周期转账相邻两次执行的最小间隔
+	minCronInterval = time.Hour
+	// cronCheckFirings 校验执行间隔时检查的连续执行次数，足以覆盖至少一个完整的执行日
+	cronCheckFirings = 50
+	// scheduleTimezone 周期表达式使用的时区，与定时任务调度器保持一致
+	scheduleTimezone = "Asia/Shanghai"
+)
@@ -0,0 +1,36 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package scheduled_transfer
+
+const (
+	ScheduledTransferNotFound     = "定时转账不存在"
+	ScheduledTransferNotActive    = "定时转账已结束或已取消"
+	ScheduleRequired              = "必须指定执行时间或周期"
+	RunAtMustBeFuture             = "执行时间必须晚于当前时间"
+	EndsAtMustBeAfterNextRun      = "结束时间必须晚于首次执行时间"
+	CronExprInvalid               = "周期表达式格式错误"
+	CronIntervalTooShort          = "周期转账的执行间隔不能小于 1 小时"
+	ScheduledTransferLimitReached = "生效中的定时转账数量已达上限"
+)
@@ -0,0 +1,325 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package scheduled_transfer
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/pay/internal/apps/oauth"
+	"github.com/linux-do/pay/internal/apps/payment"
+	"github.com/linux-do/pay/internal/common"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/util"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// CreateScheduledTransferRequest 创建定时转账请求
+// recipient_id 为空时仅按用户名查找收款人；仅指定 run_at 时为单次转账；指定 cron_expr（标准 5 段 cron 表达式，Asia/Shanghai 时区）时为周期转账，run_at 作为周期开始时间
+type CreateScheduledTransferRequest struct {
+	RecipientID       uint64          `json:"recipient_id"`
+	RecipientUsername string          `json:"recipient_username" binding:"required"`
+	Amount            decimal.Decimal `json:"amount" binding:"required"`
+	PayKey            string          `json:"pay_key" binding:"required,max=6"`
+	Remark            string          `json:"remark" binding:"max=100"`
+	RunAt             *time.Time      `json:"run_at"`
+	CronExpr          string          `json:"cron_expr" binding:"max=64"`
+	EndsAt            *time.Time      `json:"ends_at"`
+}
+
+// CreateScheduledTransfer 创建定时转账
+// @Tags scheduled_transfer
+// @Accept json
+// @Produce json
+// @Param request body CreateScheduledTransferRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/scheduled-transfers [post]
+func CreateScheduledTransfer(c *gin.Context) {
+	var req CreateScheduledTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	if req.Amount.LessThanOrEqual(decimal.Zero) {
+		c.JSON(http.StatusBadRequest, util.Err(common.AmountMustBeGreaterThanZero))
+		return
+	}
+	if req.Amount.Exponent() < -2 {
+		c.JSON(http.StatusBadRequest, util.Err(common.AmountDecimalPlacesExceeded))
+		return
+	}
+
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	if subtle.ConstantTimeCompare([]byte(currentUser.PayKey), []byte(req.PayKey)) != 1 {
+		c.JSON(http.StatusBadRequest, util.Err(common.PayKeyIncorrect))
+		return
+	}
+
+	if currentUser.Username == req.RecipientUsername {
+		c.JSON(http.StatusBadRequest, util.Err(payment.CannotTransferToSelf))
+		return
+	}
+
+	next, errSchedule := resolveSchedule(req.RunAt, req.CronExpr, req.EndsAt)
+	if errSchedule != nil {
+		respondScheduledTransferError(c, errSchedule)
+		return
+	}
+
+	maxActive, errGet := model.GetIntByKey(c.Request.Context(), model.ConfigKeyScheduledTransferMaxActive)
+	if errGet != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(errGet.Error()))
+		return
+	}
+
+	transfer := model.ScheduledTransfer{
+		SenderUserID: currentUser.ID,
+		Amount:       req.Amount,
+		Remark:       req.Remark,
+		CronExpr:     req.CronExpr,
+		NextRunAt:    next,
+		EndsAt:       req.EndsAt,
+		Status:       model.ScheduledTransferStatusActive,
+	}
+
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			// 验证收款人是否存在且用户名匹配
+			var recipient model.User
+			if err := payment.RecipientQuery(tx, req.RecipientID, req.RecipientUsername).First(&recipient).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New(payment.RecipientNotFound)
+				}
+				return err
+			}
+
+			var activeCount int64
+			if err := tx.Model(&model.ScheduledTransfer{}).
+				Where("sender_user_id = ? AND status = ?", currentUser.ID, model.ScheduledTransferStatusActive).
+				Count(&activeCount).Error; err != nil {
+				return err
+			}
+			if activeCount >= int64(maxActive) {
+				return errors.New(ScheduledTransferLimitReached)
+			}
+
+			transfer.RecipientUserID = recipient.ID
+			transfer.RecipientUsername = recipient.Username
+			return tx.Create(&transfer).Error
+		},
+	); err != nil {
+		respondScheduledTransferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(transfer))
+}
+
+// ListScheduledTransfersRequest 查询定时转账列表请求
+type ListScheduledTransfersRequest struct {
+	Page     int    `json:"page" form:"page" binding:"min=1"`
+	PageSize int    `json:"page_size" form:"page_size" binding:"min=1,max=100"`
+	Status   string `json:"status" form:"status" binding:"omitempty,oneof=active completed failed cancelled"`
+}
+
+// ListScheduledTransfersResponse 查询定时转账列表响应
+type ListScheduledTransfersResponse struct {
+	Total              int64                     `json:"total"`
+	Page               int                       `json:"page"`
+	PageSize           int                       `json:"page_size"`
+	ScheduledTransfers []model.ScheduledTransfer `json:"scheduled_transfers"`
+}
+
+// ListScheduledTransfers 查询当前用户创建的定时转账
+// @Tags scheduled_transfer
+// @Produce json
+// @Param request query ListScheduledTransfersRequest true "查询参数"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/scheduled-transfers [get]
+func ListScheduledTransfers(c *gin.Context) {
+	var req ListScheduledTransfersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	baseQuery := scheduledTransferQuery(db.DB(c.Request.Context())).
+		Where("scheduled_transfers.sender_user_id = ?", user.ID)
+	if req.Status != "" {
+		baseQuery = baseQuery.Where("scheduled_transfers.status = ?", model.ScheduledTransferStatus(req.Status))
+	}
+
+	var total int64
+	if err := baseQuery.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	response := &ListScheduledTransfersResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := baseQuery.Order("scheduled_transfers.created_at DESC").Offset(offset).Limit(req.PageSize).Find(&response.ScheduledTransfers).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// GetScheduledTransfer 查询定时转账详情
+// @Tags scheduled_transfer
+// @Produce json
+// @Param id path uint64 true "定时转账 ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/scheduled-transfers/{id} [get]
+func GetScheduledTransfer(c *gin.Context) {
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	var transfer model.ScheduledTransfer
+	if err := scheduledTransferQuery(db.DB(c.Request.Context())).
+		Where("scheduled_transfers.id = ? AND scheduled_transfers.sender_user_id = ?", c.Param("id"), user.ID).
+		First(&transfer).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(ScheduledTransferNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(transfer))
+}
+
+// UpdateScheduledTransferRequest 修改定时转账请求，收款人不可修改
+type UpdateScheduledTransferRequest struct {
+	Amount   decimal.Decimal `json:"amount" binding:"required"`
+	PayKey   string          `json:"pay_key" binding:"required,max=6"`
+	Remark   string          `json:"remark" binding:"max=100"`
+	RunAt    *time.Time      `json:"run_at"`
+	CronExpr string          `json:"cron_expr" binding:"max=64"`
+	EndsAt   *time.Time      `json:"ends_at"`
+}
+
+// UpdateScheduledTransfer 修改生效中的定时转账，修改后重新计算下次执行时间并清零连续失败次数
+// @Tags scheduled_transfer
+// @Accept json
+// @Produce json
+// @Param id path uint64 true "定时转账 ID"
+// @Param request body UpdateScheduledTransferRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/scheduled-transfers/{id} [put]
+func UpdateScheduledTransfer(c *gin.Context) {
+	var req UpdateScheduledTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	if req.Amount.LessThanOrEqual(decimal.Zero) {
+		c.JSON(http.StatusBadRequest, util.Err(common.AmountMustBeGreaterThanZero))
+		return
+	}
+	if req.Amount.Exponent() < -2 {
+		c.JSON(http.StatusBadRequest, util.Err(common.AmountDecimalPlacesExceeded))
+		return
+	}
+
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	if subtle.ConstantTimeCompare([]byte(currentUser.PayKey), []byte(req.PayKey)) != 1 {
+		c.JSON(http.StatusBadRequest, util.Err(common.PayKeyIncorrect))
+		return
+	}
+
+	next, errSchedule := resolveSchedule(req.RunAt, req.CronExpr, req.EndsAt)
+	if errSchedule != nil {
+		respondScheduledTransferError(c, errSchedule)
+		return
+	}
+
+	var transfer model.ScheduledTransfer
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			if err := lockActiveScheduledTransfer(tx, c.Param("id"), currentUser.ID, &transfer); err != nil {
+				return err
+			}
+
+			transfer.Amount = req.Amount
+			transfer.Remark = req.Remark
+			transfer.CronExpr = req.CronExpr
+			transfer.NextRunAt = next
+			transfer.EndsAt = req.EndsAt
+			transfer.FailureCount = 0
+			return tx.Model(&transfer).
+				Select("amount", "remark", "cron_expr", "next_run_at", "ends_at", "failure_count").
+				Updates(&transfer).Error
+		},
+	); err != nil {
+		respondScheduledTransferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(transfer))
+}
+
+// CancelScheduledTransfer 取消生效中的定时转账
+// @Tags scheduled_transfer
+// @Produce json
+// @Param id path uint64 true "定时转账 ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/scheduled-transfers/{id}/cancel [post]
+func CancelScheduledTransfer(c *gin.Context) {
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	var transfer model.ScheduledTransfer
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			if err := lockActiveScheduledTransfer(tx, c.Param("id"), currentUser.ID, &transfer); err != nil {
+				return err
+			}
+
+			transfer.Status = model.ScheduledTransferStatusCancelled
+			return tx.Model(&transfer).Select("status").Updates(&transfer).Error
+		},
+	); err != nil {
+		respondScheduledTransferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(transfer))
+}
@@ -0,0 +1,190 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package scheduled_transfer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/linux-do/pay/internal/config"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/logger"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/service"
+	"github.com/linux-do/pay/internal/task"
+	"github.com/linux-do/pay/internal/task/schedule"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// HandleScheduledTransferDue 查询所有到期的定时转账并逐个下发执行任务
+func HandleScheduledTransferDue(ctx context.Context, t *asynq.Task) error {
+	pageSize := 200
+	lastID := uint64(0)
+	currentDelay := 0 * time.Second
+	now := time.Now()
+
+	for {
+		var transfers []model.ScheduledTransfer
+		if err := db.DB(ctx).
+			Where("id > ? AND status = ? AND next_run_at <= ?", lastID, model.ScheduledTransferStatusActive, now).
+			Order("id ASC").
+			Limit(pageSize).
+			Find(&transfers).Error; err != nil {
+			logger.ErrorF(ctx, "查询到期定时转账失败: %v", err)
+			return err
+		}
+
+		// 没有更多定时转账，退出循环
+		if len(transfers) == 0 {
+			break
+		}
+
+		for _, transfer := range transfers {
+			currentDelay += time.Duration(config.Config.Schedule.ScheduledTransferDispatchIntervalSeconds) * time.Second
+
+			payload, _ := json.Marshal(map[string]interface{}{
+				"scheduled_transfer_id": transfer.ID,
+			})
+
+			if _, errTask := schedule.AsynqClient.Enqueue(
+				asynq.NewTask(task.ScheduledTransferSingleTask, payload),
+				asynq.ProcessIn(currentDelay),
+				asynq.MaxRetry(3),
+			); errTask != nil {
+				logger.ErrorF(ctx, "下发定时转账[ID:%d]执行任务失败: %v", transfer.ID, errTask)
+				return errTask
+			} else {
+				logger.InfoF(ctx, "下发定时转账[ID:%d]执行任务成功", transfer.ID)
+			}
+		}
+
+		lastID = transfers[len(transfers)-1].ID
+	}
+	return nil
+}
+
+// HandleScheduledTransferSingle 执行单个到期的定时转账
+// 余额不足、收款人不存在等业务失败会记录到定时转账并通知付款人：单次转账直接失败，周期转账连续失败达到上限后停止
+func HandleScheduledTransferSingle(ctx context.Context, t *asynq.Task) error {
+	var payload struct {
+		ScheduledTransferID uint64 `json:"scheduled_transfer_id"`
+	}
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("解析任务参数失败: %w", err)
+	}
+
+	maxFailures, errGet := model.GetIntByKey(ctx, model.ConfigKeyScheduledTransferMaxFailures)
+	if errGet != nil {
+		return errGet
+	}
+
+	var transfer model.ScheduledTransfer
+	var order *model.Order
+	if err := db.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+			Where("id = ? AND status = ? AND next_run_at <= ?", payload.ScheduledTransferID, model.ScheduledTransferStatusActive, now).
+			First(&transfer).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				logger.InfoF(ctx, "定时转账[ID:%d]未到期或已被处理，跳过", payload.ScheduledTransferID)
+				return nil
+			}
+			return err
+		}
+
+		var errTransfer error
+		if errTransfer = tx.Transaction(func(transferTx *gorm.DB) error {
+			var err error
+			order, err = executeTransfer(transferTx, &transfer)
+			return err
+		}); errTransfer != nil && !isTransferFailure(errTransfer) {
+			return errTransfer
+		}
+
+		transfer.LastRunAt = &now
+		if errTransfer == nil {
+			transfer.RunCount++
+			transfer.FailureCount = 0
+			transfer.LastOrderID = order.ID
+			transfer.LastError = ""
+		} else {
+			order = nil
+			transfer.FailureCount++
+			transfer.LastError = errTransfer.Error()
+			logger.InfoF(ctx, "定时转账[ID:%d]执行失败: %v", transfer.ID, errTransfer)
+		}
+
+		// 单次转账执行后结束；周期转账计算下次执行时间，超过结束时间或连续失败达到上限后结束
+		switch {
+		case !transfer.IsRecurring():
+			transfer.Status = model.ScheduledTransferStatusCompleted
+			if errTransfer != nil {
+				transfer.Status = model.ScheduledTransferStatusFailed
+			}
+		case errTransfer != nil && transfer.FailureCount >= maxFailures:
+			transfer.Status = model.ScheduledTransferStatusFailed
+		default:
+			next, err := nextRunAt(transfer.CronExpr, now)
+			if err != nil {
+				return err
+			}
+			transfer.NextRunAt = next
+			if transfer.EndsAt != nil && next.After(*transfer.EndsAt) {
+				transfer.Status = model.ScheduledTransferStatusCompleted
+			}
+		}
+
+		if err := tx.Model(&transfer).
+			Select("status", "next_run_at", "run_count", "failure_count", "last_run_at", "last_order_id", "last_error").
+			Updates(&transfer).Error; err != nil {
+			return err
+		}
+
+		if errTransfer == nil {
+			return nil
+		}
+		if err := tx.Model(&model.User{}).
+			Where("id = ?", transfer.RecipientUserID).
+			Select("username").
+			Scan(&transfer.RecipientUsername).Error; err != nil {
+			return err
+		}
+		return service.CreateNotification(tx, transfer.SenderUserID, model.NotificationTypeScheduledTransfer, NotifyTitleFailed,
+			failureContent(&transfer, errTransfer.Error()), transfer.ID)
+	}); err != nil {
+		logger.ErrorF(ctx, "处理定时转账[ID:%d]失败: %v", payload.ScheduledTransferID, err)
+		return err
+	}
+
+	if order != nil {
+		logger.InfoF(ctx, "定时转账[ID:%d]执行成功: 订单[ID:%d] 金额[%s]", transfer.ID, order.ID, order.Amount.String())
+	}
+	return nil
+}
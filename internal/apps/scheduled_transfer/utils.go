@@ -0,0 +1,237 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package scheduled_transfer
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/pay/internal/apps/payment"
+	"github.com/linux-do/pay/internal/common"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/util"
+	"github.com/robfig/cron/v3"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	scheduleLocation     *time.Location
+	scheduleLocationOnce sync.Once
+)
+
+// location 返回周期表达式使用的时区，加载失败时回退到本地时区
+func location() *time.Location {
+	scheduleLocationOnce.Do(func() {
+		loc, err := time.LoadLocation(scheduleTimezone)
+		if err != nil {
+			loc = time.Local
+		}
+		scheduleLocation = loc
+	})
+	return scheduleLocation
+}
+
+// parseCronExpr 解析标准 5 段 cron 表达式，并校验接下来连续 cronCheckFirings 次执行的相邻间隔均不小于 minCronInterval
+// 只比较前两次执行会漏掉同一天内的短间隔，例如 "0,59 0 * * *" 在 00:30 创建时前两次间隔接近一天
+func parseCronExpr(expr string) (cron.Schedule, error) {
+	schedule, err := cron.ParseStandard(expr)
+	if err != nil {
+		return nil, errors.New(CronExprInvalid)
+	}
+
+	prev := schedule.Next(time.Now().In(location()))
+	if prev.IsZero() {
+		return nil, errors.New(CronExprInvalid)
+	}
+	for i := 1; i < cronCheckFirings; i++ {
+		next := schedule.Next(prev)
+		if next.IsZero() {
+			break
+		}
+		if next.Sub(prev) < minCronInterval {
+			return nil, errors.New(CronIntervalTooShort)
+		}
+		prev = next
+	}
+	return schedule, nil
+}
+
+// nextRunAt 计算周期转账在 after 之后的下一次执行时间
+func nextRunAt(expr string, after time.Time) (time.Time, error) {
+	schedule, err := cron.ParseStandard(expr)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return schedule.Next(after.In(location())), nil
+}
+
+// resolveSchedule 根据请求中的执行时间和周期表达式计算首次执行时间
+// 周期转账指定 runAt 时从 runAt 起按周期执行，否则从当前时间起计算
+func resolveSchedule(runAt *time.Time, cronExpr string, endsAt *time.Time) (time.Time, error) {
+	now := time.Now()
+	if runAt != nil && !runAt.After(now) {
+		return time.Time{}, errors.New(RunAtMustBeFuture)
+	}
+
+	var next time.Time
+	switch {
+	case cronExpr != "":
+		schedule, err := parseCronExpr(cronExpr)
+		if err != nil {
+			return time.Time{}, err
+		}
+		start := now
+		if runAt != nil {
+			start = runAt.Add(-time.Second)
+		}
+		next = schedule.Next(start.In(location()))
+	case runAt != nil:
+		next = *runAt
+	default:
+		return time.Time{}, errors.New(ScheduleRequired)
+	}
+
+	if endsAt != nil && !endsAt.After(next) {
+		return time.Time{}, errors.New(EndsAtMustBeAfterNextRun)
+	}
+	return next, nil
+}
+
+// scheduledTransferQuery 定时转账查询，附带收款人用户名
+func scheduledTransferQuery(tx *gorm.DB) *gorm.DB {
+	return tx.Model(&model.ScheduledTransfer{}).
+		Select("scheduled_transfers.*, recipient_user.username as recipient_username").
+		Joins("JOIN users as recipient_user ON scheduled_transfers.recipient_user_id = recipient_user.id")
+}
+
+// lockActiveScheduledTransfer 锁定当前用户生效中的定时转账
+func lockActiveScheduledTransfer(tx *gorm.DB, id string, userID uint64, transfer *model.ScheduledTransfer) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+		Where("id = ? AND sender_user_id = ?", id, userID).
+		First(transfer).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New(ScheduledTransferNotFound)
+		}
+		return err
+	}
+	if transfer.Status != model.ScheduledTransferStatusActive {
+		return errors.New(ScheduledTransferNotActive)
+	}
+	return nil
+}
+
+// executeTransfer 执行一次定时转账，校验规则与 payment.Transfer 保持一致
+func executeTransfer(tx *gorm.DB, transfer *model.ScheduledTransfer) (*model.Order, error) {
+	if transfer.SenderUserID == transfer.RecipientUserID {
+		return nil, errors.New(payment.CannotTransferToSelf)
+	}
+
+	var recipient model.User
+	if err := tx.Where("id = ? AND is_active = ?", transfer.RecipientUserID, true).First(&recipient).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New(payment.RecipientNotFound)
+		}
+		return nil, err
+	}
+
+	// 扣减付款人余额
+	result := tx.Model(&model.User{}).
+		Where("id = ? AND available_balance >= ?", transfer.SenderUserID, transfer.Amount).
+		UpdateColumns(map[string]interface{}{
+			"available_balance": gorm.Expr("available_balance - ?", transfer.Amount),
+			"total_transfer":    gorm.Expr("total_transfer + ?", transfer.Amount),
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New(common.InsufficientBalance)
+	}
+
+	// 增加收款人余额
+	if err := tx.Model(&model.User{}).
+		Where("id = ?", recipient.ID).
+		UpdateColumns(map[string]interface{}{
+			"available_balance": gorm.Expr("available_balance + ?", transfer.Amount),
+			"total_receive":     gorm.Expr("total_receive + ?", transfer.Amount),
+		}).Error; err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	order := model.Order{
+		OrderName:           OrderName,
+		PayerUserID:         transfer.SenderUserID,
+		PayeeUserID:         recipient.ID,
+		Amount:              transfer.Amount,
+		Status:              model.OrderStatusSuccess,
+		Type:                model.OrderTypeTransfer,
+		Remark:              transfer.Remark,
+		ScheduledTransferID: transfer.ID,
+		TradeTime:           now,
+		ExpiresAt:           now,
+	}
+	if err := tx.Create(&order).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// isTransferFailure 判断是否为业务原因导致的转账失败，此类失败记录到定时转账并通知用户，不重试任务
+func isTransferFailure(err error) bool {
+	switch err.Error() {
+	case common.InsufficientBalance, payment.RecipientNotFound, payment.CannotTransferToSelf:
+		return true
+	default:
+		return false
+	}
+}
+
+// failureContent 定时转账执行失败的通知内容
+func failureContent(transfer *model.ScheduledTransfer, reason string) string {
+	content := fmt.Sprintf("向 %s 的 %s 定时转账执行失败：%s", transfer.RecipientUsername, transfer.Amount.StringFixed(2), reason)
+	if transfer.Status == model.ScheduledTransferStatusFailed {
+		content += "，该定时转账已停止"
+	}
+	return content
+}
+
+// respondScheduledTransferError 将定时转账错误映射为 HTTP 响应
+func respondScheduledTransferError(c *gin.Context, err error) {
+	switch err.Error() {
+	case ScheduledTransferNotFound, payment.RecipientNotFound:
+		c.JSON(http.StatusNotFound, util.Err(err.Error()))
+	case ScheduledTransferNotActive, ScheduleRequired, RunAtMustBeFuture, EndsAtMustBeAfterNextRun, CronExprInvalid,
+		CronIntervalTooShort, ScheduledTransferLimitReached, payment.CannotTransferToSelf, common.PayKeyIncorrect:
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+	}
+}
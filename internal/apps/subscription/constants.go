@@ -0,0 +1,64 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+package subscription
+
+import "github.com/linux-do/pay/internal/model"
+
+const (
+	// EventPrefix 订阅事件回调名称前缀，事件名为前缀加订阅状态，如 subscription.past_due
+	EventPrefix = "subscription."
+	// EventRenewed 订阅扣款成功事件
+	EventRenewed = "subscription.renewed"
+)
+
+// subscriptionAction 订阅状态变更操作
+type subscriptionAction string
+
+const (
+	actionCancel subscriptionAction = "cancel"
+	actionPause  subscriptionAction = "pause"
+	actionResume subscriptionAction = "resume"
+)
+
+// actionFromStatuses 各操作允许的原状态
+var actionFromStatuses = map[subscriptionAction][]model.SubscriptionStatus{
+	actionCancel: {model.SubscriptionStatusTrialing, model.SubscriptionStatusActive, model.SubscriptionStatusPastDue, model.SubscriptionStatusPaused},
+	actionPause:  {model.SubscriptionStatusTrialing, model.SubscriptionStatusActive},
+	actionResume: {model.SubscriptionStatusPaused},
+}
+
+// billableStatuses 需要续费扣款的订阅状态
+var billableStatuses = []model.SubscriptionStatus{
+	model.SubscriptionStatusTrialing,
+	model.SubscriptionStatusActive,
+	model.SubscriptionStatusPastDue,
+}
+
+// ongoingStatuses 未结束的订阅状态，同一用户对同一计划只能有一个
+var ongoingStatuses = []model.SubscriptionStatus{
+	model.SubscriptionStatusTrialing,
+	model.SubscriptionStatusActive,
+	model.SubscriptionStatusPastDue,
+	model.SubscriptionStatusPaused,
+}
@@ -0,0 +1,528 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+package subscription
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/pay/internal/apps/merchant"
+	"github.com/linux-do/pay/internal/apps/oauth"
+	"github.com/linux-do/pay/internal/common"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/util"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PlanRequest 创建/更新订阅计划请求
+type PlanRequest struct {
+	Name          string                     `json:"name" binding:"required,max=30"`
+	Description   string                     `json:"description" binding:"max=100"`
+	Amount        decimal.Decimal            `json:"amount"`
+	Interval      model.SubscriptionInterval `json:"interval" binding:"required,oneof=day week month year"`
+	IntervalCount int                        `json:"interval_count" binding:"omitempty,min=1,max=365"`
+	TrialDays     int                        `json:"trial_days" binding:"min=0,max=365"`
+	IsActive      *bool                      `json:"is_active"`
+}
+
+// validate 校验订阅计划参数
+func (req *PlanRequest) validate() error {
+	if req.Amount.LessThanOrEqual(decimal.Zero) {
+		return errors.New(common.AmountMustBeGreaterThanZero)
+	}
+	if req.Amount.Exponent() < -2 {
+		return errors.New(common.AmountDecimalPlacesExceeded)
+	}
+	if req.IntervalCount == 0 {
+		req.IntervalCount = 1
+	}
+	if req.IsActive == nil {
+		isActive := true
+		req.IsActive = &isActive
+	}
+	return nil
+}
+
+// CreatePlan 创建订阅计划
+// @Tags subscription
+// @Accept json
+// @Produce json
+// @Param id path uint64 true "API Key ID"
+// @Param request body PlanRequest true "订阅计划"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/merchant/api-keys/{id}/subscription-plans [post]
+func CreatePlan(c *gin.Context) {
+	var req PlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+	if err := req.validate(); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, merchant.APIKeyObjKey)
+
+	plan := model.SubscriptionPlan{
+		MerchantAPIKeyID: apiKey.ID,
+		Name:             req.Name,
+		Description:      req.Description,
+		Amount:           req.Amount,
+		Interval:         req.Interval,
+		IntervalCount:    req.IntervalCount,
+		TrialDays:        req.TrialDays,
+		IsActive:         *req.IsActive,
+	}
+	if err := db.DB(c.Request.Context()).Create(&plan).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(plan))
+}
+
+// ListPlans 获取订阅计划列表
+// @Tags subscription
+// @Produce json
+// @Param id path uint64 true "API Key ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/merchant/api-keys/{id}/subscription-plans [get]
+func ListPlans(c *gin.Context) {
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, merchant.APIKeyObjKey)
+
+	var plans []model.SubscriptionPlan
+	if err := db.DB(c.Request.Context()).
+		Where("merchant_api_key_id = ?", apiKey.ID).
+		Order("created_at DESC").
+		Find(&plans).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(plans))
+}
+
+// UpdatePlan 更新订阅计划，金额和周期变更从下一次续费开始生效
+// @Tags subscription
+// @Accept json
+// @Produce json
+// @Param id path uint64 true "API Key ID"
+// @Param planId path uint64 true "Plan ID"
+// @Param request body PlanRequest true "订阅计划"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/merchant/api-keys/{id}/subscription-plans/{planId} [put]
+func UpdatePlan(c *gin.Context) {
+	var req PlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+	if err := req.validate(); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, merchant.APIKeyObjKey)
+
+	result := db.DB(c.Request.Context()).
+		Model(&model.SubscriptionPlan{}).
+		Where("id = ? AND merchant_api_key_id = ?", c.Param("planId"), apiKey.ID).
+		Select("name", "description", "amount", "interval", "interval_count", "trial_days", "is_active").
+		Updates(model.SubscriptionPlan{
+			Name:          req.Name,
+			Description:   req.Description,
+			Amount:        req.Amount,
+			Interval:      req.Interval,
+			IntervalCount: req.IntervalCount,
+			TrialDays:     req.TrialDays,
+			IsActive:      *req.IsActive,
+		})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(result.Error.Error()))
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, util.Err(PlanNotFound))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
+
+// DeletePlan 删除订阅计划，已有订阅在下次续费时自动取消
+// @Tags subscription
+// @Produce json
+// @Param id path uint64 true "API Key ID"
+// @Param planId path uint64 true "Plan ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/merchant/api-keys/{id}/subscription-plans/{planId} [delete]
+func DeletePlan(c *gin.Context) {
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, merchant.APIKeyObjKey)
+
+	result := db.DB(c.Request.Context()).
+		Where("id = ? AND merchant_api_key_id = ?", c.Param("planId"), apiKey.ID).
+		Delete(&model.SubscriptionPlan{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(result.Error.Error()))
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, util.Err(PlanNotFound))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
+
+// ListSubscriptionsRequest 查询订阅列表请求
+type ListSubscriptionsRequest struct {
+	Page     int    `json:"page" form:"page" binding:"min=1"`
+	PageSize int    `json:"page_size" form:"page_size" binding:"min=1,max=100"`
+	Status   string `json:"status" form:"status" binding:"omitempty,oneof=trialing active past_due paused cancelled expired"`
+}
+
+// ListSubscriptionsResponse 查询订阅列表响应
+type ListSubscriptionsResponse struct {
+	Total         int64                `json:"total"`
+	Page          int                  `json:"page"`
+	PageSize      int                  `json:"page_size"`
+	Subscriptions []model.Subscription `json:"subscriptions"`
+}
+
+// listSubscriptions 分页查询订阅，scope 限定查询范围
+func listSubscriptions(c *gin.Context, scope func(*gorm.DB) *gorm.DB) {
+	var req ListSubscriptionsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	baseQuery := scope(db.DB(c.Request.Context()).Model(&model.Subscription{}).
+		Select("subscriptions.*, subscription_plans.name as plan_name, users.username as username").
+		Joins("JOIN subscription_plans ON subscriptions.plan_id = subscription_plans.id").
+		Joins("JOIN users ON subscriptions.user_id = users.id"))
+	if req.Status != "" {
+		baseQuery = baseQuery.Where("subscriptions.status = ?", model.SubscriptionStatus(req.Status))
+	}
+
+	var total int64
+	if err := baseQuery.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	response := &ListSubscriptionsResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := baseQuery.Order("subscriptions.created_at DESC").Offset(offset).Limit(req.PageSize).Find(&response.Subscriptions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// respondSubscriptionAction 执行订阅状态变更并返回结果
+func respondSubscriptionAction(c *gin.Context, scope func(*gorm.DB) *gorm.DB, subscriptionID string, action subscriptionAction) {
+	sub, err := changeSubscriptionStatus(c.Request.Context(), scope, subscriptionID, action)
+	if err != nil {
+		switch err.Error() {
+		case SubscriptionNotFound:
+			c.JSON(http.StatusNotFound, util.Err(SubscriptionNotFound))
+		case SubscriptionStatusInvalid:
+			c.JSON(http.StatusBadRequest, util.Err(SubscriptionStatusInvalid))
+		default:
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(sub))
+}
+
+// merchantScope 限定为当前商户应用的订阅
+func merchantScope(c *gin.Context) func(*gorm.DB) *gorm.DB {
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, merchant.APIKeyObjKey)
+	return func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("subscriptions.merchant_api_key_id = ?", apiKey.ID)
+	}
+}
+
+// userScope 限定为当前用户的订阅
+func userScope(c *gin.Context) func(*gorm.DB) *gorm.DB {
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+	return func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("subscriptions.user_id = ?", user.ID)
+	}
+}
+
+// ListMerchantSubscriptions 查询商户应用的订阅列表
+// @Tags subscription
+// @Produce json
+// @Param id path uint64 true "API Key ID"
+// @Param request query ListSubscriptionsRequest true "查询参数"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/merchant/api-keys/{id}/subscriptions [get]
+func ListMerchantSubscriptions(c *gin.Context) {
+	listSubscriptions(c, merchantScope(c))
+}
+
+// MerchantCancelSubscription 商户取消订阅
+// @Tags subscription
+// @Produce json
+// @Param id path uint64 true "API Key ID"
+// @Param subscriptionId path uint64 true "Subscription ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/merchant/api-keys/{id}/subscriptions/{subscriptionId}/cancel [post]
+func MerchantCancelSubscription(c *gin.Context) {
+	respondSubscriptionAction(c, merchantScope(c), c.Param("subscriptionId"), actionCancel)
+}
+
+// MerchantPauseSubscription 商户暂停订阅
+// @Tags subscription
+// @Produce json
+// @Param id path uint64 true "API Key ID"
+// @Param subscriptionId path uint64 true "Subscription ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/merchant/api-keys/{id}/subscriptions/{subscriptionId}/pause [post]
+func MerchantPauseSubscription(c *gin.Context) {
+	respondSubscriptionAction(c, merchantScope(c), c.Param("subscriptionId"), actionPause)
+}
+
+// MerchantResumeSubscription 商户恢复订阅
+// @Tags subscription
+// @Produce json
+// @Param id path uint64 true "API Key ID"
+// @Param subscriptionId path uint64 true "Subscription ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/merchant/api-keys/{id}/subscriptions/{subscriptionId}/resume [post]
+func MerchantResumeSubscription(c *gin.Context) {
+	respondSubscriptionAction(c, merchantScope(c), c.Param("subscriptionId"), actionResume)
+}
+
+// PlanDetail 订阅计划公开信息
+type PlanDetail struct {
+	model.SubscriptionPlan
+	AppName string `json:"app_name"`
+}
+
+// GetPlan 查询订阅计划信息，用于用户订阅页面
+// @Tags subscription
+// @Produce json
+// @Param planId path uint64 true "Plan ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/subscription/plans/{planId} [get]
+func GetPlan(c *gin.Context) {
+	var plan PlanDetail
+	if err := db.DB(c.Request.Context()).
+		Model(&model.SubscriptionPlan{}).
+		Select("subscription_plans.*, merchant_api_keys.app_name").
+		Joins("JOIN merchant_api_keys ON merchant_api_keys.id = subscription_plans.merchant_api_key_id").
+		Where("subscription_plans.id = ? AND merchant_api_keys.deleted_at IS NULL", c.Param("planId")).
+		First(&plan).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(PlanNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(plan))
+}
+
+// SubscribeRequest 订阅请求
+type SubscribeRequest struct {
+	PlanID uint64 `json:"plan_id" binding:"required"`
+	PayKey string `json:"pay_key" binding:"required,max=6"`
+}
+
+// Subscribe 用户订阅计划，有试用期时试用结束后首次扣款，否则立即扣款
+// @Tags subscription
+// @Accept json
+// @Produce json
+// @Param request body SubscribeRequest true "订阅请求"
+// @Param Idempotency-Key header string false "幂等键，重复请求返回首次请求的响应"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/subscription/subscriptions [post]
+func Subscribe(c *gin.Context) {
+	var req SubscribeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	if subtle.ConstantTimeCompare([]byte(currentUser.PayKey), []byte(req.PayKey)) != 1 {
+		c.JSON(http.StatusBadRequest, util.Err(common.PayKeyIncorrect))
+		return
+	}
+
+	var sub model.Subscription
+	var order *model.Order
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			// 锁定计划，避免同一用户并发重复订阅
+			var plan model.SubscriptionPlan
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("id = ?", req.PlanID).
+				First(&plan).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New(PlanNotFound)
+				}
+				return err
+			}
+			if !plan.IsActive {
+				return errors.New(PlanInactive)
+			}
+
+			var apiKey model.MerchantAPIKey
+			if err := apiKey.GetByID(tx, plan.MerchantAPIKeyID); err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New(MerchantNotFound)
+				}
+				return err
+			}
+			if apiKey.UserID == currentUser.ID {
+				return errors.New(common.CannotPaySelf)
+			}
+
+			var existing int64
+			if err := tx.Model(&model.Subscription{}).
+				Where("plan_id = ? AND user_id = ? AND status IN ?", plan.ID, currentUser.ID, ongoingStatuses).
+				Count(&existing).Error; err != nil {
+				return err
+			}
+			if existing > 0 {
+				return errors.New(AlreadySubscribed)
+			}
+
+			now := time.Now()
+			sub = model.Subscription{
+				PlanID:             plan.ID,
+				MerchantAPIKeyID:   apiKey.ID,
+				ClientID:           apiKey.ClientID,
+				UserID:             currentUser.ID,
+				Status:             model.SubscriptionStatusTrialing,
+				CurrentPeriodStart: now,
+				CurrentPeriodEnd:   now.AddDate(0, 0, plan.TrialDays),
+			}
+			if plan.TrialDays == 0 {
+				sub.Status = model.SubscriptionStatusActive
+				sub.CurrentPeriodEnd = plan.NextPeriodEnd(now)
+			} else {
+				trialEndsAt := sub.CurrentPeriodEnd
+				sub.TrialEndsAt = &trialEndsAt
+			}
+			sub.NextBillingAt = sub.CurrentPeriodEnd
+			if err := tx.Create(&sub).Error; err != nil {
+				return err
+			}
+
+			if sub.Status == model.SubscriptionStatusTrialing {
+				return nil
+			}
+
+			// 无试用期，立即扣除首期费用
+			var err error
+			if order, err = chargeSubscription(tx, &sub, &plan, now); err != nil {
+				return err
+			}
+			sub.LastOrderID = order.ID
+			return tx.Model(&sub).UpdateColumn("last_order_id", order.ID).Error
+		},
+	); err != nil {
+		switch err.Error() {
+		case PlanNotFound, MerchantNotFound:
+			c.JSON(http.StatusNotFound, util.Err(err.Error()))
+		case PlanInactive, AlreadySubscribed, common.CannotPaySelf:
+			c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		default:
+			if isBillingFailure(err) {
+				c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+			} else {
+				c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+			}
+		}
+		return
+	}
+
+	if order != nil {
+		notifySubscriptionEvent(c.Request.Context(), &sub, EventRenewed, order)
+	}
+
+	c.JSON(http.StatusOK, util.OK(sub))
+}
+
+// ListMySubscriptions 查询当前用户的订阅列表
+// @Tags subscription
+// @Produce json
+// @Param request query ListSubscriptionsRequest true "查询参数"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/subscription/subscriptions [get]
+func ListMySubscriptions(c *gin.Context) {
+	listSubscriptions(c, userScope(c))
+}
+
+// CancelSubscription 用户取消订阅
+// @Tags subscription
+// @Produce json
+// @Param subscriptionId path uint64 true "Subscription ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/subscription/subscriptions/{subscriptionId}/cancel [post]
+func CancelSubscription(c *gin.Context) {
+	respondSubscriptionAction(c, userScope(c), c.Param("subscriptionId"), actionCancel)
+}
+
+// PauseSubscription 用户暂停订阅
+// @Tags subscription
+// @Produce json
+// @Param subscriptionId path uint64 true "Subscription ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/subscription/subscriptions/{subscriptionId}/pause [post]
+func PauseSubscription(c *gin.Context) {
+	respondSubscriptionAction(c, userScope(c), c.Param("subscriptionId"), actionPause)
+}
+
+// ResumeSubscription 用户恢复订阅
+// @Tags subscription
+// @Produce json
+// @Param subscriptionId path uint64 true "Subscription ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/subscription/subscriptions/{subscriptionId}/resume [post]
+func ResumeSubscription(c *gin.Context) {
+	respondSubscriptionAction(c, userScope(c), c.Param("subscriptionId"), actionResume)
+}
@@ -0,0 +1,202 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+package subscription
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/linux-do/pay/internal/config"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/logger"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/task"
+	"github.com/linux-do/pay/internal/task/schedule"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// HandleSubscriptionRenewDue 查询所有到期订阅并逐个下发续费任务
+func HandleSubscriptionRenewDue(ctx context.Context, t *asynq.Task) error {
+	pageSize := 200
+	lastID := uint64(0)
+	currentDelay := 0 * time.Second
+	now := time.Now()
+
+	for {
+		var subscriptions []model.Subscription
+		if err := db.DB(ctx).
+			Where("id > ? AND status IN ? AND next_billing_at <= ?", lastID, billableStatuses, now).
+			Order("id ASC").
+			Limit(pageSize).
+			Find(&subscriptions).Error; err != nil {
+			logger.ErrorF(ctx, "查询到期订阅失败: %v", err)
+			return err
+		}
+
+		// 没有更多订阅，退出循环
+		if len(subscriptions) == 0 {
+			break
+		}
+
+		for _, sub := range subscriptions {
+			currentDelay += time.Duration(config.Config.Schedule.SubscriptionRenewDispatchIntervalSeconds) * time.Second
+
+			payload, _ := json.Marshal(map[string]interface{}{
+				"subscription_id": sub.ID,
+			})
+
+			if _, errTask := schedule.AsynqClient.Enqueue(
+				asynq.NewTask(task.SubscriptionRenewSingleTask, payload),
+				asynq.ProcessIn(currentDelay),
+				asynq.MaxRetry(3),
+			); errTask != nil {
+				logger.ErrorF(ctx, "下发订阅[ID:%d]续费任务失败: %v", sub.ID, errTask)
+				return errTask
+			} else {
+				logger.InfoF(ctx, "下发订阅[ID:%d]续费任务成功", sub.ID)
+			}
+		}
+
+		lastID = subscriptions[len(subscriptions)-1].ID
+	}
+	return nil
+}
+
+// HandleSubscriptionRenewSingle 处理单个订阅的续费扣款
+// 扣款成功推进计费周期；余额或限额不足时进入 past_due，按配置间隔重试，超过宽限期后订阅过期
+func HandleSubscriptionRenewSingle(ctx context.Context, t *asynq.Task) error {
+	var payload struct {
+		SubscriptionID uint64 `json:"subscription_id"`
+	}
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("解析任务参数失败: %w", err)
+	}
+
+	retryHours, errGet := model.GetIntByKey(ctx, model.ConfigKeySubscriptionRetryIntervalHours)
+	if errGet != nil {
+		return errGet
+	}
+	graceDays, errGet := model.GetIntByKey(ctx, model.ConfigKeySubscriptionGracePeriodDays)
+	if errGet != nil {
+		return errGet
+	}
+
+	var sub model.Subscription
+	var order *model.Order
+	var event string
+	if err := db.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+			Where("id = ? AND status IN ? AND next_billing_at <= ?", payload.SubscriptionID, billableStatuses, now).
+			First(&sub).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				logger.InfoF(ctx, "订阅[ID:%d]无需续费或已被处理，跳过", payload.SubscriptionID)
+				return nil
+			}
+			return err
+		}
+
+		cancel := func() error {
+			sub.Status = model.SubscriptionStatusCancelled
+			sub.CancelledAt = &now
+			event = EventPrefix + string(sub.Status)
+			return tx.Model(&sub).Select("status", "cancelled_at").Updates(&sub).Error
+		}
+
+		// 计划被删除后不再续费
+		var plan model.SubscriptionPlan
+		if err := tx.Where("id = ?", sub.PlanID).First(&plan).Error; err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			return cancel()
+		}
+
+		// 正常续费紧接上一周期；逾期重试或暂停恢复后从当前时间重新开始计费周期
+		periodStart := sub.CurrentPeriodEnd
+		if sub.NextBillingAt.After(sub.CurrentPeriodEnd) {
+			periodStart = now
+		}
+
+		var errCharge error
+		if errCharge = tx.Transaction(func(chargeTx *gorm.DB) error {
+			var err error
+			order, err = chargeSubscription(chargeTx, &sub, &plan, periodStart)
+			return err
+		}); errCharge != nil && !isBillingFailure(errCharge) {
+			// 商户应用或用户已不存在，订阅无法继续
+			if errors.Is(errCharge, gorm.ErrRecordNotFound) {
+				return cancel()
+			}
+			return errCharge
+		}
+
+		if errCharge == nil {
+			sub.Status = model.SubscriptionStatusActive
+			sub.CurrentPeriodStart = periodStart
+			sub.CurrentPeriodEnd = plan.NextPeriodEnd(periodStart)
+			sub.NextBillingAt = sub.CurrentPeriodEnd
+			sub.RetryCount = 0
+			sub.GraceUntil = nil
+			sub.LastOrderID = order.ID
+			event = EventRenewed
+		} else {
+			order = nil
+			logger.InfoF(ctx, "订阅[ID:%d]续费失败: %v", sub.ID, errCharge)
+
+			if sub.Status != model.SubscriptionStatusPastDue {
+				graceUntil := now.AddDate(0, 0, graceDays)
+				sub.Status = model.SubscriptionStatusPastDue
+				sub.GraceUntil = &graceUntil
+				event = EventPrefix + string(sub.Status)
+			}
+			sub.RetryCount++
+			sub.NextBillingAt = now.Add(time.Duration(retryHours) * time.Hour)
+
+			// 超过宽限期仍未扣款成功，订阅过期
+			if sub.GraceUntil != nil && !sub.NextBillingAt.Before(*sub.GraceUntil) {
+				sub.Status = model.SubscriptionStatusExpired
+				event = EventPrefix + string(sub.Status)
+			}
+		}
+
+		return tx.Model(&sub).
+			Select("status", "current_period_start", "current_period_end", "next_billing_at", "retry_count", "grace_until", "last_order_id").
+			Updates(&sub).Error
+	}); err != nil {
+		logger.ErrorF(ctx, "处理订阅[ID:%d]续费失败: %v", payload.SubscriptionID, err)
+		return err
+	}
+
+	if event != "" {
+		notifySubscriptionEvent(ctx, &sub, event, order)
+	}
+
+	return nil
+}
@@ -0,0 +1,207 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+package subscription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/linux-do/pay/internal/apps/payment"
+	"github.com/linux-do/pay/internal/common"
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/logger"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/service"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// isBillingFailure 判断扣款失败是否由付款方余额或限额导致，此类失败进入重试而不是任务报错
+func isBillingFailure(err error) bool {
+	switch err.Error() {
+	case common.InsufficientBalance,
+		common.DailyLimitExceeded,
+		common.OrderAmountBelowMinimum,
+		common.OrderAmountAboveMaximum,
+		common.MerchantDailyReceiveLimit:
+		return true
+	default:
+		return false
+	}
+}
+
+// chargeSubscription 按订阅计划扣款，走与 PayMerchantOrder 相同的限额、手续费和余额逻辑
+func chargeSubscription(tx *gorm.DB, sub *model.Subscription, plan *model.SubscriptionPlan, periodStart time.Time) (*model.Order, error) {
+	var payerUser model.User
+	if err := payerUser.GetByID(tx, sub.UserID); err != nil {
+		return nil, fmt.Errorf("查询订阅用户失败: %w", err)
+	}
+
+	var apiKey model.MerchantAPIKey
+	if err := apiKey.GetByID(tx, sub.MerchantAPIKeyID); err != nil {
+		return nil, fmt.Errorf("查询商户应用失败: %w", err)
+	}
+
+	var merchantUser model.User
+	if err := merchantUser.GetByID(tx, apiKey.UserID); err != nil {
+		return nil, fmt.Errorf("查询商户用户失败: %w", err)
+	}
+
+	var payerPayConfig, merchantPayConfig model.UserPayConfig
+	if err := payerPayConfig.GetByPayScore(tx, payerUser.PayScore); err != nil {
+		return nil, err
+	}
+	if err := merchantPayConfig.GetByPayScore(tx, merchantUser.PayScore); err != nil {
+		return nil, err
+	}
+
+	// 检查每日限额
+	if err := service.CheckDailyLimit(tx, payerUser.ID, plan.Amount, payerPayConfig.DailyLimit); err != nil {
+		return nil, err
+	}
+
+	// 检查商户应用的金额范围和每日收款限额
+	if err := service.CheckMerchantLimits(tx, &apiKey, plan.Amount); err != nil {
+		return nil, err
+	}
+
+	// 计算手续费
+	_, merchantAmount, feePercent := service.CalculateFee(plan.Amount, merchantPayConfig.FeeRate)
+
+	now := time.Now()
+	order := model.Order{
+		OrderName:       plan.Name,
+		MerchantOrderNo: fmt.Sprintf("sub_%d_%s", sub.ID, periodStart.Format("20060102150405")),
+		ClientID:        apiKey.ClientID,
+		PayerUserID:     payerUser.ID,
+		PayeeUserID:     merchantUser.ID,
+		Amount:          plan.Amount,
+		Status:          model.OrderStatusSuccess,
+		Type:            model.OrderTypePayment,
+		Remark:          fmt.Sprintf("[系统]: 订阅自动续费，收取商家%d%%手续费", feePercent),
+		SubscriptionID:  sub.ID,
+		TradeTime:       now,
+		ExpiresAt:       now,
+	}
+	if err := tx.Create(&order).Error; err != nil {
+		return nil, err
+	}
+
+	// 扣减用户余额
+	if err := service.DeductUserBalance(tx, payerUser.ID, plan.Amount); err != nil {
+		return nil, err
+	}
+
+	// 增加商户余额和积分
+	merchantScoreIncrease := plan.Amount.Mul(merchantPayConfig.ScoreRate).Round(0).IntPart()
+	if err := service.AddMerchantBalance(tx, merchantUser.ID, merchantAmount, merchantScoreIncrease); err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+// notifySubscriptionEvent 通知商户订阅事件，order 为本次扣款订单（可为空）
+func notifySubscriptionEvent(ctx context.Context, sub *model.Subscription, event string, order *model.Order) {
+	params := map[string]string{
+		"subscription_id":    strconv.FormatUint(sub.ID, 10),
+		"plan_id":            strconv.FormatUint(sub.PlanID, 10),
+		"user_id":            strconv.FormatUint(sub.UserID, 10),
+		"status":             string(sub.Status),
+		"current_period_end": sub.CurrentPeriodEnd.Format("2006-01-02 15:04:05"),
+		"next_billing_at":    sub.NextBillingAt.Format("2006-01-02 15:04:05"),
+		"retry_count":        strconv.Itoa(sub.RetryCount),
+	}
+	if order != nil {
+		params["trade_no"] = strconv.FormatUint(order.ID, 10)
+		params["out_trade_no"] = order.MerchantOrderNo
+		params["money"] = order.Amount.Truncate(2).StringFixed(2)
+	}
+
+	if err := payment.EnqueueMerchantEvent(sub.ClientID, event, params); err != nil {
+		logger.ErrorF(ctx, "下发订阅[ID:%d]事件[%s]回调失败: %v", sub.ID, event, err)
+	}
+}
+
+// changeSubscriptionStatus 取消、暂停或恢复订阅，scope 用于限定操作方可见的订阅范围
+func changeSubscriptionStatus(ctx context.Context, scope func(*gorm.DB) *gorm.DB, subscriptionID string, action subscriptionAction) (*model.Subscription, error) {
+	var sub model.Subscription
+	if err := db.DB(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			if err := scope(tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"})).
+				Where("id = ?", subscriptionID).
+				First(&sub).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New(SubscriptionNotFound)
+				}
+				return err
+			}
+
+			allowed := false
+			for _, status := range actionFromStatuses[action] {
+				if sub.Status == status {
+					allowed = true
+					break
+				}
+			}
+			if !allowed {
+				return errors.New(SubscriptionStatusInvalid)
+			}
+
+			now := time.Now()
+			switch action {
+			case actionCancel:
+				sub.Status = model.SubscriptionStatusCancelled
+				sub.CancelledAt = &now
+			case actionPause:
+				sub.Status = model.SubscriptionStatusPaused
+				sub.PausedAt = &now
+			case actionResume:
+				// 试用期未结束时恢复为试用状态
+				sub.Status = model.SubscriptionStatusActive
+				if sub.TrialEndsAt != nil && sub.TrialEndsAt.After(now) {
+					sub.Status = model.SubscriptionStatusTrialing
+				}
+				sub.PausedAt = nil
+				// 暂停期间错过的续费在恢复后立即扣款
+				if sub.NextBillingAt.Before(now) {
+					sub.NextBillingAt = now
+				}
+			}
+
+			return tx.Model(&sub).
+				Select("status", "cancelled_at", "paused_at", "next_billing_at").
+				Updates(&sub).Error
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	notifySubscriptionEvent(ctx, &sub, EventPrefix+string(sub.Status), nil)
+
+	return &sub, nil
+}
@@ -65,3 +65,67 @@ func UpdatePayKey(c *gin.Context) {
 
 	c.JSON(http.StatusOK, util.OKNil())
 }
+
+// SearchUsersRequest 搜索用户请求
+type SearchUsersRequest struct {
+	Keyword string `json:"keyword" form:"keyword" binding:"required,max=64"`
+	Limit   int    `json:"limit" form:"limit" binding:"omitempty,min=1,max=20"`
+}
+
+// SearchUserItem 用户搜索结果，仅包含转账所需的公开信息
+type SearchUserItem struct {
+	ID        uint64 `json:"id"`
+	Username  string `json:"username"`
+	Nickname  string `json:"nickname"`
+	AvatarUrl string `json:"avatar_url"`
+}
+
+// SearchUsers 按用户名或昵称前缀搜索正常状态的用户，用于解析转账收款人
+// @Tags user
+// @Produce json
+// @Param request query SearchUsersRequest true "查询参数"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/user/search [get]
+func SearchUsers(c *gin.Context) {
+	var req SearchUsersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = searchDefaultLimit
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	rateLimit, errGet := model.GetIntByKey(c.Request.Context(), model.ConfigKeyUserSearchRateLimitPerMinute)
+	if errGet != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(errGet.Error()))
+		return
+	}
+	allowed, errLimit := allowSearch(c.Request.Context(), user.ID, rateLimit)
+	if errLimit != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(errLimit.Error()))
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusTooManyRequests, util.Err(SearchTooFrequent))
+		return
+	}
+
+	prefix := escapeLike(req.Keyword) + "%"
+	items := make([]SearchUserItem, 0, req.Limit)
+	if err := db.DB(c.Request.Context()).
+		Model(&model.User{}).
+		Select("id, username, nickname, avatar_url").
+		Where("is_active = ?", true).
+		Where("username ILIKE ? OR nickname ILIKE ?", prefix, prefix).
+		Order("LENGTH(username) ASC, id ASC").
+		Limit(req.Limit).
+		Scan(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(items))
+}
@@ -39,4 +39,18 @@ const (
 	DailyLimitExceeded          = "已超过每日限额"
 	PayKeyIncorrect             = "支付密钥错误"
 	CannotPaySelf               = "不能给自己付款"
+	OrderAmountBelowMinimum     = "订单金额低于商户单笔最低金额"
+	OrderAmountAboveMaximum     = "订单金额超过商户单笔最高金额"
+	MerchantDailyReceiveLimit   = "商户已超过每日收款限额"
+	SplitReverseInsufficient    = "分账收款人余额不足，无法扣回分账"
+	AllowanceNotGranted         = "未授权该应用免密支付"
+	AllowancePerPaymentExceeded = "超过免密支付单笔限额"
+	AllowanceDailyExceeded      = "超过免密支付每日限额"
+	CouponNotFound              = "优惠券不存在或已停用"
+	CouponNotStarted            = "优惠券尚未生效"
+	CouponExpired               = "优惠券已过期"
+	CouponUsageExceeded         = "优惠券已达到使用次数上限"
+	CouponPerUserExceeded       = "已达到该优惠券的个人使用上限"
+	CouponMinSpendNotMet        = "未达到优惠券最低消费金额"
+	CouponNotApplicable         = "优惠券不适用于该订单"
 )
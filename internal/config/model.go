@@ -130,6 +130,16 @@ type scheduleConfig struct {
 	UpdateUserGamificationScoresTaskCron         string `mapstructure:"update_user_gamification_scores_task_cron"`
 	DisputeAutoRefundDispatchIntervalSeconds     int    `mapstructure:"dispute_auto_refund_dispatch_interval_seconds"`
 	AutoRefundExpiredDisputesTaskCron            string `mapstructure:"auto_refund_expired_disputes_task_cron"`
+	SubscriptionRenewDispatchIntervalSeconds     int    `mapstructure:"subscription_renew_dispatch_interval_seconds"`
+	SubscriptionRenewDueTaskCron                 string `mapstructure:"subscription_renew_due_task_cron"`
+	EscrowAutoConfirmDispatchIntervalSeconds     int    `mapstructure:"escrow_auto_confirm_dispatch_interval_seconds"`
+	EscrowAutoConfirmDueTaskCron                 string `mapstructure:"escrow_auto_confirm_due_task_cron"`
+	RedPacketRefundDispatchIntervalSeconds       int    `mapstructure:"red_packet_refund_dispatch_interval_seconds"`
+	RedPacketRefundDueTaskCron                   string `mapstructure:"red_packet_refund_due_task_cron"`
+	ScheduledTransferDispatchIntervalSeconds     int    `mapstructure:"scheduled_transfer_dispatch_interval_seconds"`
+	ScheduledTransferDueTaskCron                 string `mapstructure:"scheduled_transfer_due_task_cron"`
+	AuthorizationAutoVoidDispatchIntervalSeconds int    `mapstructure:"authorization_auto_void_dispatch_interval_seconds"`
+	AuthorizationAutoVoidDueTaskCron             string `mapstructure:"authorization_auto_void_due_task_cron"`
 }
 
 // workerConfig 工作配置
@@ -33,6 +33,7 @@ import (
 	"github.com/linux-do/pay/internal/config"
 	"github.com/linux-do/pay/internal/db"
 	"github.com/shopspring/decimal"
+	"gorm.io/gorm/clause"
 )
 
 func Migrate() {
@@ -45,9 +46,32 @@ func Migrate() {
 		&model.UserPayConfig{},
 		&model.MerchantAPIKey{},
 		&model.MerchantPaymentLink{},
+		&model.MerchantPaymentLinkView{},
 		&model.Order{},
 		&model.SystemConfig{},
 		&model.Dispute{},
+		&model.SubscriptionPlan{},
+		&model.Subscription{},
+		&model.PaymentAllowance{},
+		&model.PayoutBatch{},
+		&model.PayoutItem{},
+		&model.Escrow{},
+		&model.MoneyRequest{},
+		&model.Notification{},
+		&model.RedPacket{},
+		&model.RedPacketClaim{},
+		&model.RedeemCodeBatch{},
+		&model.RedeemCode{},
+		&model.RedeemCodeUse{},
+		&model.ScheduledTransfer{},
+		&model.UserReceiveCode{},
+		&model.OrderSplit{},
+		&model.OrderAuthorization{},
+		&model.OrderItem{},
+		&model.MerchantProduct{},
+		&model.MerchantProductCode{},
+		&model.MerchantCoupon{},
+		&model.MerchantCouponRedemption{},
 	); err != nil {
 		log.Fatalf("[PostgreSQL] auto migrate failed: %v\n", err)
 	}
@@ -60,20 +84,10 @@ func Migrate() {
 	initUserPayConfigs()
 }
 
-// initSystemConfigs 初始化系统配置数据
+// initSystemConfigs 初始化系统配置数据，已存在的配置不会被覆盖
 func initSystemConfigs() {
 	tx := db.DB(context.Background())
 
-	var count int64
-	if err := tx.Model(&model.SystemConfig{}).Count(&count).Error; err != nil {
-		log.Printf("[PostgreSQL] failed to check system_config table: %v\n", err)
-		return
-	}
-
-	if count > 0 {
-		return
-	}
-
 	defaultConfigs := []model.SystemConfig{
 		{
 			Key:         model.ConfigKeyMerchantOrderExpireMinutes,
@@ -90,12 +104,83 @@ func initSystemConfigs() {
 			Value:       "168",
 			Description: "商家争议时间窗口（小时）",
 		},
+		{
+			Key:         model.ConfigKeyMerchantSignTimestampSkewSeconds,
+			Value:       "300",
+			Description: "商户签名请求时间戳允许偏差（秒）",
+		},
+		{
+			Key:         model.ConfigKeyMerchantOrderMinExpireMinutes,
+			Value:       "1",
+			Description: "商户自定义订单过期时间下限（分钟）",
+		},
+		{
+			Key:         model.ConfigKeyMerchantOrderMaxExpireMinutes,
+			Value:       "43200",
+			Description: "商户自定义订单过期时间上限（分钟）",
+		},
+		{
+			Key:         model.ConfigKeySubscriptionRetryIntervalHours,
+			Value:       "24",
+			Description: "订阅续费失败重试间隔（小时）",
+		},
+		{
+			Key:         model.ConfigKeySubscriptionGracePeriodDays,
+			Value:       "3",
+			Description: "订阅续费失败宽限期（天）",
+		},
+		{
+			Key:         model.ConfigKeyPayoutMaxBatchSize,
+			Value:       "100",
+			Description: "商户单批付款最大笔数",
+		},
+		{
+			Key:         model.ConfigKeyEscrowAutoConfirmDays,
+			Value:       "7",
+			Description: "担保交易发货后自动确认收货时间（天）",
+		},
+		{
+			Key:         model.ConfigKeyRedPacketExpireHours,
+			Value:       "24",
+			Description: "红包过期时间（小时）",
+		},
+		{
+			Key:         model.ConfigKeyRedPacketMaxCount,
+			Value:       "200",
+			Description: "单个红包最大个数",
+		},
+		{
+			Key:         model.ConfigKeyRedeemMaxFailuresPerHour,
+			Value:       "10",
+			Description: "每小时兑换码输入错误次数上限",
+		},
+		{
+			Key:         model.ConfigKeyScheduledTransferMaxActive,
+			Value:       "20",
+			Description: "每个用户可同时生效的定时转账数量上限",
+		},
+		{
+			Key:         model.ConfigKeyScheduledTransferMaxFailures,
+			Value:       "3",
+			Description: "周期转账连续失败次数上限",
+		},
+		{
+			Key:         model.ConfigKeyUserSearchRateLimitPerMinute,
+			Value:       "30",
+			Description: "每个用户每分钟搜索用户次数上限",
+		},
+		{
+			Key:         model.ConfigKeyOrderAuthorizationVoidHours,
+			Value:       "168",
+			Description: "预授权订单未确认扣款自动撤销时间（小时）",
+		},
 	}
 
-	if err := tx.Create(&defaultConfigs).Error; err != nil {
-		log.Printf("[PostgreSQL] failed to create default system configs: %v\n", err)
-	} else {
-		log.Printf("[PostgreSQL] initialized %d default system configs\n", len(defaultConfigs))
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&defaultConfigs)
+	if result.Error != nil {
+		log.Printf("[PostgreSQL] failed to create default system configs: %v\n", result.Error)
+	} else if result.RowsAffected > 0 {
+		log.Printf("[PostgreSQL] initialized %d default system configs\n", result.RowsAffected)
 	}
 }
 
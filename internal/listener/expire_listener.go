@@ -35,6 +35,7 @@ import (
 	"github.com/linux-do/pay/internal/db"
 	"github.com/linux-do/pay/internal/logger"
 	"github.com/linux-do/pay/internal/model"
+	"github.com/linux-do/pay/internal/service"
 )
 
 // StartExpireListener 启动过期监听器
@@ -103,5 +104,12 @@ func handleExpiredKey(ctx context.Context, expiredKey string) {
 		logger.ErrorF(ctx, "更新订单状态为过期失败: order_id=%d, error=%v", orderID, result.Error)
 	} else if result.RowsAffected > 0 {
 		logger.InfoF(ctx, "订单已过期: order_id=%d", orderID)
+
+		var order model.Order
+		if err := db.DB(ctx).Where("id = ?", orderID).First(&order).Error; err != nil {
+			logger.ErrorF(ctx, "查询过期订单失败: order_id=%d, error=%v", orderID, err)
+			return
+		}
+		service.PublishOrderEvent(ctx, &order)
 	}
 }
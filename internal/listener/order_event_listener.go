@@ -0,0 +1,129 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package listener
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/logger"
+	"github.com/linux-do/pay/internal/service"
+)
+
+// orderEventSubscriber 本实例上的订单事件订阅者，OrderID 和 ClientID 为过滤条件
+type orderEventSubscriber struct {
+	orderID  uint64
+	clientID string
+	ch       chan service.OrderEvent
+}
+
+var (
+	orderEventMu          sync.RWMutex
+	orderEventSubscribers = make(map[*orderEventSubscriber]struct{})
+)
+
+// StartOrderEventListener 启动订单事件监听器，将 Redis 广播的事件分发给本实例的订阅者
+func StartOrderEventListener(ctx context.Context) error {
+	if db.Redis == nil {
+		return fmt.Errorf("redis client is not initialized")
+	}
+
+	pubSub := db.Redis.Subscribe(ctx, service.OrderEventChannel)
+	if _, err := pubSub.Receive(ctx); err != nil {
+		_ = pubSub.Close()
+		return err
+	}
+
+	go func() {
+		defer pubSub.Close()
+		log.Printf("[Order Event Listener] 订单事件监听器已启动，监听频道: %s", service.OrderEventChannel)
+
+		for {
+			msg, err := pubSub.ReceiveMessage(ctx)
+			if err != nil {
+				if errors.Is(err, context.Canceled) {
+					log.Println("[Order Event Listener] 监听器已停止")
+					return
+				}
+				logger.ErrorF(ctx, "接收订单事件失败: %v", err)
+				continue
+			}
+
+			event, errParse := service.ParseOrderEvent(msg.Payload)
+			if errParse != nil {
+				logger.ErrorF(ctx, "解析订单事件失败: payload=%s, error=%v", msg.Payload, errParse)
+				continue
+			}
+
+			dispatchOrderEvent(event)
+		}
+	}()
+
+	return nil
+}
+
+// SubscribeOrderEvents 订阅订单事件，orderID 非 0 时只接收该订单的事件，clientID 非空时只接收该商户应用的事件
+// 返回的取消函数必须调用以释放订阅
+func SubscribeOrderEvents(orderID uint64, clientID string) (<-chan service.OrderEvent, func()) {
+	subscriber := &orderEventSubscriber{
+		orderID:  orderID,
+		clientID: clientID,
+		ch:       make(chan service.OrderEvent, 16),
+	}
+
+	orderEventMu.Lock()
+	orderEventSubscribers[subscriber] = struct{}{}
+	orderEventMu.Unlock()
+
+	return subscriber.ch, func() {
+		orderEventMu.Lock()
+		delete(orderEventSubscribers, subscriber)
+		orderEventMu.Unlock()
+	}
+}
+
+// dispatchOrderEvent 将事件分发给匹配的订阅者，订阅者消费过慢时丢弃事件，避免阻塞监听
+func dispatchOrderEvent(event service.OrderEvent) {
+	orderEventMu.RLock()
+	defer orderEventMu.RUnlock()
+
+	for subscriber := range orderEventSubscribers {
+		if subscriber.orderID != 0 && subscriber.orderID != event.OrderID {
+			continue
+		}
+		if subscriber.clientID != "" && subscriber.clientID != event.ClientID {
+			continue
+		}
+
+		select {
+		case subscriber.ch <- event:
+		default:
+		}
+	}
+}
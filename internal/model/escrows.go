@@ -0,0 +1,74 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type EscrowStatus string
+
+const (
+	EscrowStatusHeld      EscrowStatus = "held"      // 买家已付款，资金托管中，待卖家发货
+	EscrowStatusShipped   EscrowStatus = "shipped"   // 卖家已发货，待买家确认收货
+	EscrowStatusCompleted EscrowStatus = "completed" // 已确认收货，资金已放款给卖家
+	EscrowStatusDisputing EscrowStatus = "disputing" // 争议中，等待管理员仲裁
+	EscrowStatusRefunded  EscrowStatus = "refunded"  // 资金已退回买家
+	EscrowStatusCancelled EscrowStatus = "cancelled" // 卖家发货前取消，资金已退回买家
+)
+
+type EscrowArbitrationResult string
+
+const (
+	EscrowArbitrationRelease EscrowArbitrationResult = "release" // 放款给卖家
+	EscrowArbitrationRefund  EscrowArbitrationResult = "refund"  // 退款给买家
+)
+
+// Escrow 用户间担保交易，关联一笔 escrow 类型订单，资金托管在买家的 EscrowBalance 中
+type Escrow struct {
+	ID                 uint64                  `json:"id" gorm:"primaryKey;autoIncrement"`
+	OrderID            uint64                  `json:"order_id" gorm:"uniqueIndex;not null"`
+	BuyerUserID        uint64                  `json:"buyer_user_id" gorm:"not null;index:idx_escrows_buyer_created,priority:1"`
+	SellerUserID       uint64                  `json:"seller_user_id" gorm:"not null;index:idx_escrows_seller_created,priority:1"`
+	Title              string                  `json:"title" gorm:"size:64;not null"`
+	Amount             decimal.Decimal         `json:"amount" gorm:"type:numeric(20,2);not null"`
+	Status             EscrowStatus            `json:"status" gorm:"type:varchar(20);not null;index:idx_escrows_status_auto_confirm,priority:1"`
+	ShippingInfo       string                  `json:"shipping_info" gorm:"size:255"`
+	ShippedAt          *time.Time              `json:"shipped_at"`
+	AutoConfirmAt      *time.Time              `json:"auto_confirm_at" gorm:"index:idx_escrows_status_auto_confirm,priority:2"`
+	CompletedAt        *time.Time              `json:"completed_at"`
+	DisputeInitiatorID uint64                  `json:"dispute_initiator_id"`
+	DisputeReason      string                  `json:"dispute_reason" gorm:"size:500"`
+	DisputedAt         *time.Time              `json:"disputed_at"`
+	ArbitratorUserID   *uint64                 `json:"arbitrator_user_id"`
+	ArbitrationResult  EscrowArbitrationResult `json:"arbitration_result" gorm:"type:varchar(20)"`
+	ArbitrationNote    string                  `json:"arbitration_note" gorm:"size:500"`
+	BuyerUsername      string                  `json:"buyer_username" gorm:"->"`
+	SellerUsername     string                  `json:"seller_username" gorm:"->"`
+	CreatedAt          time.Time               `json:"created_at" gorm:"autoCreateTime;index:idx_escrows_buyer_created,priority:2;index:idx_escrows_seller_created,priority:2"`
+	UpdatedAt          time.Time               `json:"updated_at" gorm:"autoUpdateTime"`
+}
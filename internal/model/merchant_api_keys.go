@@ -25,24 +25,37 @@
 package model
 
 import (
+	"slices"
 	"time"
 
+	"github.com/linux-do/pay/internal/util"
+	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 )
 
+// API Key 权限范围
+const (
+	APIKeyScopePayoutWrite = "payout:write" // 从商户余额向用户付款
+)
+
 type MerchantAPIKey struct {
-	ID             uint64         `json:"id" gorm:"primaryKey;autoIncrement"`
-	UserID         uint64         `json:"user_id" gorm:"not null;index:idx_merchant_api_keys_user_created,priority:1"`
-	ClientID       string         `json:"client_id" gorm:"size:64;uniqueIndex;index:idx_client_credentials,priority:2;not null"`
-	ClientSecret   string         `json:"client_secret" gorm:"size:64;index:idx_client_credentials,priority:1;not null"`
-	AppName        string         `json:"app_name" gorm:"size:20;not null"`
-	AppHomepageURL string         `json:"app_homepage_url" gorm:"size:100;not null"`
-	AppDescription string         `json:"app_description" gorm:"size:100"`
-	RedirectURI    string         `json:"redirect_uri" gorm:"size:100"`
-	NotifyURL      string         `json:"notify_url" gorm:"size:100;not null"`
-	CreatedAt      time.Time      `json:"created_at" gorm:"autoCreateTime;index:idx_merchant_api_keys_user_created,priority:2"`
-	UpdatedAt      time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
-	DeletedAt      gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+	ID                uint64           `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID            uint64           `json:"user_id" gorm:"not null;index:idx_merchant_api_keys_user_created,priority:1"`
+	ClientID          string           `json:"client_id" gorm:"size:64;uniqueIndex;index:idx_client_credentials,priority:2;not null"`
+	ClientSecret      string           `json:"client_secret" gorm:"size:64;index:idx_client_credentials,priority:1;not null"`
+	AppName           string           `json:"app_name" gorm:"size:20;not null"`
+	AppHomepageURL    string           `json:"app_homepage_url" gorm:"size:100;not null"`
+	AppDescription    string           `json:"app_description" gorm:"size:100"`
+	RedirectURI       string           `json:"redirect_uri" gorm:"size:100"`
+	NotifyURL         string           `json:"notify_url" gorm:"size:100;not null"`
+	RequireNonce      bool             `json:"require_nonce" gorm:"not null;default:false"`
+	MinOrderAmount    *decimal.Decimal `json:"min_order_amount" gorm:"type:numeric(20,2)"`
+	MaxOrderAmount    *decimal.Decimal `json:"max_order_amount" gorm:"type:numeric(20,2)"`
+	DailyReceiveLimit *decimal.Decimal `json:"daily_receive_limit" gorm:"type:numeric(20,2)"`
+	Scopes            util.StringArray `json:"scopes" gorm:"type:jsonb"`
+	CreatedAt         time.Time        `json:"created_at" gorm:"autoCreateTime;index:idx_merchant_api_keys_user_created,priority:2"`
+	UpdatedAt         time.Time        `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt         gorm.DeletedAt   `json:"deleted_at" gorm:"index"`
 }
 
 // GetByID 通过 ID 查询商户 API Key
@@ -54,3 +67,8 @@ func (m *MerchantAPIKey) GetByID(tx *gorm.DB, id uint64) error {
 func (m *MerchantAPIKey) GetByClientID(tx *gorm.DB, clientID string) error {
 	return tx.Where("client_id = ?", clientID).First(m).Error
 }
+
+// HasScope 判断 API Key 是否拥有指定权限
+func (m *MerchantAPIKey) HasScope(scope string) bool {
+	return slices.Contains(m.Scopes, scope)
+}
@@ -0,0 +1,81 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package model
+
+import (
+	"time"
+
+	"github.com/linux-do/pay/internal/util"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type CouponDiscountType string
+
+const (
+	CouponDiscountTypePercentage CouponDiscountType = "percentage" // 按订单金额百分比减免
+	CouponDiscountTypeFixed      CouponDiscountType = "fixed"      // 固定金额减免
+)
+
+type CouponScope string
+
+const (
+	CouponScopeAll   CouponScope = "all"   // 适用于商户应用的所有订单
+	CouponScopeLinks CouponScope = "links" // 仅适用于指定的支付链接
+)
+
+// MerchantCoupon 商户优惠券，同一商户应用下优惠码唯一，MaxUses 与 PerUserLimit 为空表示不限制
+type MerchantCoupon struct {
+	ID               uint64             `json:"id" gorm:"primaryKey;autoIncrement"`
+	MerchantAPIKeyID uint64             `json:"merchant_api_key_id" gorm:"not null;uniqueIndex:idx_merchant_coupons_key_code,priority:1,where:deleted_at IS NULL"`
+	Code             string             `json:"code" gorm:"size:32;not null;uniqueIndex:idx_merchant_coupons_key_code,priority:2,where:deleted_at IS NULL"`
+	DiscountType     CouponDiscountType `json:"discount_type" gorm:"type:varchar(20);not null"`
+	DiscountValue    decimal.Decimal    `json:"discount_value" gorm:"type:numeric(20,2);not null"`
+	MinSpend         *decimal.Decimal   `json:"min_spend" gorm:"type:numeric(20,2)"`
+	Scope            CouponScope        `json:"scope" gorm:"type:varchar(10);not null;default:'all'"`
+	PaymentLinkIDs   util.Uint64Array   `json:"payment_link_ids" gorm:"type:jsonb" swaggertype:"array,integer"`
+	MaxUses          *int64             `json:"max_uses"`
+	PerUserLimit     *int64             `json:"per_user_limit"`
+	UsedCount        int64              `json:"used_count" gorm:"not null;default:0"`
+	TotalDiscount    decimal.Decimal    `json:"total_discount" gorm:"type:numeric(20,2);not null;default:0"`
+	StartsAt         *time.Time         `json:"starts_at"`
+	EndsAt           *time.Time         `json:"ends_at"`
+	IsActive         bool               `json:"is_active" gorm:"not null;default:true"`
+	CreatedAt        time.Time          `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt        time.Time          `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt        gorm.DeletedAt     `json:"deleted_at" gorm:"index"`
+}
+
+// MerchantCouponRedemption 优惠券核销记录，每个订单最多使用一张优惠券
+type MerchantCouponRedemption struct {
+	ID             uint64          `json:"id" gorm:"primaryKey;autoIncrement"`
+	CouponID       uint64          `json:"coupon_id" gorm:"not null;index:idx_merchant_coupon_redemptions_coupon_user,priority:1"`
+	UserID         uint64          `json:"user_id" gorm:"not null;index:idx_merchant_coupon_redemptions_coupon_user,priority:2"`
+	OrderID        uint64          `json:"order_id" gorm:"not null;uniqueIndex"`
+	OriginalAmount decimal.Decimal `json:"original_amount" gorm:"type:numeric(20,2);not null"`
+	DiscountAmount decimal.Decimal `json:"discount_amount" gorm:"type:numeric(20,2);not null"`
+	Username       string          `json:"username" gorm:"->"`
+	CreatedAt      time.Time       `json:"created_at" gorm:"autoCreateTime;index"`
+}
@@ -0,0 +1,37 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package model
+
+import (
+	"time"
+)
+
+// MerchantPaymentLinkView 支付链接每日浏览量，同一用户每天只计一次
+type MerchantPaymentLinkView struct {
+	ID                    uint64    `json:"id" gorm:"primaryKey;autoIncrement"`
+	MerchantPaymentLinkID uint64    `json:"merchant_payment_link_id" gorm:"not null;uniqueIndex:idx_link_views_link_date,priority:1"`
+	Date                  time.Time `json:"date" gorm:"type:date;not null;uniqueIndex:idx_link_views_link_date,priority:2"`
+	Views                 int64     `json:"views" gorm:"not null;default:0"`
+}
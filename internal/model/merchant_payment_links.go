@@ -27,19 +27,40 @@ package model
 import (
 	"time"
 
+	"github.com/linux-do/pay/internal/util"
 	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 )
 
+type PaymentLinkAmountMode string
+
+const (
+	PaymentLinkAmountModeFixed PaymentLinkAmountMode = "fixed"
+	PaymentLinkAmountModeOpen  PaymentLinkAmountMode = "open"
+	PaymentLinkAmountModeTiers PaymentLinkAmountMode = "tiers"
+)
+
 type MerchantPaymentLink struct {
-	ID               uint64          `json:"id" gorm:"primaryKey;autoIncrement"`
-	MerchantAPIKeyID uint64          `json:"merchant_api_key_id" gorm:"not null;index"`
-	Token            string          `json:"token" gorm:"size:64;uniqueIndex;not null"`
-	Amount           decimal.Decimal `json:"amount" gorm:"type:numeric(20,2);not null"`
-	ProductName      string          `json:"product_name" gorm:"size:30;not null"`
-	Remark           string          `json:"remark" gorm:"size:100"`
-	CreatedAt        time.Time       `json:"created_at" gorm:"autoCreateTime;index"`
-	DeletedAt        gorm.DeletedAt  `json:"deleted_at" gorm:"index"`
+	ID               uint64                `json:"id" gorm:"primaryKey;autoIncrement"`
+	MerchantAPIKeyID uint64                `json:"merchant_api_key_id" gorm:"not null;index"`
+	Token            string                `json:"token" gorm:"size:64;uniqueIndex;not null"`
+	AmountMode       PaymentLinkAmountMode `json:"amount_mode" gorm:"type:varchar(10);not null;default:'fixed'"`
+	Amount           decimal.Decimal       `json:"amount" gorm:"type:numeric(20,2);not null"`
+	MinAmount        *decimal.Decimal      `json:"min_amount" gorm:"type:numeric(20,2)"`
+	MaxAmount        *decimal.Decimal      `json:"max_amount" gorm:"type:numeric(20,2)"`
+	AmountTiers      util.DecimalArray     `json:"amount_tiers" gorm:"type:jsonb" swaggertype:"array,number"`
+	GoalAmount       *decimal.Decimal      `json:"goal_amount" gorm:"type:numeric(20,2)"`
+	ProductName      string                `json:"product_name" gorm:"size:30;not null"`
+	Remark           string                `json:"remark" gorm:"size:100"`
+	IsActive         bool                  `json:"is_active" gorm:"not null;default:true"`
+	ExpiresAt        *time.Time            `json:"expires_at"`
+	MaxUses          *int64                `json:"max_uses"`
+	PerUserLimit     *int64                `json:"per_user_limit"`
+	Stock            *int64                `json:"stock" gorm:"check:stock >= 0"`
+	UsedCount        int64                 `json:"used_count" gorm:"not null;default:0"`
+	CreatedAt        time.Time             `json:"created_at" gorm:"autoCreateTime;index"`
+	UpdatedAt        time.Time             `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt        gorm.DeletedAt        `json:"deleted_at" gorm:"index"`
 }
 
 // GetByToken 通过 Token 查询支付链接
@@ -0,0 +1,59 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// MerchantProduct 商户应用下的商品，Stock 为空表示不限库存；虚拟商品的库存等于未发货的发货内容数量
+type MerchantProduct struct {
+	ID               uint64          `json:"id" gorm:"primaryKey;autoIncrement"`
+	MerchantAPIKeyID uint64          `json:"merchant_api_key_id" gorm:"not null;index"`
+	Name             string          `json:"name" gorm:"size:64;not null"`
+	Description      string          `json:"description" gorm:"size:500"`
+	Price            decimal.Decimal `json:"price" gorm:"type:numeric(20,2);not null"`
+	Stock            *int64          `json:"stock" gorm:"check:stock >= 0"`
+	IsDigital        bool            `json:"is_digital" gorm:"not null;default:false"`
+	IsActive         bool            `json:"is_active" gorm:"not null;default:true"`
+	SoldCount        int64           `json:"sold_count" gorm:"not null;default:0"`
+	CreatedAt        time.Time       `json:"created_at" gorm:"autoCreateTime;index"`
+	UpdatedAt        time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt        gorm.DeletedAt  `json:"deleted_at" gorm:"index"`
+}
+
+// MerchantProductCode 虚拟商品预先上传的发货内容（卡密或文本），售出后记录所属订单与买家
+type MerchantProductCode struct {
+	ID          uint64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	ProductID   uint64     `json:"product_id" gorm:"not null;index:idx_merchant_product_codes_product_order,priority:1"`
+	Content     string     `json:"content" gorm:"size:1000;not null"`
+	OrderID     *uint64    `json:"order_id" gorm:"index:idx_merchant_product_codes_product_order,priority:2;index"`
+	BuyerUserID *uint64    `json:"buyer_user_id"`
+	DeliveredAt *time.Time `json:"delivered_at"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
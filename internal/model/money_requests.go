@@ -0,0 +1,74 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type MoneyRequestStatus string
+
+const (
+	MoneyRequestStatusPending   MoneyRequestStatus = "pending"
+	MoneyRequestStatusPaid      MoneyRequestStatus = "paid"
+	MoneyRequestStatusDeclined  MoneyRequestStatus = "declined"
+	MoneyRequestStatusCancelled MoneyRequestStatus = "cancelled"
+	MoneyRequestStatusExpired   MoneyRequestStatus = "expired"
+)
+
+// MoneyRequest 用户收款请求，PayerUserID 为空时为可分享的收款链接，任何用户均可支付
+type MoneyRequest struct {
+	ID                uint64             `json:"id" gorm:"primaryKey;autoIncrement"`
+	Token             string             `json:"token" gorm:"size:64;uniqueIndex;not null"`
+	RequesterUserID   uint64             `json:"requester_user_id" gorm:"not null;index:idx_money_requests_requester_created,priority:1"`
+	PayerUserID       *uint64            `json:"payer_user_id" gorm:"index:idx_money_requests_payer_created,priority:1"`
+	Amount            decimal.Decimal    `json:"amount" gorm:"type:numeric(20,2);not null"`
+	Note              string             `json:"note" gorm:"size:100"`
+	Status            MoneyRequestStatus `json:"status" gorm:"type:varchar(20);not null;index"`
+	ExpiresAt         *time.Time         `json:"expires_at"`
+	OrderID           uint64             `json:"order_id"`
+	PaidByUserID      uint64             `json:"paid_by_user_id"`
+	ClosedAt          *time.Time         `json:"closed_at"`
+	RequesterUsername string             `json:"requester_username" gorm:"->"`
+	PayerUsername     string             `json:"payer_username" gorm:"->"`
+	CreatedAt         time.Time          `json:"created_at" gorm:"autoCreateTime;index:idx_money_requests_requester_created,priority:2;index:idx_money_requests_payer_created,priority:2"`
+	UpdatedAt         time.Time          `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// IsExpired 判断待支付的收款请求是否已过期
+func (r *MoneyRequest) IsExpired() bool {
+	return r.Status == MoneyRequestStatusPending && r.ExpiresAt != nil && !r.ExpiresAt.After(time.Now())
+}
+
+// AfterFind 待支付但已过期的收款请求按 expired 返回
+func (r *MoneyRequest) AfterFind(*gorm.DB) error {
+	if r.IsExpired() {
+		r.Status = MoneyRequestStatusExpired
+	}
+	return nil
+}
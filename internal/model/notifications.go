@@ -0,0 +1,48 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package model
+
+import (
+	"time"
+)
+
+type NotificationType string
+
+const (
+	NotificationTypeMoneyRequest      NotificationType = "money_request"
+	NotificationTypeScheduledTransfer NotificationType = "scheduled_transfer"
+)
+
+// Notification 站内通知
+type Notification struct {
+	ID        uint64           `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID    uint64           `json:"user_id" gorm:"not null;index:idx_notifications_user_read_created,priority:1"`
+	Type      NotificationType `json:"type" gorm:"type:varchar(32);not null"`
+	Title     string           `json:"title" gorm:"size:64;not null"`
+	Content   string           `json:"content" gorm:"size:255"`
+	RelatedID uint64           `json:"related_id"`
+	ReadAt    *time.Time       `json:"read_at" gorm:"index:idx_notifications_user_read_created,priority:2"`
+	CreatedAt time.Time        `json:"created_at" gorm:"autoCreateTime;index:idx_notifications_user_read_created,priority:3"`
+}
@@ -0,0 +1,55 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type OrderAuthorizationStatus string
+
+const (
+	OrderAuthorizationStatusAuthorized OrderAuthorizationStatus = "authorized" // 已冻结付款人余额，等待商户确认扣款
+	OrderAuthorizationStatusCaptured   OrderAuthorizationStatus = "captured"   // 商户已确认扣款，未扣款部分已解冻
+	OrderAuthorizationStatusVoided     OrderAuthorizationStatus = "voided"     // 商户撤销或超时自动撤销，冻结金额已解冻
+)
+
+// OrderAuthorization 预授权订单的冻结记录，冻结金额保存在付款人的 HeldBalance 中，到达 VoidAt 仍未扣款时自动撤销
+type OrderAuthorization struct {
+	ID             uint64                   `json:"id" gorm:"primaryKey;autoIncrement"`
+	OrderID        uint64                   `json:"order_id" gorm:"not null;uniqueIndex"`
+	ClientID       string                   `json:"client_id" gorm:"size:64;not null;index"`
+	PayerUserID    uint64                   `json:"payer_user_id" gorm:"not null;index"`
+	HoldAmount     decimal.Decimal          `json:"hold_amount" gorm:"type:numeric(20,2);not null"`
+	CapturedAmount decimal.Decimal          `json:"captured_amount" gorm:"type:numeric(20,2);not null;default:0"`
+	Status         OrderAuthorizationStatus `json:"status" gorm:"type:varchar(20);not null;index:idx_order_authorizations_status_void_at,priority:1"`
+	VoidAt         time.Time                `json:"void_at" gorm:"not null;index:idx_order_authorizations_status_void_at,priority:2"`
+	CapturedAt     *time.Time               `json:"captured_at"`
+	VoidedAt       *time.Time               `json:"voided_at"`
+	CreatedAt      time.Time                `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time                `json:"updated_at" gorm:"autoUpdateTime"`
+}
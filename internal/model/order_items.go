@@ -0,0 +1,47 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// OrderItem 订单商品明细，LineNo 为订单内从 1 开始的行号，用于收据展示、按行退款与统计分析
+// 按行退款时以 ID 指定商品行，RefundedQuantity 与 RefundedAmount 记录该行累计已退数量和按比例分摊优惠后的已退实付金额
+type OrderItem struct {
+	ID               uint64          `json:"id" gorm:"primaryKey;autoIncrement"`
+	OrderID          uint64          `json:"order_id" gorm:"not null;uniqueIndex:idx_order_items_order_line,priority:1"`
+	LineNo           int             `json:"line_no" gorm:"not null;uniqueIndex:idx_order_items_order_line,priority:2"`
+	Name             string          `json:"name" gorm:"size:64;not null"`
+	SKU              string          `json:"sku" gorm:"size:64;index"`
+	UnitPrice        decimal.Decimal `json:"unit_price" gorm:"type:numeric(20,2);not null"`
+	Quantity         int             `json:"quantity" gorm:"not null"`
+	Amount           decimal.Decimal `json:"amount" gorm:"type:numeric(20,2);not null"`
+	RefundedQuantity int             `json:"refunded_quantity" gorm:"not null;default:0"`
+	RefundedAmount   decimal.Decimal `json:"refunded_amount" gorm:"type:numeric(20,2);not null;default:0"`
+	CreatedAt        time.Time       `json:"created_at" gorm:"autoCreateTime"`
+}
@@ -0,0 +1,54 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type OrderSplitStatus string
+
+const (
+	OrderSplitStatusPending  OrderSplitStatus = "pending"  // 订单待支付
+	OrderSplitStatusSettled  OrderSplitStatus = "settled"  // 订单支付成功，分账金额已到账
+	OrderSplitStatusRefunded OrderSplitStatus = "refunded" // 订单退款，分账金额已全部扣回
+)
+
+// OrderSplit 订单分账，Amount 为创建订单时确定的分账份额，支付成功后按商户费率扣除手续费再转入分账收款人
+// ReversedAmount 为订单退款时按比例累计扣回的分账份额，全部扣回后状态变为 refunded
+type OrderSplit struct {
+	ID              uint64           `json:"id" gorm:"primaryKey;autoIncrement"`
+	OrderID         uint64           `json:"order_id" gorm:"not null;uniqueIndex:idx_order_splits_order_recipient,priority:1"`
+	RecipientUserID uint64           `json:"recipient_user_id" gorm:"not null;uniqueIndex:idx_order_splits_order_recipient,priority:2;index"`
+	Amount          decimal.Decimal  `json:"amount" gorm:"type:numeric(20,2);not null"`
+	FeeAmount       decimal.Decimal  `json:"fee_amount" gorm:"type:numeric(20,2);not null;default:0"`
+	NetAmount       decimal.Decimal  `json:"net_amount" gorm:"type:numeric(20,2);not null;default:0"`
+	ReversedAmount  decimal.Decimal  `json:"reversed_amount" gorm:"type:numeric(20,2);not null;default:0"`
+	Status          OrderSplitStatus `json:"status" gorm:"type:varchar(20);not null"`
+	CreatedAt       time.Time        `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time        `json:"updated_at" gorm:"autoUpdateTime"`
+}
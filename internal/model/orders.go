@@ -31,6 +31,7 @@ import (
 
 	"github.com/linux-do/pay/internal/db"
 	"github.com/linux-do/pay/internal/logger"
+	"github.com/linux-do/pay/internal/util"
 	"github.com/shopspring/decimal"
 
 	"gorm.io/gorm"
@@ -43,39 +44,59 @@ const (
 	OrderTypePayment   OrderType = "payment"
 	OrderTypeTransfer  OrderType = "transfer"
 	OrderTypeCommunity OrderType = "community"
+	OrderTypePayout    OrderType = "payout"
+	OrderTypeEscrow    OrderType = "escrow"
+	OrderTypeRedPacket OrderType = "red_packet"
+	OrderTypeRedeem    OrderType = "redeem"
 )
 
 type OrderStatus string
 
 const (
-	OrderStatusSuccess   OrderStatus = "success"
-	OrderStatusFailed    OrderStatus = "failed"
-	OrderStatusPending   OrderStatus = "pending"
-	OrderStatusExpired   OrderStatus = "expired"
-	OrderStatusDisputing OrderStatus = "disputing"
-	OrderStatusRefund    OrderStatus = "refund"
-	OrderStatusRefused   OrderStatus = "refused"
+	OrderStatusSuccess    OrderStatus = "success"
+	OrderStatusFailed     OrderStatus = "failed"
+	OrderStatusPending    OrderStatus = "pending"
+	OrderStatusExpired    OrderStatus = "expired"
+	OrderStatusDisputing  OrderStatus = "disputing"
+	OrderStatusRefund     OrderStatus = "refund"
+	OrderStatusRefused    OrderStatus = "refused"
+	OrderStatusCancelled  OrderStatus = "cancelled"
+	OrderStatusHeld       OrderStatus = "held"       // 担保交易资金托管中
+	OrderStatusAuthorized OrderStatus = "authorized" // 预授权订单已冻结付款人余额，等待商户确认扣款
 )
 
 type Order struct {
-	ID              uint64          `json:"id" gorm:"primaryKey;autoIncrement"`
-	OrderNo         string          `json:"order_no" gorm:"-"`
-	OrderName       string          `json:"order_name" gorm:"size:64;not null"`
-	MerchantOrderNo string          `json:"merchant_order_no" gorm:"size:64;index"`
-	ClientID        string          `json:"client_id" gorm:"size:64;index:idx_orders_client_status_created,priority:1;index:idx_orders_client_payee,priority:1;index:idx_orders_client_payer,priority:1"`
-	PayerUserID     uint64          `json:"payer_user_id" gorm:"index:idx_orders_payer_status_type_created,priority:1;index:idx_orders_payer_status_type_trade,priority:1;index:idx_orders_client_payer,priority:2"`
-	PayeeUserID     uint64          `json:"payee_user_id" gorm:"index:idx_orders_payee_status_type_created,priority:1;index:idx_orders_client_payee,priority:2"`
-	PayerUsername   string          `json:"payer_username" gorm:"->"`
-	PayeeUsername   string          `json:"payee_username" gorm:"->"`
-	Amount          decimal.Decimal `json:"amount" gorm:"type:numeric(20,2);not null;index"`
-	Status          OrderStatus     `json:"status" gorm:"type:varchar(20);not null;index:idx_orders_payee_status_type_created,priority:2;index:idx_orders_payer_status_type_created,priority:2;index:idx_orders_client_status_created,priority:2;index:idx_orders_payer_status_type_trade,priority:2"`
-	Type            OrderType       `json:"type" gorm:"type:varchar(20);not null;index:idx_orders_payee_status_type_created,priority:3;index:idx_orders_payer_status_type_created,priority:3;index:idx_orders_payer_status_type_trade,priority:3"`
-	Remark          string          `json:"remark" gorm:"size:255"`
-	PaymentType     string          `json:"payment_type" gorm:"size:20"`
-	TradeTime       time.Time       `json:"trade_time" gorm:"index:idx_orders_payer_status_type_trade,priority:4"`
-	ExpiresAt       time.Time       `json:"expires_at" gorm:"not null"`
-	CreatedAt       time.Time       `json:"created_at" gorm:"autoCreateTime;index:idx_orders_payee_status_type_created,priority:4;index:idx_orders_payer_status_type_created,priority:4;index:idx_orders_client_status_created,priority:3"`
-	UpdatedAt       time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
+	ID                    uint64          `json:"id" gorm:"primaryKey;autoIncrement"`
+	OrderNo               string          `json:"order_no" gorm:"-"`
+	OrderName             string          `json:"order_name" gorm:"size:64;not null"`
+	MerchantOrderNo       string          `json:"merchant_order_no" gorm:"size:64;index"`
+	ClientID              string          `json:"client_id" gorm:"size:64;index:idx_orders_client_status_created,priority:1;index:idx_orders_client_payee,priority:1;index:idx_orders_client_payer,priority:1"`
+	PayerUserID           uint64          `json:"payer_user_id" gorm:"index:idx_orders_payer_status_type_created,priority:1;index:idx_orders_payer_status_type_trade,priority:1;index:idx_orders_client_payer,priority:2;index:idx_orders_link_payer,priority:2"`
+	PayeeUserID           uint64          `json:"payee_user_id" gorm:"index:idx_orders_payee_status_type_created,priority:1;index:idx_orders_client_payee,priority:2"`
+	PayerUsername         string          `json:"payer_username" gorm:"->"`
+	PayeeUsername         string          `json:"payee_username" gorm:"->"`
+	Amount                decimal.Decimal `json:"amount" gorm:"type:numeric(20,2);not null;index"`
+	Status                OrderStatus     `json:"status" gorm:"type:varchar(20);not null;index:idx_orders_payee_status_type_created,priority:2;index:idx_orders_payer_status_type_created,priority:2;index:idx_orders_client_status_created,priority:2;index:idx_orders_payer_status_type_trade,priority:2"`
+	Type                  OrderType       `json:"type" gorm:"type:varchar(20);not null;index:idx_orders_payee_status_type_created,priority:3;index:idx_orders_payer_status_type_created,priority:3;index:idx_orders_payer_status_type_trade,priority:3"`
+	Remark                string          `json:"remark" gorm:"size:255"`
+	PaymentType           string          `json:"payment_type" gorm:"size:20"`
+	PreAuth               bool            `json:"pre_auth" gorm:"not null;default:false"`
+	Metadata              util.StringMap  `json:"metadata" gorm:"type:jsonb"`
+	MerchantPaymentLinkID uint64          `json:"merchant_payment_link_id" gorm:"index:idx_orders_link_payer,priority:1"`
+	MerchantProductID     uint64          `json:"merchant_product_id" gorm:"index"`
+	CouponID              uint64          `json:"coupon_id" gorm:"index"`
+	OriginalAmount        decimal.Decimal `json:"original_amount" gorm:"type:numeric(20,2);not null;default:0"`
+	DiscountAmount        decimal.Decimal `json:"discount_amount" gorm:"type:numeric(20,2);not null;default:0"`
+	RefundedAmount        decimal.Decimal `json:"refunded_amount" gorm:"type:numeric(20,2);not null;default:0"`
+	SubscriptionID        uint64          `json:"subscription_id" gorm:"index"`
+	AllowanceID           uint64          `json:"allowance_id" gorm:"index"`
+	MoneyRequestID        uint64          `json:"money_request_id" gorm:"index"`
+	RedPacketID           uint64          `json:"red_packet_id" gorm:"index"`
+	ScheduledTransferID   uint64          `json:"scheduled_transfer_id" gorm:"index"`
+	TradeTime             time.Time       `json:"trade_time" gorm:"index:idx_orders_payer_status_type_trade,priority:4"`
+	ExpiresAt             time.Time       `json:"expires_at" gorm:"not null"`
+	CreatedAt             time.Time       `json:"created_at" gorm:"autoCreateTime;index:idx_orders_payee_status_type_created,priority:4;index:idx_orders_payer_status_type_created,priority:4;index:idx_orders_client_status_created,priority:3"`
+	UpdatedAt             time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
 }
 
 // AfterFind 格式化 OrderNo
@@ -84,6 +105,11 @@ func (o *Order) AfterFind(*gorm.DB) error {
 	return nil
 }
 
+// RefundableAmount 订单剩余可退款金额
+func (o *Order) RefundableAmount() decimal.Decimal {
+	return o.Amount.Sub(o.RefundedAmount)
+}
+
 // ExpirePendingOrders 将已过期且 pending 状态的订单设置为 expired
 func ExpirePendingOrders(ctx context.Context) {
 	result := db.DB(ctx).Model(&Order{}).
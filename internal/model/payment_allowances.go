@@ -0,0 +1,45 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PaymentAllowance 用户授予商户应用的免密支付额度
+type PaymentAllowance struct {
+	ID              uint64          `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID          uint64          `json:"user_id" gorm:"not null;uniqueIndex:idx_payment_allowances_user_client,priority:1"`
+	ClientID        string          `json:"client_id" gorm:"size:64;not null;uniqueIndex:idx_payment_allowances_user_client,priority:2"`
+	PerPaymentLimit decimal.Decimal `json:"per_payment_limit" gorm:"type:numeric(20,2);not null"`
+	DailyLimit      decimal.Decimal `json:"daily_limit" gorm:"type:numeric(20,2);not null"`
+	IsActive        bool            `json:"is_active" gorm:"not null;default:true"`
+	RevokedAt       *time.Time      `json:"revoked_at"`
+	AppName         string          `json:"app_name" gorm:"->"`
+	TodayUsedAmount decimal.Decimal `json:"today_used_amount" gorm:"-"`
+	CreatedAt       time.Time       `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
+}
@@ -0,0 +1,80 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type PayoutBatchStatus string
+
+const (
+	PayoutBatchStatusProcessing PayoutBatchStatus = "processing"
+	PayoutBatchStatusFinished   PayoutBatchStatus = "finished"
+)
+
+type PayoutItemStatus string
+
+const (
+	PayoutItemStatusPending PayoutItemStatus = "pending"
+	PayoutItemStatusSuccess PayoutItemStatus = "success"
+	PayoutItemStatusFailed  PayoutItemStatus = "failed"
+)
+
+// PayoutBatch 商户付款批次，单笔付款即只有一个明细的批次
+type PayoutBatch struct {
+	ID             uint64            `json:"id" gorm:"primaryKey;autoIncrement"`
+	ClientID       string            `json:"client_id" gorm:"size:64;not null;uniqueIndex:idx_payout_batches_client_out_batch,priority:1"`
+	OutBatchNo     string            `json:"out_batch_no" gorm:"size:64;not null;uniqueIndex:idx_payout_batches_client_out_batch,priority:2"`
+	MerchantUserID uint64            `json:"merchant_user_id" gorm:"not null;index"`
+	TotalCount     int               `json:"total_count" gorm:"not null"`
+	TotalAmount    decimal.Decimal   `json:"total_amount" gorm:"type:numeric(20,2);not null"`
+	SuccessCount   int               `json:"success_count" gorm:"not null;default:0"`
+	SuccessAmount  decimal.Decimal   `json:"success_amount" gorm:"type:numeric(20,2);not null;default:0"`
+	FailedCount    int               `json:"failed_count" gorm:"not null;default:0"`
+	Status         PayoutBatchStatus `json:"status" gorm:"type:varchar(20);not null;index"`
+	Remark         string            `json:"remark" gorm:"size:100"`
+	FinishedAt     *time.Time        `json:"finished_at"`
+	Items          []PayoutItem      `json:"items" gorm:"foreignKey:BatchID"`
+	CreatedAt      time.Time         `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time         `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// PayoutItem 商户付款明细，付款成功后关联一笔 payout 订单
+type PayoutItem struct {
+	ID              uint64           `json:"id" gorm:"primaryKey;autoIncrement"`
+	BatchID         uint64           `json:"batch_id" gorm:"not null;index"`
+	OutDetailNo     string           `json:"out_detail_no" gorm:"size:64"`
+	RecipientUserID uint64           `json:"recipient_user_id" gorm:"not null;index"`
+	Amount          decimal.Decimal  `json:"amount" gorm:"type:numeric(20,2);not null"`
+	Remark          string           `json:"remark" gorm:"size:100"`
+	Status          PayoutItemStatus `json:"status" gorm:"type:varchar(20);not null"`
+	FailReason      string           `json:"fail_reason" gorm:"size:255"`
+	OrderID         uint64           `json:"order_id"`
+	CreatedAt       time.Time        `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time        `json:"updated_at" gorm:"autoUpdateTime"`
+}
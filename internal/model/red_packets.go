@@ -0,0 +1,77 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type RedPacketSplitMode string
+
+const (
+	RedPacketSplitModeEqual  RedPacketSplitMode = "equal"  // 平均分配
+	RedPacketSplitModeRandom RedPacketSplitMode = "random" // 拼手气随机分配
+)
+
+type RedPacketStatus string
+
+const (
+	RedPacketStatusActive   RedPacketStatus = "active"   // 可领取
+	RedPacketStatusFinished RedPacketStatus = "finished" // 已领完
+	RedPacketStatusRefunded RedPacketStatus = "refunded" // 已过期，剩余金额已退回
+)
+
+// RedPacket 红包，创建时从发送者余额扣除全部金额，过期未领取的部分由定时任务退回
+type RedPacket struct {
+	ID              uint64             `json:"id" gorm:"primaryKey;autoIncrement"`
+	Token           string             `json:"token" gorm:"size:64;uniqueIndex;not null"`
+	SenderUserID    uint64             `json:"sender_user_id" gorm:"not null;index:idx_red_packets_sender_created,priority:1"`
+	TotalAmount     decimal.Decimal    `json:"total_amount" gorm:"type:numeric(20,2);not null"`
+	TotalCount      int                `json:"total_count" gorm:"not null"`
+	RemainingAmount decimal.Decimal    `json:"remaining_amount" gorm:"type:numeric(20,2);not null;check:remaining_amount >= 0"`
+	RemainingCount  int                `json:"remaining_count" gorm:"not null;check:remaining_count >= 0"`
+	SplitMode       RedPacketSplitMode `json:"split_mode" gorm:"type:varchar(20);not null"`
+	MinTrustLevel   TrustLevel         `json:"min_trust_level" gorm:"not null;default:0"`
+	Greeting        string             `json:"greeting" gorm:"size:64"`
+	Status          RedPacketStatus    `json:"status" gorm:"type:varchar(20);not null;index:idx_red_packets_status_expires,priority:1"`
+	RefundedAmount  decimal.Decimal    `json:"refunded_amount" gorm:"type:numeric(20,2);not null;default:0"`
+	ExpiresAt       time.Time          `json:"expires_at" gorm:"not null;index:idx_red_packets_status_expires,priority:2"`
+	SenderUsername  string             `json:"sender_username" gorm:"->"`
+	CreatedAt       time.Time          `json:"created_at" gorm:"autoCreateTime;index:idx_red_packets_sender_created,priority:2"`
+	UpdatedAt       time.Time          `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// RedPacketClaim 红包领取记录，每个用户对同一红包只能领取一次
+type RedPacketClaim struct {
+	ID          uint64          `json:"id" gorm:"primaryKey;autoIncrement"`
+	RedPacketID uint64          `json:"red_packet_id" gorm:"not null;uniqueIndex:idx_red_packet_claims_packet_user,priority:1"`
+	UserID      uint64          `json:"user_id" gorm:"not null;uniqueIndex:idx_red_packet_claims_packet_user,priority:2;index"`
+	Amount      decimal.Decimal `json:"amount" gorm:"type:numeric(20,2);not null"`
+	OrderID     uint64          `json:"order_id"`
+	Username    string          `json:"username" gorm:"->"`
+	CreatedAt   time.Time       `json:"created_at" gorm:"autoCreateTime"`
+}
@@ -0,0 +1,80 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type RedeemCodeBatchStatus string
+
+const (
+	RedeemCodeBatchStatusActive   RedeemCodeBatchStatus = "active"
+	RedeemCodeBatchStatusDisabled RedeemCodeBatchStatus = "disabled"
+)
+
+// RedeemCodeBatch 兑换码批次
+// ClientID 为空表示由管理员发放、平台出资；否则由对应商户应用发放，创建时从商户余额预扣全部额度，停用时退回未兑换部分
+type RedeemCodeBatch struct {
+	ID             uint64                `json:"id" gorm:"primaryKey;autoIncrement"`
+	ClientID       string                `json:"client_id" gorm:"size:64;index:idx_redeem_code_batches_client_created,priority:1"`
+	CreatorUserID  uint64                `json:"creator_user_id" gorm:"not null;index"`
+	Name           string                `json:"name" gorm:"size:64;not null"`
+	Amount         decimal.Decimal       `json:"amount" gorm:"type:numeric(20,2);not null"`
+	CodeCount      int                   `json:"code_count" gorm:"not null"`
+	MaxUses        int                   `json:"max_uses" gorm:"not null;default:1"`
+	PerUserLimit   int                   `json:"per_user_limit" gorm:"not null;default:1"`
+	TotalBudget    decimal.Decimal       `json:"total_budget" gorm:"type:numeric(20,2);not null"`
+	RedeemedCount  int                   `json:"redeemed_count" gorm:"not null;default:0"`
+	RedeemedAmount decimal.Decimal       `json:"redeemed_amount" gorm:"type:numeric(20,2);not null;default:0"`
+	RefundedAmount decimal.Decimal       `json:"refunded_amount" gorm:"type:numeric(20,2);not null;default:0"`
+	Status         RedeemCodeBatchStatus `json:"status" gorm:"type:varchar(20);not null"`
+	ExpiresAt      *time.Time            `json:"expires_at"`
+	CreatedAt      time.Time             `json:"created_at" gorm:"autoCreateTime;index:idx_redeem_code_batches_client_created,priority:2"`
+	UpdatedAt      time.Time             `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// RedeemCode 兑换码，MaxUses 为 1 时为一次性兑换码
+type RedeemCode struct {
+	ID        uint64    `json:"id" gorm:"primaryKey;autoIncrement"`
+	BatchID   uint64    `json:"batch_id" gorm:"not null;index"`
+	Code      string    `json:"code" gorm:"size:32;uniqueIndex;not null"`
+	MaxUses   int       `json:"max_uses" gorm:"not null"`
+	UsedCount int       `json:"used_count" gorm:"not null;default:0"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// RedeemCodeUse 兑换记录
+type RedeemCodeUse struct {
+	ID        uint64          `json:"id" gorm:"primaryKey;autoIncrement"`
+	BatchID   uint64          `json:"batch_id" gorm:"not null;index:idx_redeem_code_uses_batch_user,priority:1"`
+	CodeID    uint64          `json:"code_id" gorm:"not null;uniqueIndex:idx_redeem_code_uses_code_user,priority:1"`
+	UserID    uint64          `json:"user_id" gorm:"not null;index:idx_redeem_code_uses_batch_user,priority:2;uniqueIndex:idx_redeem_code_uses_code_user,priority:2"`
+	Amount    decimal.Decimal `json:"amount" gorm:"type:numeric(20,2);not null"`
+	OrderID   uint64          `json:"order_id"`
+	CreatedAt time.Time       `json:"created_at" gorm:"autoCreateTime"`
+}
@@ -0,0 +1,66 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type ScheduledTransferStatus string
+
+const (
+	ScheduledTransferStatusActive    ScheduledTransferStatus = "active"    // 等待执行
+	ScheduledTransferStatusCompleted ScheduledTransferStatus = "completed" // 单次转账已执行或周期转账已到结束时间
+	ScheduledTransferStatusFailed    ScheduledTransferStatus = "failed"    // 执行失败次数达到上限，不再执行
+	ScheduledTransferStatusCancelled ScheduledTransferStatus = "cancelled" // 用户已取消
+)
+
+// ScheduledTransfer 定时转账，CronExpr 为空时为单次转账，否则按 cron 表达式周期执行
+type ScheduledTransfer struct {
+	ID                uint64                  `json:"id" gorm:"primaryKey;autoIncrement"`
+	SenderUserID      uint64                  `json:"sender_user_id" gorm:"not null;index:idx_scheduled_transfers_sender_created,priority:1"`
+	RecipientUserID   uint64                  `json:"recipient_user_id" gorm:"not null"`
+	Amount            decimal.Decimal         `json:"amount" gorm:"type:numeric(20,2);not null"`
+	Remark            string                  `json:"remark" gorm:"size:100"`
+	CronExpr          string                  `json:"cron_expr" gorm:"size:64"`
+	NextRunAt         time.Time               `json:"next_run_at" gorm:"not null;index:idx_scheduled_transfers_status_next_run,priority:2"`
+	EndsAt            *time.Time              `json:"ends_at"`
+	Status            ScheduledTransferStatus `json:"status" gorm:"type:varchar(20);not null;index:idx_scheduled_transfers_status_next_run,priority:1"`
+	RunCount          int                     `json:"run_count" gorm:"not null;default:0"`
+	FailureCount      int                     `json:"failure_count" gorm:"not null;default:0"`
+	LastRunAt         *time.Time              `json:"last_run_at"`
+	LastOrderID       uint64                  `json:"last_order_id"`
+	LastError         string                  `json:"last_error" gorm:"size:255"`
+	RecipientUsername string                  `json:"recipient_username" gorm:"->"`
+	CreatedAt         time.Time               `json:"created_at" gorm:"autoCreateTime;index:idx_scheduled_transfers_sender_created,priority:2"`
+	UpdatedAt         time.Time               `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// IsRecurring 是否为周期转账
+func (s *ScheduledTransfer) IsRecurring() bool {
+	return s.CronExpr != ""
+}
@@ -0,0 +1,69 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type SubscriptionInterval string
+
+const (
+	SubscriptionIntervalDay   SubscriptionInterval = "day"
+	SubscriptionIntervalWeek  SubscriptionInterval = "week"
+	SubscriptionIntervalMonth SubscriptionInterval = "month"
+	SubscriptionIntervalYear  SubscriptionInterval = "year"
+)
+
+type SubscriptionPlan struct {
+	ID               uint64               `json:"id" gorm:"primaryKey;autoIncrement"`
+	MerchantAPIKeyID uint64               `json:"merchant_api_key_id" gorm:"not null;index"`
+	Name             string               `json:"name" gorm:"size:30;not null"`
+	Description      string               `json:"description" gorm:"size:100"`
+	Amount           decimal.Decimal      `json:"amount" gorm:"type:numeric(20,2);not null"`
+	Interval         SubscriptionInterval `json:"interval" gorm:"type:varchar(10);not null"`
+	IntervalCount    int                  `json:"interval_count" gorm:"not null;default:1"`
+	TrialDays        int                  `json:"trial_days" gorm:"not null;default:0"`
+	IsActive         bool                 `json:"is_active" gorm:"not null;default:true"`
+	CreatedAt        time.Time            `json:"created_at" gorm:"autoCreateTime;index"`
+	UpdatedAt        time.Time            `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt        gorm.DeletedAt       `json:"deleted_at" gorm:"index"`
+}
+
+// NextPeriodEnd 计算从 start 开始的一个计费周期结束时间
+func (p *SubscriptionPlan) NextPeriodEnd(start time.Time) time.Time {
+	switch p.Interval {
+	case SubscriptionIntervalDay:
+		return start.AddDate(0, 0, p.IntervalCount)
+	case SubscriptionIntervalWeek:
+		return start.AddDate(0, 0, 7*p.IntervalCount)
+	case SubscriptionIntervalYear:
+		return start.AddDate(p.IntervalCount, 0, 0)
+	default:
+		return start.AddDate(0, p.IntervalCount, 0)
+	}
+}
@@ -0,0 +1,61 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+package model
+
+import (
+	"time"
+)
+
+type SubscriptionStatus string
+
+const (
+	SubscriptionStatusTrialing  SubscriptionStatus = "trialing"
+	SubscriptionStatusActive    SubscriptionStatus = "active"
+	SubscriptionStatusPastDue   SubscriptionStatus = "past_due"
+	SubscriptionStatusPaused    SubscriptionStatus = "paused"
+	SubscriptionStatusCancelled SubscriptionStatus = "cancelled"
+	SubscriptionStatusExpired   SubscriptionStatus = "expired"
+)
+
+type Subscription struct {
+	ID                 uint64             `json:"id" gorm:"primaryKey;autoIncrement"`
+	PlanID             uint64             `json:"plan_id" gorm:"not null;index:idx_subscriptions_plan_user,priority:1"`
+	MerchantAPIKeyID   uint64             `json:"merchant_api_key_id" gorm:"not null;index"`
+	ClientID           string             `json:"client_id" gorm:"size:64;not null"`
+	UserID             uint64             `json:"user_id" gorm:"not null;index:idx_subscriptions_plan_user,priority:2;index"`
+	Status             SubscriptionStatus `json:"status" gorm:"type:varchar(20);not null;index:idx_subscriptions_status_next_billing,priority:1"`
+	CurrentPeriodStart time.Time          `json:"current_period_start"`
+	CurrentPeriodEnd   time.Time          `json:"current_period_end"`
+	TrialEndsAt        *time.Time         `json:"trial_ends_at"`
+	NextBillingAt      time.Time          `json:"next_billing_at" gorm:"index:idx_subscriptions_status_next_billing,priority:2"`
+	RetryCount         int                `json:"retry_count" gorm:"not null;default:0"`
+	GraceUntil         *time.Time         `json:"grace_until"`
+	LastOrderID        uint64             `json:"last_order_id"`
+	PausedAt           *time.Time         `json:"paused_at"`
+	CancelledAt        *time.Time         `json:"cancelled_at"`
+	PlanName           string             `json:"plan_name" gorm:"->"`
+	Username           string             `json:"username" gorm:"->"`
+	CreatedAt          time.Time          `json:"created_at" gorm:"autoCreateTime;index"`
+	UpdatedAt          time.Time          `json:"updated_at" gorm:"autoUpdateTime"`
+}
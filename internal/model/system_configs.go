@@ -37,9 +37,23 @@ import (
 
 // 配置键常量 - 所有系统配置的 key 定义
 const (
-	ConfigKeyMerchantOrderExpireMinutes = "merchant_order_expire_minutes" // 商家订单过期时间（分钟）
-	ConfigKeyWebsiteOrderExpireMinutes  = "website_order_expire_minutes"  // 网站订单过期时间（分钟）
-	ConfigKeyDisputeTimeWindowHours     = "dispute_time_window_hours"     // 商家争议时间窗口（小时）
+	ConfigKeyMerchantOrderExpireMinutes       = "merchant_order_expire_minutes"        // 商家订单过期时间（分钟）
+	ConfigKeyWebsiteOrderExpireMinutes        = "website_order_expire_minutes"         // 网站订单过期时间（分钟）
+	ConfigKeyDisputeTimeWindowHours           = "dispute_time_window_hours"            // 商家争议时间窗口（小时）
+	ConfigKeyMerchantSignTimestampSkewSeconds = "merchant_sign_timestamp_skew_seconds" // 商户签名请求时间戳允许偏差（秒）
+	ConfigKeyMerchantOrderMinExpireMinutes    = "merchant_order_min_expire_minutes"    // 商户自定义订单过期时间下限（分钟）
+	ConfigKeyMerchantOrderMaxExpireMinutes    = "merchant_order_max_expire_minutes"    // 商户自定义订单过期时间上限（分钟）
+	ConfigKeySubscriptionRetryIntervalHours   = "subscription_retry_interval_hours"    // 订阅续费失败重试间隔（小时）
+	ConfigKeySubscriptionGracePeriodDays      = "subscription_grace_period_days"       // 订阅续费失败宽限期（天）
+	ConfigKeyPayoutMaxBatchSize               = "payout_max_batch_size"                // 商户单批付款最大笔数
+	ConfigKeyEscrowAutoConfirmDays            = "escrow_auto_confirm_days"             // 担保交易发货后自动确认收货时间（天）
+	ConfigKeyRedPacketExpireHours             = "red_packet_expire_hours"              // 红包过期时间（小时）
+	ConfigKeyRedPacketMaxCount                = "red_packet_max_count"                 // 单个红包最大个数
+	ConfigKeyRedeemMaxFailuresPerHour         = "redeem_max_failures_per_hour"         // 每小时兑换码输入错误次数上限
+	ConfigKeyScheduledTransferMaxActive       = "scheduled_transfer_max_active"        // 每个用户可同时生效的定时转账数量上限
+	ConfigKeyScheduledTransferMaxFailures     = "scheduled_transfer_max_failures"      // 周期转账连续失败次数上限
+	ConfigKeyUserSearchRateLimitPerMinute     = "user_search_rate_limit_per_minute"    // 每个用户每分钟搜索用户次数上限
+	ConfigKeyOrderAuthorizationVoidHours      = "order_authorization_void_hours"       // 预授权订单未确认扣款自动撤销时间（小时）
 )
 
 const (
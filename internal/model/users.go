@@ -83,6 +83,8 @@ type User struct {
 	TotalCommunity   decimal.Decimal `json:"total_community" gorm:"type:numeric(20,2);default:0"`
 	CommunityBalance decimal.Decimal `json:"community_balance" gorm:"type:numeric(20,2);default:0"`
 	AvailableBalance decimal.Decimal `json:"available_balance" gorm:"type:numeric(20,2);default:0"`
+	EscrowBalance    decimal.Decimal `json:"escrow_balance" gorm:"type:numeric(20,2);default:0"`
+	HeldBalance      decimal.Decimal `json:"held_balance" gorm:"type:numeric(20,2);default:0"`
 	IsActive         bool            `json:"is_active" gorm:"default:true"`
 	IsAdmin          bool            `json:"is_admin" gorm:"default:false"`
 	LastLoginAt      time.Time       `json:"last_login_at" gorm:"index"`
@@ -37,10 +37,23 @@ import (
 	"time"
 
 	"github.com/linux-do/pay/internal/apps/admin"
+	"github.com/linux-do/pay/internal/apps/allowance"
 	publicconfig "github.com/linux-do/pay/internal/apps/config"
 	"github.com/linux-do/pay/internal/apps/dispute"
+	"github.com/linux-do/pay/internal/apps/escrow"
+	"github.com/linux-do/pay/internal/apps/idempotency"
 	"github.com/linux-do/pay/internal/apps/merchant/api_key"
+	"github.com/linux-do/pay/internal/apps/merchant/coupon"
 	"github.com/linux-do/pay/internal/apps/merchant/link"
+	"github.com/linux-do/pay/internal/apps/merchant/product"
+	"github.com/linux-do/pay/internal/apps/money_request"
+	"github.com/linux-do/pay/internal/apps/notification"
+	"github.com/linux-do/pay/internal/apps/payout"
+	"github.com/linux-do/pay/internal/apps/receive_code"
+	"github.com/linux-do/pay/internal/apps/red_packet"
+	"github.com/linux-do/pay/internal/apps/redeem"
+	"github.com/linux-do/pay/internal/apps/scheduled_transfer"
+	"github.com/linux-do/pay/internal/apps/subscription"
 	"github.com/linux-do/pay/internal/listener"
 
 	"github.com/linux-do/pay/internal/apps/payment"
@@ -56,6 +69,7 @@ import (
 	"github.com/linux-do/pay/internal/apps/order"
 	"github.com/linux-do/pay/internal/apps/user"
 	"github.com/linux-do/pay/internal/config"
+	"github.com/linux-do/pay/internal/model"
 	"github.com/linux-do/pay/internal/otel_trace"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -101,6 +115,8 @@ func Serve() {
 
 	// 支付接口
 	r.POST("/pay/submit.php", payment.RequireSignatureAuth(), payment.CreateMerchantOrder)
+	// API 支付接口
+	r.POST("/mapi.php", payment.RequireSignatureAuth(), payment.CreateMerchantOrderAPI)
 	// 查询订单
 	r.GET("/api.php", payment.QueryMerchantOrder)
 	// 退款接口
@@ -130,6 +146,11 @@ func Serve() {
 			userRouter.Use(oauth.LoginRequired())
 			{
 				userRouter.PUT("/pay-key", user.UpdatePayKey)
+				userRouter.GET("/search", user.SearchUsers)
+				userRouter.GET("/allowances", allowance.ListAllowances)
+				userRouter.PUT("/allowances", allowance.GrantAllowance)
+				userRouter.DELETE("/allowances/:id", allowance.RevokeAllowance)
+				userRouter.GET("/allowances/:id/usages", allowance.ListAllowanceUsages)
 			}
 
 			// Order
@@ -148,7 +169,91 @@ func Serve() {
 			paymentRouter := apiV1Router.Group("/payment")
 			paymentRouter.Use(oauth.LoginRequired())
 			{
-				paymentRouter.POST("/transfer", payment.Transfer)
+				paymentRouter.POST("/transfer", idempotency.Idempotent(), payment.Transfer)
+			}
+
+			// Escrow
+			escrowRouter := apiV1Router.Group("/escrow")
+			escrowRouter.Use(oauth.LoginRequired())
+			{
+				escrowRouter.POST("", idempotency.Idempotent(), escrow.CreateEscrow)
+				escrowRouter.GET("", escrow.ListEscrows)
+				escrowRouter.GET("/:id", escrow.GetEscrow)
+				escrowRouter.POST("/:id/ship", escrow.ShipEscrow)
+				escrowRouter.POST("/:id/confirm", idempotency.Idempotent(), escrow.ConfirmEscrow)
+				escrowRouter.POST("/:id/cancel", escrow.CancelEscrow)
+				escrowRouter.POST("/:id/dispute", escrow.DisputeEscrow)
+			}
+
+			// Money Request
+			moneyRequestRouter := apiV1Router.Group("/money-requests")
+			moneyRequestRouter.Use(oauth.LoginRequired())
+			{
+				moneyRequestRouter.POST("", money_request.CreateMoneyRequest)
+				moneyRequestRouter.GET("", money_request.ListMoneyRequests)
+				moneyRequestRouter.GET("/:token", money_request.GetMoneyRequest)
+				moneyRequestRouter.POST("/:token/pay", idempotency.Idempotent(), money_request.PayMoneyRequest)
+				moneyRequestRouter.POST("/:token/decline", money_request.DeclineMoneyRequest)
+				moneyRequestRouter.POST("/:token/cancel", money_request.CancelMoneyRequest)
+			}
+
+			// Red Packet
+			redPacketRouter := apiV1Router.Group("/red-packets")
+			redPacketRouter.Use(oauth.LoginRequired())
+			{
+				redPacketRouter.POST("", idempotency.Idempotent(), red_packet.CreateRedPacket)
+				redPacketRouter.GET("", red_packet.ListRedPackets)
+				redPacketRouter.GET("/:token", red_packet.GetRedPacket)
+				redPacketRouter.POST("/:token/claim", idempotency.Idempotent(), red_packet.ClaimRedPacket)
+			}
+
+			// Receive Code
+			receiveCodeRouter := apiV1Router.Group("/receive-code")
+			receiveCodeRouter.Use(oauth.LoginRequired())
+			{
+				receiveCodeRouter.GET("", receive_code.GetMyReceiveCode)
+				receiveCodeRouter.GET("/qrcode", receive_code.GetMyReceiveCodeQRCode)
+				receiveCodeRouter.POST("/regenerate", receive_code.RegenerateReceiveCode)
+			}
+			receiveCodesRouter := apiV1Router.Group("/receive-codes")
+			receiveCodesRouter.Use(oauth.LoginRequired())
+			{
+				receiveCodesRouter.GET("/:token", receive_code.GetReceiveCodeOwner)
+				receiveCodesRouter.POST("/:token/pay", idempotency.Idempotent(), receive_code.PayReceiveCode)
+			}
+
+			// Scheduled Transfer
+			scheduledTransferRouter := apiV1Router.Group("/scheduled-transfers")
+			scheduledTransferRouter.Use(oauth.LoginRequired())
+			{
+				scheduledTransferRouter.POST("", scheduled_transfer.CreateScheduledTransfer)
+				scheduledTransferRouter.GET("", scheduled_transfer.ListScheduledTransfers)
+				scheduledTransferRouter.GET("/:id", scheduled_transfer.GetScheduledTransfer)
+				scheduledTransferRouter.PUT("/:id", scheduled_transfer.UpdateScheduledTransfer)
+				scheduledTransferRouter.POST("/:id/cancel", scheduled_transfer.CancelScheduledTransfer)
+			}
+
+			// Redeem
+			apiV1Router.POST("/redeem", oauth.LoginRequired(), idempotency.Idempotent(), redeem.Redeem)
+
+			// Notification
+			notificationRouter := apiV1Router.Group("/notifications")
+			notificationRouter.Use(oauth.LoginRequired())
+			{
+				notificationRouter.GET("", notification.ListNotifications)
+				notificationRouter.POST("/read", notification.MarkNotificationsRead)
+			}
+
+			// Subscription
+			subscriptionRouter := apiV1Router.Group("/subscription")
+			subscriptionRouter.Use(oauth.LoginRequired())
+			{
+				subscriptionRouter.GET("/plans/:planId", subscription.GetPlan)
+				subscriptionRouter.GET("/subscriptions", subscription.ListMySubscriptions)
+				subscriptionRouter.POST("/subscriptions", idempotency.Idempotent(), subscription.Subscribe)
+				subscriptionRouter.POST("/subscriptions/:subscriptionId/cancel", subscription.CancelSubscription)
+				subscriptionRouter.POST("/subscriptions/:subscriptionId/pause", subscription.PauseSubscription)
+				subscriptionRouter.POST("/subscriptions/:subscriptionId/resume", subscription.ResumeSubscription)
 			}
 
 			// Config (public)
@@ -175,18 +280,96 @@ func Serve() {
 					{
 						linkRouter.GET("", link.ListPaymentLinks)
 						linkRouter.POST("", link.CreatePaymentLink)
+						linkRouter.PUT("/:linkId", link.UpdatePaymentLink)
+						linkRouter.GET("/:linkId/stats", link.GetPaymentLinkStats)
 						linkRouter.DELETE("/:linkId", link.DeletePaymentLink)
 					}
+
+					// Products
+					productRouter := apiKeyRouter.Group("/products")
+					{
+						productRouter.GET("", product.ListProducts)
+						productRouter.POST("", product.CreateProduct)
+						productRouter.PUT("/:productId", product.UpdateProduct)
+						productRouter.DELETE("/:productId", product.DeleteProduct)
+						productRouter.GET("/:productId/codes", product.ListProductCodes)
+						productRouter.POST("/:productId/codes", product.UploadProductCodes)
+					}
+
+					// Coupons
+					couponRouter := apiKeyRouter.Group("/coupons")
+					{
+						couponRouter.GET("", coupon.ListCoupons)
+						couponRouter.POST("", coupon.CreateCoupon)
+						couponRouter.PUT("/:couponId", coupon.UpdateCoupon)
+						couponRouter.DELETE("/:couponId", coupon.DeleteCoupon)
+						couponRouter.GET("/:couponId/redemptions", coupon.ListCouponRedemptions)
+					}
+
+					// Subscription Plans
+					planRouter := apiKeyRouter.Group("/subscription-plans")
+					{
+						planRouter.GET("", subscription.ListPlans)
+						planRouter.POST("", subscription.CreatePlan)
+						planRouter.PUT("/:planId", subscription.UpdatePlan)
+						planRouter.DELETE("/:planId", subscription.DeletePlan)
+					}
+
+					// Subscriptions
+					merchantSubscriptionRouter := apiKeyRouter.Group("/subscriptions")
+					{
+						merchantSubscriptionRouter.GET("", subscription.ListMerchantSubscriptions)
+						merchantSubscriptionRouter.POST("/:subscriptionId/cancel", subscription.MerchantCancelSubscription)
+						merchantSubscriptionRouter.POST("/:subscriptionId/pause", subscription.MerchantPauseSubscription)
+						merchantSubscriptionRouter.POST("/:subscriptionId/resume", subscription.MerchantResumeSubscription)
+					}
+
+					// Redeem Code Batches
+					merchantRedeemRouter := apiKeyRouter.Group("/redeem-batches")
+					{
+						merchantRedeemRouter.POST("", redeem.CreateRedeemBatch)
+						merchantRedeemRouter.GET("", redeem.ListRedeemBatches)
+						merchantRedeemRouter.GET("/:batchId/stats", redeem.GetRedeemBatchStats)
+						merchantRedeemRouter.GET("/:batchId/export", redeem.ExportRedeemBatch)
+						merchantRedeemRouter.POST("/:batchId/disable", redeem.DisableRedeemBatch)
+					}
 				}
 
 				merchantRouter.GET("/payment-links/:token", oauth.LoginRequired(), link.GetPaymentLinkByToken)
-				merchantRouter.POST("/payment-links/pay", oauth.LoginRequired(), link.PayByLink)
+				merchantRouter.GET("/payment-links/:token/qrcode", link.RequirePaymentLink(), link.GetPaymentLinkQRCode)
+				merchantRouter.GET("/payment-links/:token/button", link.RequirePaymentLink(), link.GetPaymentLinkButton)
+				merchantRouter.GET("/payment-links/:token/coupon", oauth.LoginRequired(), link.RequirePaymentLink(), link.PreviewPaymentLinkCoupon)
+				merchantRouter.POST("/payment-links/pay", oauth.LoginRequired(), idempotency.Idempotent(), link.PayByLink)
+
+				// Storefront
+				merchantRouter.GET("/stores/:clientId", product.GetStore)
+				merchantRouter.POST("/products/:productId/buy", oauth.LoginRequired(), idempotency.Idempotent(), product.BuyProduct)
+				merchantRouter.GET("/product-orders/:tradeNo/deliveries", oauth.LoginRequired(), product.ListOrderDeliveries)
 
 				// MerchantAPIKey Payment
 				MerchantPaymentRouter := merchantRouter.Group("/payment")
 				{
 					MerchantPaymentRouter.GET("/order", oauth.LoginRequired(), payment.GetPaymentPageDetails)
-					MerchantPaymentRouter.POST("", oauth.LoginRequired(), payment.PayMerchantOrder)
+					MerchantPaymentRouter.POST("", oauth.LoginRequired(), idempotency.Idempotent(), payment.PayMerchantOrder)
+					MerchantPaymentRouter.POST("/cancel", oauth.LoginRequired(), payment.CancelMerchantOrder)
+					MerchantPaymentRouter.GET("/events", oauth.LoginRequired(), payment.StreamOrderStatus)
+					MerchantPaymentRouter.GET("/qrcode", payment.GetOrderQRCode)
+					MerchantPaymentRouter.GET("/coupon", oauth.LoginRequired(), payment.PreviewOrderCoupon)
+				}
+
+				// MerchantAPIKey Native Order
+				merchantRouter.POST("/orders", payment.RequireMerchantAuth(), payment.CreateNativeOrder)
+				merchantRouter.GET("/orders/events", payment.RequireMerchantAuth(), payment.StreamMerchantOrderEvents)
+				merchantRouter.POST("/charges", payment.RequireMerchantAuth(), idempotency.Idempotent(), payment.ChargeUser)
+				merchantRouter.POST("/orders/:tradeNo/capture", payment.RequireMerchantAuth(), idempotency.Idempotent(), payment.CaptureMerchantOrder)
+				merchantRouter.POST("/orders/:tradeNo/void", payment.RequireMerchantAuth(), payment.VoidMerchantOrder)
+
+				// Payouts
+				payoutRouter := merchantRouter.Group("/payouts")
+				payoutRouter.Use(payment.RequireMerchantAuth(), payment.RequireAPIKeyScope(model.APIKeyScopePayoutWrite))
+				{
+					payoutRouter.POST("", idempotency.Idempotent(), payout.CreatePayout)
+					payoutRouter.GET("/:outBatchNo", payout.GetPayout)
 				}
 			}
 
@@ -215,6 +398,20 @@ func Serve() {
 					userPayConfigRouter.PUT("", user_pay_config.UpdateUserPayConfig)
 					userPayConfigRouter.DELETE("", user_pay_config.DeleteUserPayConfig)
 				}
+
+				// Escrow Arbitration
+				adminRouter.GET("/escrows", escrow.ListDisputedEscrows)
+				adminRouter.POST("/escrows/:id/arbitrate", escrow.ArbitrateEscrow)
+
+				// Redeem Code Batches
+				adminRedeemRouter := adminRouter.Group("/redeem-batches")
+				{
+					adminRedeemRouter.POST("", redeem.CreateRedeemBatch)
+					adminRedeemRouter.GET("", redeem.ListRedeemBatches)
+					adminRedeemRouter.GET("/:batchId/stats", redeem.GetRedeemBatchStats)
+					adminRedeemRouter.GET("/:batchId/export", redeem.ExportRedeemBatch)
+					adminRedeemRouter.POST("/:batchId/disable", redeem.DisableRedeemBatch)
+				}
 			}
 		}
 	}
@@ -225,6 +422,10 @@ func Serve() {
 		log.Fatalf("[API] 警告: 启动过期监听器失败: %v\n", err)
 	}
 
+	if err := listener.StartOrderEventListener(expireListenerCtx); err != nil {
+		log.Fatalf("[API] 警告: 启动订单事件监听器失败: %v\n", err)
+	}
+
 	srv := &http.Server{
 		Addr:    config.Config.App.Addr,
 		Handler: r,
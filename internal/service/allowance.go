@@ -0,0 +1,91 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/linux-do/pay/internal/common"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// allowanceCountedStatuses 计入免密额度的订单状态
+var allowanceCountedStatuses = []model.OrderStatus{model.OrderStatusSuccess, model.OrderStatusDisputing, model.OrderStatusRefused, model.OrderStatusAuthorized}
+
+// GetAllowanceTodayUsed 查询免密额度当日已使用金额
+func GetAllowanceTodayUsed(tx *gorm.DB, allowanceID uint64) (decimal.Decimal, error) {
+	now := time.Now()
+	todayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	var used decimal.Decimal
+	if err := tx.Model(&model.Order{}).
+		Where("allowance_id = ? AND status IN ? AND trade_time >= ?", allowanceID, allowanceCountedStatuses, todayStart).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&used).Error; err != nil {
+		return decimal.Zero, err
+	}
+	return used, nil
+}
+
+// ConsumeAllowance 在事务内锁定并校验用户对商户应用的免密额度
+// 校验通过后调用方需将订单的 AllowanceID 设为返回额度的 ID，以便计入当日用量
+func ConsumeAllowance(tx *gorm.DB, userID uint64, clientID string, amount decimal.Decimal) (*model.PaymentAllowance, error) {
+	var allowance model.PaymentAllowance
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND client_id = ? AND is_active = ?", userID, clientID, true).
+		First(&allowance).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New(common.AllowanceNotGranted)
+		}
+		return nil, err
+	}
+
+	if amount.GreaterThan(allowance.PerPaymentLimit) {
+		return nil, errors.New(common.AllowancePerPaymentExceeded)
+	}
+
+	used, err := GetAllowanceTodayUsed(tx, allowance.ID)
+	if err != nil {
+		return nil, err
+	}
+	if used.Add(amount).GreaterThan(allowance.DailyLimit) {
+		return nil, errors.New(common.AllowanceDailyExceeded)
+	}
+
+	return &allowance, nil
+}
+
+// IsAllowanceError 判断是否为免密额度校验错误
+func IsAllowanceError(err error) bool {
+	switch err.Error() {
+	case common.AllowanceNotGranted, common.AllowancePerPaymentExceeded, common.AllowanceDailyExceeded:
+		return true
+	default:
+		return false
+	}
+}
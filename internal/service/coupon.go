@@ -0,0 +1,169 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package service
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/linux-do/pay/internal/common"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// minPayableAmount 使用优惠券后订单的最低应付金额
+var minPayableAmount = decimal.New(1, -2)
+
+// CouponDiscount 优惠券对一笔订单的减免结果
+type CouponDiscount struct {
+	Coupon         *model.MerchantCoupon `json:"-"`
+	Code           string                `json:"code"`
+	OriginalAmount decimal.Decimal       `json:"original_amount"`
+	DiscountAmount decimal.Decimal       `json:"discount_amount"`
+	PayableAmount  decimal.Decimal       `json:"payable_amount"`
+}
+
+// NormalizeCouponCode 统一优惠码格式，优惠码不区分大小写
+func NormalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// ResolveCoupon 校验优惠券对订单是否可用并计算减免金额，linkID 为 0 表示非支付链接订单
+// lock 为 true 时锁定优惠券，用于支付事务内核销
+func ResolveCoupon(tx *gorm.DB, apiKeyID uint64, linkID uint64, userID uint64, code string, amount decimal.Decimal, lock bool) (*CouponDiscount, error) {
+	query := tx.Where("merchant_api_key_id = ? AND code = ? AND is_active = ?", apiKeyID, NormalizeCouponCode(code), true)
+	if lock {
+		query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+
+	var coupon model.MerchantCoupon
+	if err := query.First(&coupon).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New(common.CouponNotFound)
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if coupon.StartsAt != nil && coupon.StartsAt.After(now) {
+		return nil, errors.New(common.CouponNotStarted)
+	}
+	if coupon.EndsAt != nil && !coupon.EndsAt.After(now) {
+		return nil, errors.New(common.CouponExpired)
+	}
+	if coupon.MaxUses != nil && coupon.UsedCount >= *coupon.MaxUses {
+		return nil, errors.New(common.CouponUsageExceeded)
+	}
+	if coupon.Scope == model.CouponScopeLinks && (linkID == 0 || !slices.Contains(coupon.PaymentLinkIDs, linkID)) {
+		return nil, errors.New(common.CouponNotApplicable)
+	}
+	if coupon.MinSpend != nil && amount.LessThan(*coupon.MinSpend) {
+		return nil, errors.New(common.CouponMinSpendNotMet)
+	}
+
+	if coupon.PerUserLimit != nil {
+		var used int64
+		if err := tx.Model(&model.MerchantCouponRedemption{}).
+			Where("coupon_id = ? AND user_id = ?", coupon.ID, userID).
+			Count(&used).Error; err != nil {
+			return nil, err
+		}
+		if used >= *coupon.PerUserLimit {
+			return nil, errors.New(common.CouponPerUserExceeded)
+		}
+	}
+
+	// 百分比减免向下取整到分，减免后至少应付 0.01
+	discount := coupon.DiscountValue
+	if coupon.DiscountType == model.CouponDiscountTypePercentage {
+		discount = amount.Mul(coupon.DiscountValue).Div(decimal.NewFromInt(100)).RoundDown(2)
+	}
+	if maxDiscount := amount.Sub(minPayableAmount); discount.GreaterThan(maxDiscount) {
+		discount = maxDiscount
+	}
+	if discount.LessThanOrEqual(decimal.Zero) {
+		return nil, errors.New(common.CouponNotApplicable)
+	}
+
+	return &CouponDiscount{
+		Coupon:         &coupon,
+		Code:           coupon.Code,
+		OriginalAmount: amount,
+		DiscountAmount: discount,
+		PayableAmount:  amount.Sub(discount),
+	}, nil
+}
+
+// ApplyCoupon 在支付事务内锁定并核销优惠券，核销记录需在订单创建后通过 RecordCouponRedemption 写入
+func ApplyCoupon(tx *gorm.DB, apiKeyID uint64, linkID uint64, userID uint64, code string, amount decimal.Decimal) (*CouponDiscount, error) {
+	discount, err := ResolveCoupon(tx, apiKeyID, linkID, userID, code, amount, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Model(discount.Coupon).
+		UpdateColumns(map[string]interface{}{
+			"used_count":     gorm.Expr("used_count + 1"),
+			"total_discount": gorm.Expr("total_discount + ?", discount.DiscountAmount),
+		}).Error; err != nil {
+		return nil, err
+	}
+
+	return discount, nil
+}
+
+// ApplyToOrder 将减免结果写入订单字段，订单金额改为减免后的应付金额
+func (d *CouponDiscount) ApplyToOrder(order *model.Order) {
+	order.CouponID = d.Coupon.ID
+	order.OriginalAmount = d.OriginalAmount
+	order.DiscountAmount = d.DiscountAmount
+	order.Amount = d.PayableAmount
+}
+
+// RecordCouponRedemption 写入优惠券核销记录
+func RecordCouponRedemption(tx *gorm.DB, discount *CouponDiscount, orderID uint64, userID uint64) error {
+	return tx.Create(&model.MerchantCouponRedemption{
+		CouponID:       discount.Coupon.ID,
+		UserID:         userID,
+		OrderID:        orderID,
+		OriginalAmount: discount.OriginalAmount,
+		DiscountAmount: discount.DiscountAmount,
+	}).Error
+}
+
+// IsCouponError 判断是否为优惠券校验错误
+func IsCouponError(err error) bool {
+	switch err.Error() {
+	case common.CouponNotFound, common.CouponNotStarted, common.CouponExpired, common.CouponUsageExceeded,
+		common.CouponPerUserExceeded, common.CouponMinSpendNotMet, common.CouponNotApplicable:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,86 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/linux-do/pay/internal/db"
+	"github.com/linux-do/pay/internal/logger"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// OrderEventChannel Redis Pub/Sub 频道，用于在多个 API 实例间广播订单状态变更
+	OrderEventChannel = "payment:order:events"
+)
+
+// OrderEvent 订单状态变更事件
+type OrderEvent struct {
+	OrderID    uint64            `json:"order_id"`
+	TradeNo    string            `json:"trade_no"`
+	OutTradeNo string            `json:"out_trade_no"`
+	ClientID   string            `json:"client_id"`
+	Status     model.OrderStatus `json:"status"`
+	Amount     decimal.Decimal   `json:"amount"`
+	EventTime  time.Time         `json:"event_time"`
+}
+
+// NewOrderEvent 根据订单当前状态构造事件
+func NewOrderEvent(order *model.Order) OrderEvent {
+	return OrderEvent{
+		OrderID:    order.ID,
+		TradeNo:    strconv.FormatUint(order.ID, 10),
+		OutTradeNo: order.MerchantOrderNo,
+		ClientID:   order.ClientID,
+		Status:     order.Status,
+		Amount:     order.Amount,
+		EventTime:  time.Now(),
+	}
+}
+
+// PublishOrderEvent 发布订单状态变更事件，失败只记录日志不影响主流程
+// 应在数据库事务提交后调用，避免订阅方读到未提交的状态
+func PublishOrderEvent(ctx context.Context, order *model.Order) {
+	if order.ClientID == "" {
+		return
+	}
+
+	payload, _ := json.Marshal(NewOrderEvent(order))
+	if err := db.Redis.Publish(ctx, OrderEventChannel, payload).Err(); err != nil {
+		logger.ErrorF(ctx, "发布订单[ID:%d]状态事件失败: %v", order.ID, err)
+	}
+}
+
+// ParseOrderEvent 解析 Redis 中的订单状态事件
+func ParseOrderEvent(payload string) (OrderEvent, error) {
+	var event OrderEvent
+	err := json.Unmarshal([]byte(payload), &event)
+	return event, err
+}
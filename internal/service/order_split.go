@@ -0,0 +1,119 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package service
+
+import (
+	"errors"
+
+	"github.com/linux-do/pay/internal/common"
+	"github.com/linux-do/pay/internal/model"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SettleOrderSplits 订单支付成功后结算分账：按商户费率扣除分账份额的手续费，将实收金额转入分账收款人
+// 返回分账收款人实收金额合计，商户实收金额需扣除该部分
+func SettleOrderSplits(tx *gorm.DB, orderID uint64, feeRate decimal.Decimal) (decimal.Decimal, error) {
+	var splits []model.OrderSplit
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("order_id = ? AND status = ?", orderID, model.OrderSplitStatusPending).
+		Order("id ASC").
+		Find(&splits).Error; err != nil {
+		return decimal.Zero, err
+	}
+
+	totalNet := decimal.Zero
+	for i := range splits {
+		split := &splits[i]
+		split.FeeAmount, split.NetAmount, _ = CalculateFee(split.Amount, feeRate)
+		split.Status = model.OrderSplitStatusSettled
+
+		if err := tx.Model(&model.User{}).
+			Where("id = ?", split.RecipientUserID).
+			UpdateColumns(map[string]interface{}{
+				"available_balance": gorm.Expr("available_balance + ?", split.NetAmount),
+				"total_receive":     gorm.Expr("total_receive + ?", split.NetAmount),
+			}).Error; err != nil {
+			return decimal.Zero, err
+		}
+		if err := tx.Model(split).Select("fee_amount", "net_amount", "status").Updates(split).Error; err != nil {
+			return decimal.Zero, err
+		}
+		totalNet = totalNet.Add(split.NetAmount)
+	}
+	return totalNet, nil
+}
+
+// ReverseOrderSplits 订单退款时按退款金额占订单金额的比例扣回已结算的分账份额，refundAmount 须在更新订单已退金额前传入
+// 每个分账收款人扣回 分账份额 × 退款金额 / 订单金额，不超过尚未扣回的部分；本次退完订单剩余金额时扣回全部剩余份额，避免舍入残差
+// 结算时扣除的手续费不退还，由分账收款人承担；收款人可用余额不足以扣回时返回错误，整笔退款回滚
+// 返回本次扣回的分账份额合计，商户只需退还退款金额扣除该部分后的余额
+func ReverseOrderSplits(tx *gorm.DB, order *model.Order, refundAmount decimal.Decimal) (decimal.Decimal, error) {
+	var splits []model.OrderSplit
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("order_id = ? AND status = ?", order.ID, model.OrderSplitStatusSettled).
+		Order("id ASC").
+		Find(&splits).Error; err != nil {
+		return decimal.Zero, err
+	}
+
+	fullRefund := refundAmount.GreaterThanOrEqual(order.RefundableAmount())
+	total := decimal.Zero
+	for i := range splits {
+		split := &splits[i]
+		remaining := split.Amount.Sub(split.ReversedAmount)
+		share := remaining
+		if !fullRefund {
+			share = decimal.Min(split.Amount.Mul(refundAmount).Div(order.Amount).Round(2), remaining)
+		}
+		if share.LessThanOrEqual(decimal.Zero) {
+			continue
+		}
+
+		result := tx.Model(&model.User{}).
+			Where("id = ? AND available_balance >= ?", split.RecipientUserID, share).
+			UpdateColumns(map[string]interface{}{
+				"available_balance": gorm.Expr("available_balance - ?", share),
+				"total_receive":     gorm.Expr("total_receive - ?", share),
+			})
+		if result.Error != nil {
+			return decimal.Zero, result.Error
+		}
+		if result.RowsAffected == 0 {
+			return decimal.Zero, errors.New(common.SplitReverseInsufficient)
+		}
+
+		split.ReversedAmount = split.ReversedAmount.Add(share)
+		if split.ReversedAmount.Equal(split.Amount) {
+			split.Status = model.OrderSplitStatusRefunded
+		}
+		if err := tx.Model(split).Select("reversed_amount", "status").Updates(split).Error; err != nil {
+			return decimal.Zero, err
+		}
+		total = total.Add(share)
+	}
+	return total, nil
+}
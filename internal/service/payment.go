@@ -34,6 +34,9 @@ import (
 	"gorm.io/gorm"
 )
 
+// dailyLimitCountedStatuses 计入每日限额的订单状态，预授权冻结的金额同样占用限额
+var dailyLimitCountedStatuses = []model.OrderStatus{model.OrderStatusSuccess, model.OrderStatusAuthorized}
+
 // CheckDailyLimit 检查用户每日支付限额
 // 返回 nil 表示未超限额，返回 error 表示超限或查询失败
 func CheckDailyLimit(tx *gorm.DB, userID uint64, amount decimal.Decimal, dailyLimit *int64) error {
@@ -51,12 +54,12 @@ func CheckDailyLimit(tx *gorm.DB, userID uint64, amount decimal.Decimal, dailyLi
 	todayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	todayEnd := todayStart.Add(24 * time.Hour)
 
-	// 统计当日成功支付的订单总金额
+	// 统计当日成功支付及预授权冻结中的订单总金额
 	var todayTotalAmount decimal.Decimal
 	if err := tx.Model(&model.Order{}).
-		Where("payer_user_id = ? AND status = ? AND type = ? AND trade_time >= ? AND trade_time < ?",
+		Where("payer_user_id = ? AND status IN ? AND type = ? AND trade_time >= ? AND trade_time < ?",
 			userID,
-			model.OrderStatusSuccess,
+			dailyLimitCountedStatuses,
 			model.OrderTypePayment,
 			todayStart,
 			todayEnd).
@@ -73,6 +76,81 @@ func CheckDailyLimit(tx *gorm.DB, userID uint64, amount decimal.Decimal, dailyLi
 	return nil
 }
 
+// CheckMerchantAmountRange 检查订单金额是否在商户设置的单笔金额范围内
+func CheckMerchantAmountRange(apiKey *model.MerchantAPIKey, amount decimal.Decimal) error {
+	if apiKey.MinOrderAmount != nil && amount.LessThan(*apiKey.MinOrderAmount) {
+		return errors.New(common.OrderAmountBelowMinimum)
+	}
+	if apiKey.MaxOrderAmount != nil && amount.GreaterThan(*apiKey.MaxOrderAmount) {
+		return errors.New(common.OrderAmountAboveMaximum)
+	}
+	return nil
+}
+
+// CheckMerchantDailyReceiveLimit 检查商户应用每日收款限额
+// 返回 nil 表示未超限额，返回 error 表示超限或查询失败
+func CheckMerchantDailyReceiveLimit(tx *gorm.DB, apiKey *model.MerchantAPIKey, amount decimal.Decimal) error {
+	if apiKey.DailyReceiveLimit == nil {
+		return nil
+	}
+
+	// 使用负数锁 ID，与用户每日限额的锁区分开
+	now := time.Now()
+	datePart := int64(now.Year()*10000 + int(now.Month())*100 + now.Day())
+	lockID := -(int64(apiKey.ID)*100000000 + datePart)
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", lockID).Error; err != nil {
+		return err
+	}
+
+	return checkMerchantTodayReceived(tx, apiKey, amount, now)
+}
+
+// checkMerchantTodayReceived 统计商户应用当日已收款金额，加上本次金额后超过每日收款限额时返回错误
+func checkMerchantTodayReceived(tx *gorm.DB, apiKey *model.MerchantAPIKey, amount decimal.Decimal, now time.Time) error {
+	todayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	todayEnd := todayStart.Add(24 * time.Hour)
+
+	// 统计当日该应用成功收款及预授权冻结中的订单总金额
+	var todayTotalAmount decimal.Decimal
+	if err := tx.Model(&model.Order{}).
+		Where("client_id = ? AND status IN ? AND type = ? AND trade_time >= ? AND trade_time < ?",
+			apiKey.ClientID,
+			dailyLimitCountedStatuses,
+			model.OrderTypePayment,
+			todayStart,
+			todayEnd).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&todayTotalAmount).Error; err != nil {
+		return err
+	}
+
+	if todayTotalAmount.Add(amount).GreaterThan(*apiKey.DailyReceiveLimit) {
+		return errors.New(common.MerchantDailyReceiveLimit)
+	}
+
+	return nil
+}
+
+// CheckMerchantLimits 检查商户应用的单笔金额范围和每日收款限额
+func CheckMerchantLimits(tx *gorm.DB, apiKey *model.MerchantAPIKey, amount decimal.Decimal) error {
+	if err := CheckMerchantAmountRange(apiKey, amount); err != nil {
+		return err
+	}
+	return CheckMerchantDailyReceiveLimit(tx, apiKey, amount)
+}
+
+// PrecheckMerchantLimits 下单时预检商户应用的单笔金额范围和每日收款限额，不加锁
+// 下单到支付之间可能有其他订单完成收款，支付事务内仍需通过 CheckMerchantLimits 加锁复核
+func PrecheckMerchantLimits(tx *gorm.DB, apiKey *model.MerchantAPIKey, amount decimal.Decimal) error {
+	if err := CheckMerchantAmountRange(apiKey, amount); err != nil {
+		return err
+	}
+	if apiKey.DailyReceiveLimit == nil {
+		return nil
+	}
+	return checkMerchantTodayReceived(tx, apiKey, amount, time.Now())
+}
+
 // DeductUserBalance 扣减用户余额
 // 返回 nil 表示扣减成功，返回 error 表示余额不足或更新失败
 func DeductUserBalance(tx *gorm.DB, userID uint64, amount decimal.Decimal) error {
@@ -122,9 +200,9 @@ func GetTodayUsedAmount(db *gorm.DB, userID uint64) (decimal.Decimal, error) {
 
 	var todayTotalAmount decimal.Decimal
 	if err := db.Model(&model.Order{}).
-		Where("payer_user_id = ? AND status = ? AND type = ? AND trade_time >= ? AND trade_time < ?",
+		Where("payer_user_id = ? AND status IN ? AND type = ? AND trade_time >= ? AND trade_time < ?",
 			userID,
-			model.OrderStatusSuccess,
+			dailyLimitCountedStatuses,
 			model.OrderTypePayment,
 			todayStart,
 			todayEnd).
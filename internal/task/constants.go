@@ -29,7 +29,19 @@ const (
 	UpdateSingleUserGamificationScoreTask = "user:gamification:update_single_score_task"
 	AutoRefundExpiredDisputesTask         = "dispute:auto_refund_expired"
 	AutoRefundSingleDisputeTask           = "dispute:auto_refund_single"
-	MerchantPaymentNotifyTask             = "payment:merchant_notify" // 商户支付回调任务
+	MerchantPaymentNotifyTask             = "payment:merchant_notify"       // 商户支付回调任务
+	MerchantEventNotifyTask               = "payment:merchant_event_notify" // 商户事件回调任务
+	SubscriptionRenewDueTask              = "subscription:renew_due"
+	SubscriptionRenewSingleTask           = "subscription:renew_single"
+	PayoutBatchTask                       = "payout:process_batch" // 商户批量付款任务
+	EscrowAutoConfirmDueTask              = "escrow:auto_confirm_due"
+	EscrowAutoConfirmSingleTask           = "escrow:auto_confirm_single"
+	RedPacketRefundDueTask                = "red_packet:refund_due"
+	RedPacketRefundSingleTask             = "red_packet:refund_single"
+	ScheduledTransferDueTask              = "scheduled_transfer:run_due"
+	ScheduledTransferSingleTask           = "scheduled_transfer:run_single"
+	AuthorizationAutoVoidDueTask          = "payment:authorization_auto_void_due"
+	AuthorizationAutoVoidSingleTask       = "payment:authorization_auto_void_single"
 )
 
 const (
@@ -69,6 +69,26 @@ func StartScheduler() error {
 			return
 		}
 
+		if _, err = scheduler.Register(config.Config.Schedule.SubscriptionRenewDueTaskCron, asynq.NewTask(task.SubscriptionRenewDueTask, nil)); err != nil {
+			return
+		}
+
+		if _, err = scheduler.Register(config.Config.Schedule.EscrowAutoConfirmDueTaskCron, asynq.NewTask(task.EscrowAutoConfirmDueTask, nil)); err != nil {
+			return
+		}
+
+		if _, err = scheduler.Register(config.Config.Schedule.RedPacketRefundDueTaskCron, asynq.NewTask(task.RedPacketRefundDueTask, nil)); err != nil {
+			return
+		}
+
+		if _, err = scheduler.Register(config.Config.Schedule.ScheduledTransferDueTaskCron, asynq.NewTask(task.ScheduledTransferDueTask, nil)); err != nil {
+			return
+		}
+
+		if _, err = scheduler.Register(config.Config.Schedule.AuthorizationAutoVoidDueTaskCron, asynq.NewTask(task.AuthorizationAutoVoidDueTask, nil)); err != nil {
+			return
+		}
+
 		// 启动调度器
 		err = scheduler.Run()
 	})
@@ -29,7 +29,12 @@ import (
 
 	"github.com/hibiken/asynq"
 	"github.com/linux-do/pay/internal/apps/dispute"
+	"github.com/linux-do/pay/internal/apps/escrow"
 	"github.com/linux-do/pay/internal/apps/payment"
+	"github.com/linux-do/pay/internal/apps/payout"
+	"github.com/linux-do/pay/internal/apps/red_packet"
+	"github.com/linux-do/pay/internal/apps/scheduled_transfer"
+	"github.com/linux-do/pay/internal/apps/subscription"
 	"github.com/linux-do/pay/internal/apps/user"
 	"github.com/linux-do/pay/internal/config"
 	"github.com/linux-do/pay/internal/task"
@@ -55,6 +60,18 @@ func StartWorker() error {
 	mux.HandleFunc(task.AutoRefundExpiredDisputesTask, dispute.HandleAutoRefundExpiredDisputes)
 	mux.HandleFunc(task.AutoRefundSingleDisputeTask, dispute.HandleAutoRefundSingleDispute)
 	mux.HandleFunc(task.MerchantPaymentNotifyTask, payment.HandleMerchantPaymentNotify)
+	mux.HandleFunc(task.MerchantEventNotifyTask, payment.HandleMerchantEventNotify)
+	mux.HandleFunc(task.SubscriptionRenewDueTask, subscription.HandleSubscriptionRenewDue)
+	mux.HandleFunc(task.SubscriptionRenewSingleTask, subscription.HandleSubscriptionRenewSingle)
+	mux.HandleFunc(task.PayoutBatchTask, payout.HandlePayoutBatch)
+	mux.HandleFunc(task.EscrowAutoConfirmDueTask, escrow.HandleEscrowAutoConfirmDue)
+	mux.HandleFunc(task.EscrowAutoConfirmSingleTask, escrow.HandleEscrowAutoConfirmSingle)
+	mux.HandleFunc(task.RedPacketRefundDueTask, red_packet.HandleRedPacketRefundDue)
+	mux.HandleFunc(task.RedPacketRefundSingleTask, red_packet.HandleRedPacketRefundSingle)
+	mux.HandleFunc(task.ScheduledTransferDueTask, scheduled_transfer.HandleScheduledTransferDue)
+	mux.HandleFunc(task.ScheduledTransferSingleTask, scheduled_transfer.HandleScheduledTransferSingle)
+	mux.HandleFunc(task.AuthorizationAutoVoidDueTask, payment.HandleAuthorizationAutoVoidDue)
+	mux.HandleFunc(task.AuthorizationAutoVoidSingleTask, payment.HandleAuthorizationAutoVoidSingle)
 	// 启动服务器
 	return asynqServer.Run(mux)
 }
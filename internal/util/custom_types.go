@@ -28,19 +28,99 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
+
+	"github.com/shopspring/decimal"
 )
 
 // StringArray custom type for handling JSON arrays
 type StringArray []string
 
 func (sa *StringArray) Scan(value interface{}) error {
-	bytesValue, ok := value.([]byte)
-	if !ok {
+	switch v := value.(type) {
+	case nil:
+		*sa = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, sa)
+	case string:
+		return json.Unmarshal([]byte(v), sa)
+	default:
 		return fmt.Errorf("invalid value: %v", value)
 	}
-	return json.Unmarshal(bytesValue, sa)
 }
 
 func (sa StringArray) Value() (driver.Value, error) {
 	return json.Marshal(sa)
 }
+
+// StringMap custom type for handling JSON objects
+type StringMap map[string]string
+
+func (sm *StringMap) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*sm = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, sm)
+	case string:
+		return json.Unmarshal([]byte(v), sm)
+	default:
+		return fmt.Errorf("invalid value: %v", value)
+	}
+}
+
+func (sm StringMap) Value() (driver.Value, error) {
+	if sm == nil {
+		return nil, nil
+	}
+	return json.Marshal(sm)
+}
+
+// DecimalArray custom type for handling JSON arrays of decimals
+type DecimalArray []decimal.Decimal
+
+func (da *DecimalArray) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*da = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, da)
+	case string:
+		return json.Unmarshal([]byte(v), da)
+	default:
+		return fmt.Errorf("invalid value: %v", value)
+	}
+}
+
+func (da DecimalArray) Value() (driver.Value, error) {
+	if da == nil {
+		return nil, nil
+	}
+	return json.Marshal(da)
+}
+
+// Uint64Array custom type for handling JSON arrays of ids
+type Uint64Array []uint64
+
+func (ua *Uint64Array) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*ua = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, ua)
+	case string:
+		return json.Unmarshal([]byte(v), ua)
+	default:
+		return fmt.Errorf("invalid value: %v", value)
+	}
+}
+
+func (ua Uint64Array) Value() (driver.Value, error) {
+	if ua == nil {
+		return nil, nil
+	}
+	return json.Marshal(ua)
+}
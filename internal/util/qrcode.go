@@ -0,0 +1,104 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 linux.do
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package util
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/skip2/go-qrcode"
+)
+
+const (
+	QRCodeFormatPNG = "png"
+	QRCodeFormatSVG = "svg"
+
+	// QRCodeDefaultSize 二维码默认边长（像素）
+	QRCodeDefaultSize = 256
+)
+
+// QRCodeRequest 二维码渲染参数
+type QRCodeRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=png svg"`
+	Size   int    `form:"size" binding:"omitempty,min=64,max=1024"`
+}
+
+// QRCodePNG 生成 PNG 格式二维码
+func QRCodePNG(content string, size int) ([]byte, error) {
+	return qrcode.Encode(content, qrcode.Medium, size)
+}
+
+// QRCodeSVG 生成 SVG 格式二维码，每个模块绘制为一个单位方格
+func QRCodeSVG(content string, size int) (string, error) {
+	qr, err := qrcode.New(content, qrcode.Medium)
+	if err != nil {
+		return "", err
+	}
+
+	bitmap := qr.Bitmap()
+	modules := len(bitmap)
+
+	var path strings.Builder
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&path, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+
+	return fmt.Sprintf(
+		`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+
+			`<rect width="100%%" height="100%%" fill="#ffffff"/><path fill="#000000" d="%s"/></svg>`,
+		size, size, modules, modules, path.String(),
+	), nil
+}
+
+// RenderQRCode 按请求参数输出二维码图片
+func RenderQRCode(c *gin.Context, content string, req *QRCodeRequest) {
+	size := req.Size
+	if size == 0 {
+		size = QRCodeDefaultSize
+	}
+
+	if req.Format == QRCodeFormatSVG {
+		svg, err := QRCodeSVG(content, size)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, Err(err.Error()))
+			return
+		}
+		c.Data(http.StatusOK, "image/svg+xml", []byte(svg))
+		return
+	}
+
+	png, err := QRCodePNG(content, size)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Err(err.Error()))
+		return
+	}
+	c.Data(http.StatusOK, "image/png", png)
+}